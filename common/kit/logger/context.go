@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestIDKey struct{}
+
+type callerIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request id
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id of ctx or ""
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextWithCallerID returns a copy of ctx carrying the user id of the caller
+func ContextWithCallerID(ctx context.Context, callerID string) context.Context {
+	return context.WithValue(ctx, callerIDKey{}, callerID)
+}
+
+// CallerIDFromContext returns the caller id of ctx or ""
+func CallerIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(callerIDKey{}).(string)
+	return id
+}
+
+// contextAttrs returns request_id and caller_id found in ctx. The caller is
+// not logged as user_id, which usecases use for the user they act on.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if id := RequestIDFromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if id := CallerIDFromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("caller_id", id))
+	}
+	return attrs
+}
+
+// contextHandler adds the request id and caller of the record context,
+// so log lines of one request can be found together
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(contextAttrs(ctx)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -13,11 +14,21 @@ type LoggerInterface interface {
 	Error(msg string, params map[string]any)
 }
 
+// ContextLoggerInterface adds methods taking the request context, their
+// lines carry request_id, caller_id, trace_id and span_id found in ctx
+type ContextLoggerInterface interface {
+	LoggerInterface
+	DebugCtx(ctx context.Context, msg string, params map[string]any)
+	InfoCtx(ctx context.Context, msg string, params map[string]any)
+	WarnCtx(ctx context.Context, msg string, params map[string]any)
+	ErrorCtx(ctx context.Context, msg string, params map[string]any)
+}
+
 type Logger struct {
 	logger *slog.Logger
 }
 
-var _ LoggerInterface = (*Logger)(nil)
+var _ ContextLoggerInterface = (*Logger)(nil)
 
 func NewLogger(levelStr string, params map[string]any) *Logger {
 	var level slog.Level
@@ -37,7 +48,7 @@ func NewLogger(levelStr string, params map[string]any) *Logger {
 		Level:     level,
 		AddSource: true,
 	})
-	l := slog.New(handler)
+	l := slog.New(contextHandler{traceHandler{handler}})
 	l = l.With(paramsToAny(params)...)
 
 	return &Logger{
@@ -61,6 +72,38 @@ func (l *Logger) Error(msg string, params map[string]any) {
 	l.logger.Error(msg, paramsToAny(params)...)
 }
 
+func (l *Logger) DebugCtx(ctx context.Context, msg string, params map[string]any) {
+	l.logger.DebugContext(ctx, msg, paramsToAny(params)...)
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, msg string, params map[string]any) {
+	l.logger.InfoContext(ctx, msg, paramsToAny(params)...)
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, msg string, params map[string]any) {
+	l.logger.WarnContext(ctx, msg, paramsToAny(params)...)
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, params map[string]any) {
+	l.logger.ErrorContext(ctx, msg, paramsToAny(params)...)
+}
+
+// WithContext returns a logger binding the request scoped fields of ctx
+// for code that only has the context-free methods
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	attrs := append(contextAttrs(ctx), traceAttrs(ctx)...)
+	if len(attrs) == 0 {
+		return l
+	}
+	args := make([]any, len(attrs))
+	for i, a := range attrs {
+		args[i] = a
+	}
+	return &Logger{
+		logger: l.logger.With(args...),
+	}
+}
+
 func (l *Logger) With(params map[string]any) *Logger {
 	return &Logger{
 		logger: l.logger.With(paramsToAny(params)...),
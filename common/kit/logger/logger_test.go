@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logLine logs one record through the handlers of NewLogger and returns it raw and decoded
+func logLine(t *testing.T, ctx context.Context, args ...any) (string, map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	l := slog.New(contextHandler{traceHandler{slog.NewJSONHandler(&buf, nil)}})
+	l.InfoContext(ctx, "test", args...)
+
+	line := buf.String()
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		t.Fatalf("decode log line %q: %v", line, err)
+	}
+	return line, fields
+}
+
+func TestHandler_ContextFields(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithCallerID(ctx, "admin1")
+	ctx = trace.ContextWithSpanContext(ctx, sc)
+
+	// the usecase logs the user it acts on as user_id
+	line, fields := logLine(t, ctx, "user_id", "u2")
+
+	want := map[string]string{
+		"request_id": "req-1",
+		"caller_id":  "admin1",
+		"user_id":    "u2",
+		"trace_id":   "0af7651916cd43dd8448eb211c80319c",
+		"span_id":    "b7ad6b7169203331",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("%s: expected %q, got %v", key, value, fields[key])
+		}
+	}
+	// a decoded map hides duplicate keys, the raw line does not
+	for key := range want {
+		if n := strings.Count(line, `"`+key+`":`); n != 1 {
+			t.Errorf("%s: expected the key once, got %d times in %s", key, n, line)
+		}
+	}
+}
+
+func TestHandler_EmptyContext(t *testing.T) {
+	_, fields := logLine(t, context.Background())
+
+	for _, key := range []string{"request_id", "caller_id", "trace_id", "span_id"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("expected no %s without a context value, got %v", key, fields[key])
+		}
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{name: "client id", header: "3f2a-retry-1", wantKept: true},
+		{name: "missing", header: ""},
+		{name: "too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+		{name: "not printable", header: "id with spaces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if tt.wantKept && got != tt.header {
+				t.Fatalf("expected the client id %q, got %q", tt.header, got)
+			}
+			if !tt.wantKept && (got == tt.header || !validRequestID(got) || len(got) != 32) {
+				t.Fatalf("expected a generated id, got %q", got)
+			}
+			if echoed := rec.Header().Get(RequestIDHeader); echoed != got {
+				t.Fatalf("expected %q echoed in the response, got %q", got, echoed)
+			}
+		})
+	}
+}
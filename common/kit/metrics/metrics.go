@@ -10,15 +10,74 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// UnmatchedRoute labels requests no route pattern matched, so that
+// unknown paths of scanners don't create new series
+const UnmatchedRoute = "unmatched"
+
+// DefaultSizeBuckets are the request and response size buckets in bytes
+var DefaultSizeBuckets = prometheus.ExponentialBuckets(100, 10, 6)
+
 var (
 	httpRequestsTotal   *prometheus.CounterVec
 	httpRequestDuration *prometheus.HistogramVec
+	httpRequestSize     *prometheus.HistogramVec
+	httpResponseSize    *prometheus.HistogramVec
+	httpInFlight        *prometheus.GaugeVec
 	businessOpsTotal    *prometheus.CounterVec
 	metricsInitOnce     sync.Once
 )
 
-func InitMetrics() {
+type options struct {
+	durationBuckets []float64
+	sizeBuckets     []float64
+	route           func(r *http.Request) string
+}
+
+type Option func(*options)
+
+// WithDurationBuckets sets the buckets of http_request_duration_seconds
+func WithDurationBuckets(buckets []float64) Option {
+	return func(o *options) {
+		if len(buckets) > 0 {
+			o.durationBuckets = buckets
+		}
+	}
+}
+
+// WithSizeBuckets sets the buckets of the request and response size histograms
+func WithSizeBuckets(buckets []float64) Option {
+	return func(o *options) {
+		if len(buckets) > 0 {
+			o.sizeBuckets = buckets
+		}
+	}
+}
+
+// WithRoute sets the func returning the route pattern of a request, an
+// empty pattern is reported as UnmatchedRoute
+func WithRoute(route func(r *http.Request) string) Option {
+	return func(o *options) {
+		o.route = route
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		durationBuckets: prometheus.DefBuckets,
+		sizeBuckets:     DefaultSizeBuckets,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// InitMetrics registers the metrics once, bucket options of later calls
+// are ignored
+func InitMetrics(opts ...Option) {
 	metricsInitOnce.Do(func() {
+		o := newOptions(opts)
+
 		httpRequestsTotal = promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "http_requests_total",
@@ -31,11 +90,37 @@ func InitMetrics() {
 			prometheus.HistogramOpts{
 				Name:    "http_request_duration_seconds",
 				Help:    "Duration of HTTP requests in seconds.",
-				Buckets: prometheus.DefBuckets,
+				Buckets: o.durationBuckets,
+			},
+			[]string{"service", "method", "path", "status"},
+		)
+
+		httpRequestSize = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_request_size_bytes",
+				Help:    "Size of HTTP request bodies in bytes.",
+				Buckets: o.sizeBuckets,
 			},
 			[]string{"service", "method", "path", "status"},
 		)
 
+		httpResponseSize = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_response_size_bytes",
+				Help:    "Size of HTTP response bodies in bytes.",
+				Buckets: o.sizeBuckets,
+			},
+			[]string{"service", "method", "path", "status"},
+		)
+
+		httpInFlight = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "http_requests_in_flight",
+				Help: "Number of HTTP requests being served.",
+			},
+			[]string{"service"},
+		)
+
 		businessOpsTotal = promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "business_operations_total",
@@ -49,6 +134,7 @@ func InitMetrics() {
 type statusRecorder struct {
 	http.ResponseWriter
 	status int
+	size   int
 }
 
 func (r *statusRecorder) WriteHeader(code int) {
@@ -56,37 +142,86 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.ResponseWriter.WriteHeader(code)
 }
 
-func HTTPMiddleware(serviceName string, next http.Handler) http.Handler {
-	InitMetrics()
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach Flush and deadlines of the
+// underlying writer, which streaming handlers need
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// ServeMuxRoute returns the pattern of mux serving the request
+func ServeMuxRoute(mux *http.ServeMux) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		return pattern
+	}
+}
+
+// HTTPMiddleware labels requests with the route pattern, not the raw path.
+// When next is a *http.ServeMux its patterns are used unless WithRoute is
+// given, otherwise requests are reported as UnmatchedRoute.
+func HTTPMiddleware(serviceName string, next http.Handler, opts ...Option) http.Handler {
+	InitMetrics(opts...)
+
+	o := newOptions(opts)
+	if o.route == nil {
+		if mux, ok := next.(*http.ServeMux); ok {
+			o.route = ServeMuxRoute(mux)
+		} else {
+			o.route = func(*http.Request) string { return "" }
+		}
+	}
+	inFlight := httpInFlight.WithLabelValues(serviceName)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		route := o.route(r)
+		if route == "" {
+			route = UnmatchedRoute
+		}
 
 		rec := &statusRecorder{
 			ResponseWriter: w,
 			status:         http.StatusOK,
 		}
 
+		inFlight.Inc()
+		defer inFlight.Dec()
+
 		next.ServeHTTP(rec, r)
 
 		duration := time.Since(start)
 
-		if httpRequestsTotal == nil || httpRequestDuration == nil {
-			return
-		}
-
 		labels := prometheus.Labels{
 			"service": serviceName,
-			"method":  r.Method,
-			"path":    r.URL.Path,
+			"method":  methodLabel(r.Method),
+			"path":    route,
 			"status":  strconv.Itoa(rec.status),
 		}
 
 		httpRequestsTotal.With(labels).Inc()
 		httpRequestDuration.With(labels).Observe(duration.Seconds())
+		httpRequestSize.With(labels).Observe(float64(max(r.ContentLength, 0)))
+		httpResponseSize.With(labels).Observe(float64(rec.size))
 	})
 }
 
+// methodLabel keeps standard methods, other ones are reported as OTHER
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
 func IncBusinessOperation(service, operation, result string) {
 	if businessOpsTotal == nil {
 		return
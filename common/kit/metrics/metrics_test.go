@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// requests counts the requests of the service with the route and status
+func requests(service, method, route, status string) float64 {
+	return testutil.ToFloat64(httpRequestsTotal.WithLabelValues(service, method, route, status))
+}
+
+func TestHTTPMiddleware_ServeMuxRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /team/get", func(w http.ResponseWriter, r *http.Request) {})
+	h := HTTPMiddleware("test-mux", mux)
+
+	for _, target := range []string{"/team/get?team_name=x", "/team/get?team_name=y", "/wp-login.php", "/team/get/x"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	// the query string is not part of the label
+	if got := requests("test-mux", http.MethodGet, "GET /team/get", "200"); got != 2 {
+		t.Fatalf("expected 2 requests labeled by the pattern, got %v", got)
+	}
+	if got := requests("test-mux", http.MethodGet, UnmatchedRoute, "404"); got != 2 {
+		t.Fatalf("expected 2 unknown paths in the %s bucket, got %v", UnmatchedRoute, got)
+	}
+	if got := requests("test-mux", http.MethodGet, "/wp-login.php", "404"); got != 0 {
+		t.Fatalf("expected no series for the raw path, got %v", got)
+	}
+}
+
+func TestHTTPMiddleware_WithRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /pullRequest/create", func(w http.ResponseWriter, r *http.Request) {})
+	// the mux is wrapped, so its patterns are passed explicitly
+	wrapped := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { mux.ServeHTTP(w, r) })
+
+	h := HTTPMiddleware("test-route", wrapped, WithRoute(ServeMuxRoute(mux)))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/unknown", nil))
+
+	if got := requests("test-route", http.MethodPost, "POST /pullRequest/create", "200"); got != 1 {
+		t.Fatalf("expected the request labeled by the pattern, got %v", got)
+	}
+	if got := requests("test-route", http.MethodPost, UnmatchedRoute, "404"); got != 1 {
+		t.Fatalf("expected the unknown path in the %s bucket, got %v", UnmatchedRoute, got)
+	}
+
+	// without WithRoute a wrapped handler has no patterns
+	plain := HTTPMiddleware("test-plain", wrapped)
+	plain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil))
+	if got := requests("test-plain", http.MethodPost, UnmatchedRoute, "200"); got != 1 {
+		t.Fatalf("expected the request in the %s bucket, got %v", UnmatchedRoute, got)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var rosterContentTypes = map[string]string{
+	RosterYAML: "application/yaml",
+	RosterCSV:  "text/csv",
+}
+
+// ImportRoster upserts teams and users from a YAML or CSV roster.
+// With dryRun the service only reports the changes.
+func (c *Client) ImportRoster(ctx context.Context, format string, roster []byte, dryRun bool) (*RosterImportResult, error) {
+	var resp RosterImportResult
+	err := c.do(ctx, call{
+		method: http.MethodPost,
+		path:   "/admin/import",
+		query: url.Values{
+			"format":  {format},
+			"dry_run": {strconv.FormatBool(dryRun)},
+		},
+		body:        roster,
+		contentType: rosterContentTypes[format],
+		idempotent:  true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExportRoster returns all teams and members in a format ImportRoster accepts
+func (c *Client) ExportRoster(ctx context.Context, format string) ([]byte, error) {
+	var roster []byte
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/admin/export",
+		query:      url.Values{"format": {format}},
+		accept:     rosterContentTypes[format],
+		idempotent: true,
+	}, &roster)
+	if err != nil {
+		return nil, err
+	}
+	return roster, nil
+}
+
+type restoreSnapshotResponseJSON struct {
+	Restored RestoredSnapshot `json:"restored"`
+}
+
+// ExportSnapshot returns a JSON snapshot of teams, users, memberships,
+// pull requests and reviewer assignments
+func (c *Client) ExportSnapshot(ctx context.Context) ([]byte, error) {
+	var snapshot []byte
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/admin/snapshot",
+		idempotent: true,
+	}, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot loads a snapshot from ExportSnapshot into an empty database,
+// ErrDatabaseNotEmpty otherwise
+func (c *Client) RestoreSnapshot(ctx context.Context, snapshot []byte) (*RestoredSnapshot, error) {
+	var resp restoreSnapshotResponseJSON
+	err := c.do(ctx, call{
+		method:      http.MethodPost,
+		path:        "/admin/restore",
+		body:        snapshot,
+		contentType: "application/json",
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Restored, nil
+}
@@ -0,0 +1,33 @@
+package client
+
+import "net/http"
+
+// Authenticator adds credentials to an outgoing request
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthFunc adapts a function to the Authenticator interface
+type AuthFunc func(req *http.Request) error
+
+func (f AuthFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken sends "Authorization: Bearer <token>"
+func BearerToken(token string) Authenticator {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// AdminToken authenticates as an admin acting as userId
+func AdminToken(userId string) Authenticator {
+	return BearerToken("admin:" + userId)
+}
+
+// UserToken authenticates as a regular user
+func UserToken(userId string) Authenticator {
+	return BearerToken("user:" + userId)
+}
@@ -0,0 +1,241 @@
+// Package client is the Go SDK for the pr-manager-service HTTP API.
+//
+//	c := client.New("http://localhost:8080", client.WithAuth(client.AdminToken("u1")))
+//	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequestRequest{...})
+//	if errors.Is(err, client.ErrPullRequestExists) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = 200 * time.Millisecond
+	defaultUserAgent    = "pr-manager-go-client"
+)
+
+// Client calls the pr-manager-service HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	auth       Authenticator
+	userAgent  string
+
+	// retries apply to idempotent calls only
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default http.Client with a 10s timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuth sets how requests are authenticated
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithRetry sets the number of attempts for idempotent calls and the base
+// delay between them, doubled after every attempt. maxAttempts 1 disables retries.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		userAgent:    defaultUserAgent,
+		maxAttempts:  defaultMaxAttempts,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxAttempts < 1 {
+		c.maxAttempts = 1
+	}
+	return c
+}
+
+// call describes a single API request
+type call struct {
+	method     string
+	path       string
+	query      url.Values
+	body       any
+	idempotent bool
+
+	// contentType is set for raw []byte bodies, others are sent as JSON
+	contentType string
+	// accept overrides the default application/json
+	accept string
+	// ifMatch is sent as the If-Match header
+	ifMatch string
+	// etag receives the ETag header of a successful response
+	etag *string
+	// idempotencyKey is sent as the Idempotency-Key header,
+	// the service replays the first response, so the call can be retried
+	idempotencyKey string
+}
+
+// do sends the call, retrying idempotent ones on network errors and
+// 502, 503, 504 responses, and decodes a 2xx response into out
+func (c *Client) do(ctx context.Context, cl call, out any) error {
+	var body []byte
+	if raw, ok := cl.body.([]byte); ok && cl.contentType != "" {
+		body = raw
+	} else if cl.body != nil {
+		var err error
+		body, err = json.Marshal(cl.body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	attempts := 1
+	if cl.idempotent || cl.idempotencyKey != "" {
+		attempts = c.maxAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := c.retryBackoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, err := c.send(ctx, cl, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		err = decodeResponse(resp, out)
+		if retryableStatus(resp.StatusCode) {
+			lastErr = err
+			continue
+		}
+		if err == nil && cl.etag != nil {
+			*cl.etag = resp.Header.Get("ETag")
+		}
+		return err
+	}
+
+	return lastErr
+}
+
+func (c *Client) send(ctx context.Context, cl call, body []byte) (*http.Response, error) {
+	u := c.baseURL + cl.path
+	if len(cl.query) > 0 {
+		u += "?" + cl.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cl.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		contentType := cl.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	accept := cl.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", c.userAgent)
+	if cl.ifMatch != "" {
+		req.Header.Set("If-Match", cl.ifMatch)
+	}
+	if cl.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", cl.idempotencyKey)
+	}
+
+	if c.auth != nil {
+		if err := c.auth.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("authenticate request: %w", err)
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	// raw responses like roster exports
+	if raw, ok := out.(*[]byte); ok {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+		*raw = data
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable ||
+		status == http.StatusGatewayTimeout
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{WithRetry(3, time.Millisecond)}, opts...)
+	return New(srv.URL, opts...)
+}
+
+func TestClient_AuthAndDecode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/getReview" || r.URL.Query().Get("user_id") != "u2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer user:u2" {
+			t.Errorf("unexpected Authorization %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"user_id":"u2","pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}]}`)
+	}, WithAuth(UserToken("u2")))
+
+	reviews, err := c.GetUserReviews(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].Status != StatusOpen {
+		t.Fatalf("unexpected reviews: %+v", reviews)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = fmt.Fprint(w, `{"error":{"code":"PR_MERGED","message":"cannot reassign on merged PR"}}`)
+	})
+
+	_, err := c.ReassignReviewer(context.Background(), "pr-1", "u2")
+	if !errors.Is(err, ErrPullRequestMerged) {
+		t.Fatalf("expected ErrPullRequestMerged, got %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Fatalf("error must not match other codes")
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Message != "cannot reassign on merged PR" {
+		t.Fatalf("unexpected error details: %+v", apiErr)
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = fmt.Fprint(w, `{"error":{"code":"RATE_LIMITED","message":"rate limit of /users/getReview exceeded, retry after 7 seconds"}}`)
+	})
+
+	_, err := c.GetUserReviews(context.Background(), "u2")
+	var apiErr *Error
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second {
+		t.Fatalf("expected ErrRateLimited with RetryAfter 7s, got %v", err)
+	}
+	// waiting is up to the caller, retries would only drain the bucket
+	if calls.Load() != 1 {
+		t.Fatalf("expected no retries, got %d calls", calls.Load())
+	}
+}
+
+func TestClient_RetriesIdempotentCallsOnly(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		// the body must be sent again on every attempt
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && len(body) == 0 {
+			t.Errorf("attempt %d has empty body", n)
+		}
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"pr":{"pull_request_id":"pr-1","status":"MERGED"}}`)
+	})
+
+	pr, err := c.MergePullRequest(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Status != StatusMerged || calls.Load() != 3 {
+		t.Fatalf("expected success on 3rd attempt, got %+v after %d calls", pr, calls.Load())
+	}
+
+	calls.Store(0)
+	_, err = c.CreatePullRequest(context.Background(), CreatePullRequestRequest{PullRequestId: "pr-2"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("non-idempotent call was retried %d times", calls.Load())
+	}
+}
+
+func TestClient_IdempotencyKeyEnablesRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if got := r.Header.Get("Idempotency-Key"); got != "key-1" {
+			t.Errorf("unexpected Idempotency-Key %q", got)
+		}
+		if n < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"pr":{"pull_request_id":"pr-1","status":"OPEN"}}`)
+	})
+
+	pr, err := c.CreatePullRequest(context.Background(), CreatePullRequestRequest{PullRequestId: "pr-1"}, IdempotencyKey("key-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.PullRequestId != "pr-1" || calls.Load() != 2 {
+		t.Fatalf("expected success on 2nd attempt, got %+v after %d calls", pr, calls.Load())
+	}
+}
+
+func TestClient_IfMatch(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("If-Match") == "" {
+			w.Header().Set("ETag", `"2"`)
+			_, _ = fmt.Fprint(w, `{"pr":{"pull_request_id":"pr-1","status":"MERGED"}}`)
+			return
+		}
+		if got := r.Header.Get("If-Match"); got != `"1"` {
+			t.Errorf("unexpected If-Match %q", got)
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+		_, _ = fmt.Fprint(w, `{"error":{"code":"STALE_VERSION","message":"pull request was changed"}}`)
+	})
+
+	pr, err := c.MergePullRequest(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.ETag != `"2"` {
+		t.Fatalf("expected ETag \"2\", got %q", pr.ETag)
+	}
+
+	calls.Store(0)
+	_, err = c.MergePullRequest(context.Background(), "pr-1", IfMatch(`"1"`))
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("expected ErrStaleVersion, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("conditional call was sent %d times", calls.Load())
+	}
+}
+
+func TestClient_StreamUserReviews(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, ": ping\n\n")
+		_, _ = fmt.Fprint(w, "event: assign\ndata: {\"pull_request_id\":\"pr-1\",\"author_id\":\"u1\",\"occurred_at\":\"2025-10-24T12:34:56Z\"}\n\n")
+	}, WithAuth(UserToken("u2")))
+
+	stream, err := c.StreamUserReviews(context.Background(), "")
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	if ev.Kind != ReviewEventAssign || ev.PullRequestId != "pr-1" || ev.OccurredAt.IsZero() {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected io.EOF after the server closed the stream, got %v", err)
+	}
+}
+
+func TestClient_Roster(t *testing.T) {
+	const roster = "team_name,user_id,username,is_active\nbackend,u1,Alice,true\n"
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/export":
+			if r.Header.Get("Accept") != "text/csv" {
+				t.Errorf("unexpected Accept %q", r.Header.Get("Accept"))
+			}
+			w.Header().Set("Content-Type", "text/csv")
+			_, _ = fmt.Fprint(w, roster)
+		case "/admin/import":
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "text/csv" || string(body) != roster || r.URL.Query().Get("dry_run") != "true" {
+				t.Errorf("unexpected import request %s %q", r.URL, body)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"dry_run":true,"teams_created":["backend"],"users_created":[],"users_updated":[],"memberships_added":[]}`)
+		}
+	})
+
+	exported, err := c.ExportRoster(context.Background(), RosterCSV)
+	if err != nil || string(exported) != roster {
+		t.Fatalf("unexpected export %q, err %v", exported, err)
+	}
+
+	result, err := c.ImportRoster(context.Background(), RosterCSV, exported, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun || len(result.TeamsCreated) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorCode is the error.code value of an API error response
+type ErrorCode string
+
+const (
+	CodeTeamExists    ErrorCode = "TEAM_EXISTS"
+	CodePrExists      ErrorCode = "PR_EXISTS"
+	CodePrMerged      ErrorCode = "PR_MERGED"
+	CodeNotAssigned   ErrorCode = "NOT_ASSIGNED"
+	CodeNoCandidate   ErrorCode = "NO_CANDIDATE"
+	CodeNotFound      ErrorCode = "NOT_FOUND"
+	CodeValidation    ErrorCode = "VALIDATION"
+	CodeInternal      ErrorCode = "INTERNAL_ERROR"
+	CodeNotConfigured ErrorCode = "NOT_CONFIGURED"
+	CodeNotEmpty      ErrorCode = "DATABASE_NOT_EMPTY"
+	CodeStaleVersion  ErrorCode = "STALE_VERSION"
+	CodeKeyReused     ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	CodeInProgress    ErrorCode = "IDEMPOTENCY_IN_PROGRESS"
+	CodeRateLimited   ErrorCode = "RATE_LIMITED"
+)
+
+// Error is a non-2xx API response
+type Error struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	// RetryAfter is the Retry-After header of a RATE_LIMITED response
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("pr-manager: http %d", e.StatusCode)
+	}
+	return fmt.Sprintf("pr-manager: %s: %s", e.Code, e.Message)
+}
+
+// Is matches errors by code, so errors.Is(err, client.ErrNotFound) works
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code != "" && t.Code == e.Code
+}
+
+// Sentinels for errors.Is
+var (
+	ErrTeamExists            = &Error{Code: CodeTeamExists}
+	ErrPullRequestExists     = &Error{Code: CodePrExists}
+	ErrPullRequestMerged     = &Error{Code: CodePrMerged}
+	ErrNotAssigned           = &Error{Code: CodeNotAssigned}
+	ErrNoCandidate           = &Error{Code: CodeNoCandidate}
+	ErrNotFound              = &Error{Code: CodeNotFound}
+	ErrValidation            = &Error{Code: CodeValidation}
+	ErrInternal              = &Error{Code: CodeInternal}
+	ErrNotConfigured         = &Error{Code: CodeNotConfigured}
+	ErrDatabaseNotEmpty      = &Error{Code: CodeNotEmpty}
+	ErrStaleVersion          = &Error{Code: CodeStaleVersion}
+	ErrIdempotencyKeyReused  = &Error{Code: CodeKeyReused}
+	ErrIdempotencyInProgress = &Error{Code: CodeInProgress}
+	ErrRateLimited           = &Error{Code: CodeRateLimited}
+)
+
+type errorResponseJSON struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	var body errorResponseJSON
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := json.Unmarshal(data, &body); err == nil {
+		apiErr.Code = ErrorCode(body.Error.Code)
+		apiErr.Message = body.Error.Message
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
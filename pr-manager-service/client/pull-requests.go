@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type pullRequestResponseJSON struct {
+	PR PullRequest `json:"pr"`
+}
+
+// PullRequestOption configures a pull request change
+type PullRequestOption func(*call)
+
+// IfMatch makes the change fail with ErrStaleVersion unless the pull request
+// still has the version of etag, taken from PullRequest.ETag
+func IfMatch(etag string) PullRequestOption {
+	return func(cl *call) {
+		cl.ifMatch = etag
+		// a retry after a lost response would see the new version
+		cl.idempotent = false
+	}
+}
+
+// IdempotencyKey makes the service run the change once per key and return
+// the first response to repeated calls, which are then retried like
+// idempotent ones. Use a new random key, e.g. a UUID, per change.
+func IdempotencyKey(key string) PullRequestOption {
+	return func(cl *call) {
+		cl.idempotencyKey = key
+	}
+}
+
+// CreatePullRequest creates a pull request and assigns up to two reviewers
+func (c *Client) CreatePullRequest(ctx context.Context, req CreatePullRequestRequest, opts ...PullRequestOption) (*PullRequest, error) {
+	var resp pullRequestResponseJSON
+	cl := call{
+		method: http.MethodPost,
+		path:   "/pullRequest/create",
+		body:   req,
+		etag:   &resp.PR.ETag,
+	}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+	if err := c.do(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.PR, nil
+}
+
+// MergePullRequest marks the pull request as merged, repeated calls are safe
+func (c *Client) MergePullRequest(ctx context.Context, pullRequestId string, opts ...PullRequestOption) (*PullRequest, error) {
+	var resp pullRequestResponseJSON
+	cl := call{
+		method:     http.MethodPost,
+		path:       "/pullRequest/merge",
+		body:       map[string]string{"pull_request_id": pullRequestId},
+		idempotent: true,
+		etag:       &resp.PR.ETag,
+	}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+	if err := c.do(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.PR, nil
+}
+
+// ReassignReviewer replaces oldUserId with another active member of their team
+func (c *Client) ReassignReviewer(ctx context.Context, pullRequestId, oldUserId string, opts ...PullRequestOption) (*ReassignResult, error) {
+	var resp ReassignResult
+	cl := call{
+		method: http.MethodPost,
+		path:   "/pullRequest/reassign",
+		body: map[string]string{
+			"pull_request_id": pullRequestId,
+			"old_user_id":     oldUserId,
+		},
+		etag: &resp.PR.ETag,
+	}
+	for _, opt := range opts {
+		opt(&cl)
+	}
+	if err := c.do(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
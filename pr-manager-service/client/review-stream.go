@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ReviewStream reads live events of a user's review queue
+type ReviewStream struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+// StreamUserReviews opens the Server-Sent Events stream of the user's review
+// queue. An empty userId streams the queue of the authenticated user, only
+// admins may stream other users. The stream ends when ctx is cancelled or
+// Close is called. The client timeout does not apply to the stream.
+func (c *Client) StreamUserReviews(ctx context.Context, userId string) (*ReviewStream, error) {
+	cl := call{
+		method: http.MethodGet,
+		path:   "/users/reviewStream",
+	}
+	if userId != "" {
+		cl.query = url.Values{"user_id": {userId}}
+	}
+
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	sc := *c
+	sc.httpClient = &streamClient
+
+	resp, err := sc.send(ctx, cl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeResponse(resp, nil)
+	}
+
+	return &ReviewStream{
+		resp:   resp,
+		reader: bufio.NewReader(resp.Body),
+	}, nil
+}
+
+// Recv blocks until the next event, it returns io.EOF when the server closes the stream
+func (s *ReviewStream) Recv() (*ReviewEvent, error) {
+	var kind, data string
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// end of an event, comments and heartbeats have no data
+			if data == "" {
+				kind = ""
+				continue
+			}
+			ev := &ReviewEvent{Kind: kind}
+			if err := json.Unmarshal([]byte(data), ev); err != nil {
+				return nil, fmt.Errorf("decode review event: %w", err)
+			}
+			return ev, nil
+		case strings.HasPrefix(line, ":"):
+			// comment
+		case strings.HasPrefix(line, "event:"):
+			kind = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+func (s *ReviewStream) Close() error {
+	return s.resp.Body.Close()
+}
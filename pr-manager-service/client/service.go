@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type healthResponseJSON struct {
+	Status string `json:"status"`
+}
+
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	var resp Stats
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/stats",
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Health returns the status reported by /health, "ok" when healthy
+func (c *Client) Health(ctx context.Context) (string, error) {
+	var resp healthResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/health",
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Status, nil
+}
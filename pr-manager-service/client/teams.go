@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type teamResponseJSON struct {
+	Team Team `json:"team"`
+}
+
+// CreateTeam creates a team and creates or updates its members
+func (c *Client) CreateTeam(ctx context.Context, team Team) (*Team, error) {
+	var resp teamResponseJSON
+	err := c.do(ctx, call{
+		method: http.MethodPost,
+		path:   "/team/add",
+		body:   team,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Team, nil
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var resp teamResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/team/get",
+		query:      url.Values{"team_name": {teamName}},
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Team, nil
+}
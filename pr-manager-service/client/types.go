@@ -0,0 +1,168 @@
+package client
+
+import "time"
+
+// Pull request statuses
+const (
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+)
+
+// Webhook event types
+const (
+	EventReviewerAssigned  = "reviewer.assigned"
+	EventReviewerReplaced  = "reviewer.replaced"
+	EventPullRequestMerged = "pull_request.merged"
+)
+
+// Git providers and chat providers
+const (
+	ProviderGitHub         = "github"
+	ProviderGitLab         = "gitlab"
+	ChatProviderSlack      = "slack"
+	ChatProviderMattermost = "mattermost"
+)
+
+// Kinds of review stream events
+const (
+	ReviewEventAssign   = "assign"
+	ReviewEventUnassign = "unassign"
+	ReviewEventMerge    = "merge"
+)
+
+type TeamMember struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type Team struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+}
+
+type User struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type PullRequest struct {
+	PullRequestId     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorId          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+
+	// ETag identifies the version of the pull request, pass it to IfMatch
+	// to fail the next change with ErrStaleVersion if someone else changed it
+	ETag string `json:"-"`
+}
+
+type PullRequestShort struct {
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorId        string `json:"author_id"`
+	Status          string `json:"status"`
+}
+
+type UserReviews struct {
+	UserId       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+}
+
+type CreatePullRequestRequest struct {
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorId        string `json:"author_id"`
+}
+
+type ReassignResult struct {
+	PR         PullRequest `json:"pr"`
+	ReplacedBy string      `json:"replaced_by"`
+}
+
+type CreateWebhookRequest struct {
+	Url    string `json:"url"`
+	Secret string `json:"secret"`
+	// Empty means all event types
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+type WebhookSubscription struct {
+	Id         int64    `json:"id"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+}
+
+type Identity struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserId   string `json:"user_id"`
+}
+
+type ChatHandle struct {
+	UserId   string `json:"user_id"`
+	Provider string `json:"provider"`
+	Handle   string `json:"handle"`
+}
+
+type EmailSubscription struct {
+	UserId        string `json:"user_id"`
+	Email         string `json:"email"`
+	DigestEnabled bool   `json:"digest_enabled"`
+}
+
+// Roster formats of ImportRoster and ExportRoster
+const (
+	RosterYAML = "yaml"
+	RosterCSV  = "csv"
+)
+
+type RosterUserChange struct {
+	UserId      string `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	Username    string `json:"username"`
+	OldIsActive bool   `json:"old_is_active"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type RosterMembership struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// RosterImportResult lists the changes an import made, or would make on a dry run
+type RosterImportResult struct {
+	DryRun           bool               `json:"dry_run"`
+	TeamsCreated     []string           `json:"teams_created"`
+	UsersCreated     []TeamMember       `json:"users_created"`
+	UsersUpdated     []RosterUserChange `json:"users_updated"`
+	MembershipsAdded []RosterMembership `json:"memberships_added"`
+}
+
+// RestoredSnapshot counts the records loaded by RestoreSnapshot
+type RestoredSnapshot struct {
+	SchemaVersion int64 `json:"schema_version"`
+	Teams         int   `json:"teams"`
+	Users         int   `json:"users"`
+	Memberships   int   `json:"memberships"`
+	PullRequests  int   `json:"pull_requests"`
+	Assignments   int   `json:"assignments"`
+}
+
+type ReviewEvent struct {
+	Kind            string    `json:"-"`
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorId        string    `json:"author_id"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+type Stats struct {
+	Service string    `json:"service"`
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type userResponseJSON struct {
+	User User `json:"user"`
+}
+
+type chatHandleResponseJSON struct {
+	ChatHandle ChatHandle `json:"chat_handle"`
+}
+
+type emailSubscriptionResponseJSON struct {
+	EmailSubscription EmailSubscription `json:"email_subscription"`
+}
+
+func (c *Client) SetIsActive(ctx context.Context, userId string, isActive bool) (*User, error) {
+	var resp userResponseJSON
+	err := c.do(ctx, call{
+		method: http.MethodPost,
+		path:   "/users/setIsActive",
+		body: map[string]any{
+			"user_id":   userId,
+			"is_active": isActive,
+		},
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+// GetUserReviews lists pull requests where the user is assigned as a reviewer
+func (c *Client) GetUserReviews(ctx context.Context, userId string) (*UserReviews, error) {
+	var resp UserReviews
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/users/getReview",
+		query:      url.Values{"user_id": {userId}},
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) SetChatHandle(ctx context.Context, handle ChatHandle) (*ChatHandle, error) {
+	var resp chatHandleResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodPost,
+		path:       "/users/setChatHandle",
+		body:       handle,
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.ChatHandle, nil
+}
+
+func (c *Client) SetEmailSubscription(ctx context.Context, sub EmailSubscription) (*EmailSubscription, error) {
+	var resp emailSubscriptionResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodPost,
+		path:       "/users/setEmail",
+		body:       sub,
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.EmailSubscription, nil
+}
+
+// UnsubscribeEmail disables the email digest using the token from the digest email
+func (c *Client) UnsubscribeEmail(ctx context.Context, token string) error {
+	return c.do(ctx, call{
+		method:     http.MethodPost,
+		path:       "/email/unsubscribe",
+		query:      url.Values{"token": {token}},
+		idempotent: true,
+	}, nil)
+}
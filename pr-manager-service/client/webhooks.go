@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type webhookSubscriptionResponseJSON struct {
+	Subscription WebhookSubscription `json:"subscription"`
+}
+
+type webhookSubscriptionsResponseJSON struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+type identityResponseJSON struct {
+	Identity Identity `json:"identity"`
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*WebhookSubscription, error) {
+	var resp webhookSubscriptionResponseJSON
+	err := c.do(ctx, call{
+		method: http.MethodPost,
+		path:   "/webhooks/add",
+		body:   req,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Subscription, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookSubscription, error) {
+	var resp webhookSubscriptionsResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodGet,
+		path:       "/webhooks/list",
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Subscriptions, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	return c.do(ctx, call{
+		method: http.MethodPost,
+		path:   "/webhooks/delete",
+		body:   map[string]int64{"id": id},
+	}, nil)
+}
+
+// SetIdentity maps a GitHub or GitLab login to a service user
+func (c *Client) SetIdentity(ctx context.Context, identity Identity) (*Identity, error) {
+	var resp identityResponseJSON
+	err := c.do(ctx, call{
+		method:     http.MethodPost,
+		path:       "/integrations/identities/set",
+		body:       identity,
+		idempotent: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Identity, nil
+}
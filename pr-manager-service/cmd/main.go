@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -12,6 +13,10 @@ import (
 )
 
 func main() {
+	migrateOnly := flag.Bool("migrate-only", false, "apply database migrations and exit")
+	migrateDown := flag.Int("migrate-down", 0, "roll back the given number of migrations and exit")
+	flag.Parse()
+
 	cfg, err := config.NewConfig()
 	if err != nil {
 		log.Fatalf("Config error: %v", err)
@@ -20,8 +25,21 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	err = app.Run(ctx, cfg)
-	if err != nil {
-		log.Fatalf("Application run error: %v", err)
+	switch {
+	case *migrateDown > 0:
+		err = app.MigrateDown(ctx, cfg, *migrateDown)
+		if err != nil {
+			log.Fatalf("Migration error: %v", err)
+		}
+	case *migrateOnly:
+		err = app.MigrateUp(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Migration error: %v", err)
+		}
+	default:
+		err = app.Run(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Application run error: %v", err)
+		}
 	}
 }
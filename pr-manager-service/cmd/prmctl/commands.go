@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"pr-manager-service/client"
+)
+
+// env is what every command gets besides its own arguments
+type env struct {
+	flags  globalFlags
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]command{
+	"config": groupCommand("config", map[string]command{
+		"set-profile": configSetProfile,
+		"use":         configUse,
+		"list":        configList,
+	}),
+	"team": groupCommand("team", map[string]command{
+		"add": teamAdd,
+		"get": teamGet,
+	}),
+	"user": groupCommand("user", map[string]command{
+		"activate":   userSetActive(true),
+		"deactivate": userSetActive(false),
+	}),
+	"pr": groupCommand("pr", map[string]command{
+		"create":   prCreate,
+		"merge":    prMerge,
+		"reassign": prReassign,
+	}),
+	"stats": stats,
+}
+
+func groupCommand(group string, subcommands map[string]command) command {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%w: %s needs a subcommand", errUsage, group)
+		}
+		cmd, ok := subcommands[args[0]]
+		if !ok {
+			return fmt.Errorf("%w: unknown command %q", errUsage, group+" "+args[0])
+		}
+		return cmd(ctx, e, args[1:])
+	}
+}
+
+func (e *env) newClient() (*client.Client, error) {
+	p, err := resolveProfile(e.flags)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []client.Option{client.WithUserAgent("prmctl")}
+	if p.Token != "" {
+		opts = append(opts, client.WithAuth(client.BearerToken(p.Token)))
+	}
+	return client.New(p.BaseURL, opts...), nil
+}
+
+// Parses flags of a command; positional arguments may come before or after flags
+func parseArgs(fs *flag.FlagSet, e *env, args []string, positional int) ([]string, error) {
+	fs.SetOutput(e.stderr)
+
+	var values []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		values = append(values, args[0])
+		args = args[1:]
+	}
+
+	if len(values) != positional {
+		return nil, fmt.Errorf("%w: %s expects %d argument(s), got %d", errUsage, fs.Name(), positional, len(values))
+	}
+	return values, nil
+}
+
+func requireFlag(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%w: --%s is required", errUsage, name)
+	}
+	return nil
+}
+
+func configSetProfile(_ context.Context, e *env, args []string) error {
+	var p profile
+	fs := flag.NewFlagSet("config set-profile", flag.ContinueOnError)
+	fs.StringVar(&p.BaseURL, "url", defaultBaseURL, "service base url")
+	fs.StringVar(&p.Token, "token", "", "auth token like admin:u1")
+	values, err := parseArgs(fs, e, args, 1)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(e.flags.configPath)
+	if err != nil {
+		return err
+	}
+	cfg.Profiles[values[0]] = p
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = values[0]
+	}
+	return saveConfig(e.flags.configPath, cfg)
+}
+
+func configUse(_ context.Context, e *env, args []string) error {
+	values, err := parseArgs(flag.NewFlagSet("config use", flag.ContinueOnError), e, args, 1)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(e.flags.configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[values[0]]; !ok {
+		return fmt.Errorf("%w: unknown profile %q", errUsage, values[0])
+	}
+	cfg.CurrentProfile = values[0]
+	return saveConfig(e.flags.configPath, cfg)
+}
+
+func configList(_ context.Context, e *env, args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("config list", flag.ContinueOnError), e, args, 0); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(e.flags.configPath)
+	if err != nil {
+		return err
+	}
+	masked := &config{CurrentProfile: cfg.CurrentProfile, Profiles: map[string]profile{}}
+	for name, p := range cfg.Profiles {
+		masked.Profiles[name] = profile{BaseURL: p.BaseURL, Token: maskToken(p.Token)}
+	}
+
+	return e.print(masked, func(t *table) {
+		t.header("CURRENT", "PROFILE", "URL", "TOKEN")
+		for _, name := range masked.profileNames() {
+			current := ""
+			if name == masked.CurrentProfile {
+				current = "*"
+			}
+			p := masked.Profiles[name]
+			t.row(current, name, p.BaseURL, p.Token)
+		}
+	})
+}
+
+// memberFlags collects repeated --member ID:USERNAME[:inactive] values
+type memberFlags []client.TeamMember
+
+func (m *memberFlags) String() string {
+	return fmt.Sprint(len(*m))
+}
+
+func (m *memberFlags) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("member must be ID:USERNAME[:inactive], got %q", value)
+	}
+
+	member := client.TeamMember{UserId: parts[0], Username: parts[1], IsActive: true}
+	if len(parts) == 3 {
+		if parts[2] != "inactive" {
+			return fmt.Errorf("unknown member flag %q", parts[2])
+		}
+		member.IsActive = false
+	}
+	*m = append(*m, member)
+	return nil
+}
+
+func teamAdd(ctx context.Context, e *env, args []string) error {
+	var (
+		name    string
+		members memberFlags
+	)
+	fs := flag.NewFlagSet("team add", flag.ContinueOnError)
+	fs.StringVar(&name, "name", "", "team name")
+	fs.Var(&members, "member", "team member as ID:USERNAME[:inactive], repeatable")
+	if _, err := parseArgs(fs, e, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag("name", name); err != nil {
+		return err
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	team, err := c.CreateTeam(ctx, client.Team{TeamName: name, Members: members})
+	if err != nil {
+		return err
+	}
+	return e.printTeam(team)
+}
+
+func teamGet(ctx context.Context, e *env, args []string) error {
+	values, err := parseArgs(flag.NewFlagSet("team get", flag.ContinueOnError), e, args, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	team, err := c.GetTeam(ctx, values[0])
+	if err != nil {
+		return err
+	}
+	return e.printTeam(team)
+}
+
+func userSetActive(isActive bool) command {
+	name := "user deactivate"
+	if isActive {
+		name = "user activate"
+	}
+
+	return func(ctx context.Context, e *env, args []string) error {
+		values, err := parseArgs(flag.NewFlagSet(name, flag.ContinueOnError), e, args, 1)
+		if err != nil {
+			return err
+		}
+
+		c, err := e.newClient()
+		if err != nil {
+			return err
+		}
+		user, err := c.SetIsActive(ctx, values[0], isActive)
+		if err != nil {
+			return err
+		}
+		return e.print(user, func(t *table) {
+			t.header("USER ID", "USERNAME", "TEAM", "ACTIVE")
+			t.row(user.UserId, user.Username, user.TeamName, fmt.Sprint(user.IsActive))
+		})
+	}
+}
+
+func prCreate(ctx context.Context, e *env, args []string) error {
+	var req client.CreatePullRequestRequest
+	fs := flag.NewFlagSet("pr create", flag.ContinueOnError)
+	fs.StringVar(&req.PullRequestId, "id", "", "pull request id")
+	fs.StringVar(&req.PullRequestName, "name", "", "pull request name")
+	fs.StringVar(&req.AuthorId, "author", "", "author user id")
+	idempotencyKey := idempotencyKeyFlag(fs)
+	if _, err := parseArgs(fs, e, args, 0); err != nil {
+		return err
+	}
+	for _, f := range []struct{ name, value string }{
+		{"id", req.PullRequestId},
+		{"name", req.PullRequestName},
+		{"author", req.AuthorId},
+	} {
+		if err := requireFlag(f.name, f.value); err != nil {
+			return err
+		}
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	pr, err := c.CreatePullRequest(ctx, req, pullRequestOptions("", *idempotencyKey)...)
+	if err != nil {
+		return err
+	}
+	return e.printPullRequest(pr)
+}
+
+func prMerge(ctx context.Context, e *env, args []string) error {
+	var ifMatch string
+	fs := flag.NewFlagSet("pr merge", flag.ContinueOnError)
+	fs.StringVar(&ifMatch, "if-match", "", "fail unless the pull request still has this ETag")
+	idempotencyKey := idempotencyKeyFlag(fs)
+	values, err := parseArgs(fs, e, args, 1)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	pr, err := c.MergePullRequest(ctx, values[0], pullRequestOptions(ifMatch, *idempotencyKey)...)
+	if err != nil {
+		return err
+	}
+	return e.printPullRequest(pr)
+}
+
+func prReassign(ctx context.Context, e *env, args []string) error {
+	var oldUserId string
+	fs := flag.NewFlagSet("pr reassign", flag.ContinueOnError)
+	var ifMatch string
+	fs.StringVar(&oldUserId, "old", "", "reviewer to replace")
+	fs.StringVar(&ifMatch, "if-match", "", "fail unless the pull request still has this ETag")
+	idempotencyKey := idempotencyKeyFlag(fs)
+	values, err := parseArgs(fs, e, args, 1)
+	if err != nil {
+		return err
+	}
+	if err := requireFlag("old", oldUserId); err != nil {
+		return err
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.ReassignReviewer(ctx, values[0], oldUserId, pullRequestOptions(ifMatch, *idempotencyKey)...)
+	if err != nil {
+		return err
+	}
+	return e.print(result, func(t *table) {
+		t.header("PR ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS", "REPLACED BY")
+		t.row(result.PR.PullRequestId, result.PR.PullRequestName, result.PR.AuthorId,
+			result.PR.Status, strings.Join(result.PR.AssignedReviewers, ","), result.ReplacedBy)
+	})
+}
+
+func idempotencyKeyFlag(fs *flag.FlagSet) *string {
+	return fs.String("idempotency-key", "", "run the change once per key, repeated calls get the first response")
+}
+
+func pullRequestOptions(ifMatch, idempotencyKey string) []client.PullRequestOption {
+	var opts []client.PullRequestOption
+	if ifMatch != "" {
+		opts = append(opts, client.IfMatch(ifMatch))
+	}
+	if idempotencyKey != "" {
+		opts = append(opts, client.IdempotencyKey(idempotencyKey))
+	}
+	return opts
+}
+
+func stats(ctx context.Context, e *env, args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("stats", flag.ContinueOnError), e, args, 0); err != nil {
+		return err
+	}
+
+	c, err := e.newClient()
+	if err != nil {
+		return err
+	}
+	s, err := c.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	return e.print(s, func(t *table) {
+		t.header("SERVICE", "VERSION", "TIME")
+		t.row(s.Service, s.Version, s.Time.Format("2006-01-02T15:04:05Z07:00"))
+	})
+}
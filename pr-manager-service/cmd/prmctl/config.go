@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const defaultBaseURL = "http://localhost:8080"
+
+// config is stored as JSON in ~/.config/prmctl/config.json
+type config struct {
+	CurrentProfile string             `json:"current_profile"`
+	Profiles       map[string]profile `json:"profiles"`
+}
+
+type profile struct {
+	BaseURL string `json:"base_url"`
+	Token   string `json:"token"`
+}
+
+func defaultConfigPath() string {
+	if p := os.Getenv("PRMCTL_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "prmctl.json"
+	}
+	return filepath.Join(dir, "prmctl", "config.json")
+}
+
+// A missing file is an empty config
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Profiles: map[string]profile{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]profile{}
+	}
+	return cfg, nil
+}
+
+// The file holds tokens, so it is readable by the owner only
+func saveConfig(path string, cfg *config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func (c *config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolves the base url and token: flags override the selected profile
+func resolveProfile(g globalFlags) (profile, error) {
+	cfg, err := loadConfig(g.configPath)
+	if err != nil {
+		return profile{}, err
+	}
+
+	name := g.profile
+	if name == "" {
+		name = cfg.CurrentProfile
+	}
+
+	var p profile
+	if name != "" {
+		var ok bool
+		p, ok = cfg.Profiles[name]
+		if !ok {
+			return profile{}, fmt.Errorf("%w: unknown profile %q", errUsage, name)
+		}
+	}
+
+	if g.url != "" {
+		p.BaseURL = g.url
+	}
+	if g.token != "" {
+		p.Token = g.token
+	}
+	if p.BaseURL == "" {
+		p.BaseURL = defaultBaseURL
+	}
+	return p, nil
+}
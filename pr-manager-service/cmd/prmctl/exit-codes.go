@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"pr-manager-service/client"
+)
+
+// Exit codes, API errors get one code per error.code value
+const (
+	exitOK            = 0
+	exitFailure       = 1
+	exitUsage         = 2
+	exitValidation    = 3
+	exitNotFound      = 4
+	exitTeamExists    = 5
+	exitPrExists      = 6
+	exitPrMerged      = 7
+	exitNotAssigned   = 8
+	exitNoCandidate   = 9
+	exitNotConfigured = 10
+	exitInternal      = 11
+	exitUnauthorized  = 12
+	exitStaleVersion  = 13
+	exitKeyReused     = 14
+	exitInProgress    = 15
+	exitRateLimited   = 16
+)
+
+var exitCodesByErrorCode = map[client.ErrorCode]int{
+	client.CodeValidation:    exitValidation,
+	client.CodeNotFound:      exitNotFound,
+	client.CodeTeamExists:    exitTeamExists,
+	client.CodePrExists:      exitPrExists,
+	client.CodePrMerged:      exitPrMerged,
+	client.CodeNotAssigned:   exitNotAssigned,
+	client.CodeNoCandidate:   exitNoCandidate,
+	client.CodeNotConfigured: exitNotConfigured,
+	client.CodeInternal:      exitInternal,
+	client.CodeStaleVersion:  exitStaleVersion,
+	client.CodeKeyReused:     exitKeyReused,
+	client.CodeInProgress:    exitInProgress,
+	client.CodeRateLimited:   exitRateLimited,
+}
+
+// errUsage marks invalid command line arguments
+var errUsage = errors.New("invalid arguments")
+
+func exitCode(err error) int {
+	if errors.Is(err, errUsage) {
+		return exitUsage
+	}
+
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		// the service reports auth failures with the NOT_FOUND code
+		if apiErr.StatusCode == http.StatusUnauthorized {
+			return exitUnauthorized
+		}
+		if code, ok := exitCodesByErrorCode[apiErr.Code]; ok {
+			return code
+		}
+	}
+
+	return exitFailure
+}
@@ -0,0 +1,101 @@
+// Command prmctl is an admin command-line tool for pr-manager-service.
+//
+//	prmctl config set-profile local --url http://localhost:8080 --token admin:u1
+//	prmctl team get backend
+//	prmctl -o json pr create --id pr-1 --name "Add search" --author u1
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: prmctl [global flags] <command> [args]
+
+Commands:
+  config set-profile NAME --url URL --token TOKEN   create or update a profile
+  config use NAME                                   make the profile current
+  config list                                       list profiles
+  team add --name NAME --member ID:USERNAME[:inactive]...
+  team get NAME
+  user activate USER_ID
+  user deactivate USER_ID
+  pr create --id ID --name NAME --author USER_ID
+  pr merge ID
+  pr reassign ID --old USER_ID
+  stats
+
+Global flags:
+`
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	cancel()
+	os.Exit(code)
+}
+
+type globalFlags struct {
+	configPath string
+	profile    string
+	url        string
+	token      string
+	output     string
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var g globalFlags
+
+	fs := flag.NewFlagSet("prmctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&g.configPath, "config", defaultConfigPath(), "path to the config file")
+	fs.StringVar(&g.profile, "profile", os.Getenv("PRMCTL_PROFILE"), "profile name, the current profile by default")
+	fs.StringVar(&g.url, "url", "", "service base url, overrides the profile")
+	fs.StringVar(&g.token, "token", "", "auth token like admin:u1, overrides the profile")
+	fs.StringVar(&g.output, "o", outputTable, "output format: table or json")
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if g.output != outputTable && g.output != outputJSON {
+		_, _ = fmt.Fprintf(stderr, "unknown output format %q\n", g.output)
+		return exitUsage
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	cmd, ok := commands[rest[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n", rest[0])
+		fs.Usage()
+		return exitUsage
+	}
+
+	err := cmd(ctx, &env{
+		flags:  g,
+		stdout: stdout,
+		stderr: stderr,
+	}, rest[1:])
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runPrmctl(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_ProfilesAndTable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer admin:u1" {
+			t.Errorf("unexpected Authorization %q", got)
+		}
+		if r.URL.Path != "/team/get" || r.URL.Query().Get("team_name") != "backend" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"team":{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}}`)
+	}))
+	defer srv.Close()
+
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+
+	code, _, stderr := runPrmctl(t, "--config", cfgPath, "config", "set-profile", "local", "--url", srv.URL, "--token", "admin:u1")
+	if code != exitOK {
+		t.Fatalf("set-profile exit %d: %s", code, stderr)
+	}
+
+	code, stdout, stderr := runPrmctl(t, "--config", cfgPath, "team", "get", "backend")
+	if code != exitOK {
+		t.Fatalf("team get exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "TEAM") || !strings.Contains(stdout, "Alice") {
+		t.Fatalf("unexpected table output:\n%s", stdout)
+	}
+
+	code, stdout, _ = runPrmctl(t, "--config", cfgPath, "-o", "json", "config", "list")
+	if code != exitOK || strings.Contains(stdout, "admin:u1") || !strings.Contains(stdout, "admin:***") {
+		t.Fatalf("tokens must be masked in config list, got exit %d:\n%s", code, stdout)
+	}
+}
+
+func TestRun_JSONOutput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["pull_request_id"] != "pr-1" || body["author_id"] != "u1" {
+			t.Errorf("unexpected body %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"pr":{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN","assigned_reviewers":["u2"]}}`)
+	}))
+	defer srv.Close()
+
+	code, stdout, stderr := runPrmctl(t, "--config", filepath.Join(t.TempDir(), "none.json"), "--url", srv.URL, "-o", "json",
+		"pr", "create", "--id", "pr-1", "--name", "Add search", "--author", "u1")
+	if code != exitOK {
+		t.Fatalf("pr create exit %d: %s", code, stderr)
+	}
+
+	var pr struct {
+		Status            string   `json:"status"`
+		AssignedReviewers []string `json:"assigned_reviewers"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &pr); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout)
+	}
+	if pr.Status != "OPEN" || len(pr.AssignedReviewers) != 1 {
+		t.Fatalf("unexpected pull request %+v", pr)
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{"pr merged", http.StatusConflict, `{"error":{"code":"PR_MERGED","message":"merged"}}`, exitPrMerged},
+		{"not found", http.StatusNotFound, `{"error":{"code":"NOT_FOUND","message":"not found"}}`, exitNotFound},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"code":"NOT_FOUND","message":"missing token"}}`, exitUnauthorized},
+		{"unknown code", http.StatusTeapot, `{}`, exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			code, _, _ := runPrmctl(t, "--config", filepath.Join(t.TempDir(), "none.json"), "--url", srv.URL,
+				"pr", "reassign", "pr-1", "--old", "u2")
+			if code != tt.want {
+				t.Fatalf("expected exit %d, got %d", tt.want, code)
+			}
+		})
+	}
+
+	if code, _, _ := runPrmctl(t, "pr", "reassign", "pr-1"); code != exitUsage {
+		t.Fatalf("missing --old must be a usage error, got %d", code)
+	}
+	if code, _, _ := runPrmctl(t, "bogus"); code != exitUsage {
+		t.Fatalf("unknown command must be a usage error, got %d", code)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"pr-manager-service/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type table struct {
+	w *tabwriter.Writer
+}
+
+func (t *table) header(columns ...string) {
+	t.row(columns...)
+}
+
+func (t *table) row(columns ...string) {
+	_, _ = fmt.Fprintln(t.w, strings.Join(columns, "\t"))
+}
+
+// Prints v as JSON or renders it with fill as a table, depending on -o
+func (e *env) print(v any, fill func(t *table)) error {
+	if e.flags.output == outputJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	t := &table{w: tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)}
+	fill(t)
+	return t.w.Flush()
+}
+
+func (e *env) printTeam(team *client.Team) error {
+	return e.print(team, func(t *table) {
+		t.header("TEAM", "USER ID", "USERNAME", "ACTIVE")
+		for _, m := range team.Members {
+			t.row(team.TeamName, m.UserId, m.Username, fmt.Sprint(m.IsActive))
+		}
+	})
+}
+
+func (e *env) printPullRequest(pr *client.PullRequest) error {
+	return e.print(pr, func(t *table) {
+		t.header("PR ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS")
+		t.row(pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, strings.Join(pr.AssignedReviewers, ","))
+	})
+}
+
+// Shows only the role part of a token
+func maskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	role, _, found := strings.Cut(token, ":")
+	if !found {
+		return "***"
+	}
+	return role + ":***"
+}
@@ -1,17 +1,33 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	env "github.com/caarlos0/env/v10"
 )
 
 // Config holds application configuration loaded from env
 type Config struct {
-	App        App
-	Log        Log
-	HTTP       HTTP
-	PostgreSQL PostgreSQL
+	App          App
+	Log          Log
+	HTTP         HTTP
+	GRPC         GRPC
+	Storage      Storage
+	PostgreSQL   PostgreSQL
+	SQLite       SQLite
+	Webhooks     Webhooks
+	Integrations Integrations
+	Chat         Chat
+	Email        Email
+	Idempotency  Idempotency
+	Tracing      Tracing
+	Metrics      Metrics
+	Workload     Workload
+	RateLimit    RateLimit
 }
 
 type App struct {
@@ -25,15 +41,236 @@ type Log struct {
 
 type HTTP struct {
 	Port string `env:"HTTP_PORT,required"`
+	// bound of every /readyz dependency check
+	ReadinessTimeout time.Duration `env:"READINESS_CHECK_TIMEOUT" envDefault:"2s"`
+	// on shutdown /readyz fails for this long before the server stops,
+	// so load balancers stop sending traffic first
+	ShutdownDrainDelay time.Duration `env:"HTTP_SHUTDOWN_DRAIN_DELAY" envDefault:"3s"`
+	// bound of JSON request bodies, larger ones are rejected with 413
+	MaxBodyBytes int64 `env:"HTTP_MAX_BODY_BYTES" envDefault:"1048576"`
+	// OpenAPI contract requests are validated against, empty disables validation
+	OpenAPISpecPath string `env:"OPENAPI_SPEC_PATH"`
 }
 
+type GRPC struct {
+	Enabled bool   `env:"GRPC_ENABLED" envDefault:"true"`
+	Port    string `env:"GRPC_PORT" envDefault:"50051"`
+}
+
+// Storage drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+type Storage struct {
+	// postgres, sqlite, or memory for the demo mode without a database
+	Driver string `env:"DB_DRIVER" envDefault:"postgres"`
+}
+
+// PostgreSQL settings are required when DB_DRIVER is postgres
 type PostgreSQL struct {
-	User       string `env:"DB_USER,required"`
-	Password   string `env:"DB_PASSWORD,required"`
-	Host       string `env:"DB_HOST,required"`
-	Port       string `env:"DB_PORT,required"`
-	Name       string `env:"DB_NAME,required"`
-	SslEnabled bool   `env:"DB_SSL_ENABLED,required"`
+	User       string `env:"DB_USER"`
+	Password   string `env:"DB_PASSWORD"`
+	Host       string `env:"DB_HOST"`
+	Port       string `env:"DB_PORT"`
+	Name       string `env:"DB_NAME"`
+	SslEnabled bool   `env:"DB_SSL_ENABLED"`
+	// apply embedded migrations on start, otherwise only check the schema version
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" envDefault:"true"`
+
+	// pool sizing, zero keeps the pgx default
+	MaxConns          int32         `env:"DB_MAX_CONNS" envDefault:"10"`
+	MinConns          int32         `env:"DB_MIN_CONNS" envDefault:"0"`
+	MaxConnLifetime   time.Duration `env:"DB_MAX_CONN_LIFETIME" envDefault:"1h"`
+	MaxConnIdleTime   time.Duration `env:"DB_MAX_CONN_IDLE_TIME" envDefault:"30m"`
+	HealthCheckPeriod time.Duration `env:"DB_HEALTH_CHECK_PERIOD" envDefault:"1m"`
+	ConnectTimeout    time.Duration `env:"DB_CONNECT_TIMEOUT" envDefault:"5s"`
+
+	// bound of every repository call, zero disables it
+	QueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT" envDefault:"5s"`
+	// cache_statement, cache_describe, describe_exec, exec or simple_protocol,
+	// the last two work behind PgBouncer in transaction mode
+	QueryExecMode          string `env:"DB_QUERY_EXEC_MODE" envDefault:"cache_statement"`
+	StatementCacheCapacity int    `env:"DB_STATEMENT_CACHE_CAPACITY" envDefault:"512"`
+
+	// serialization failures, deadlocks and connection errors before the
+	// query was sent are retried, 1 disables retries
+	RetryMaxAttempts int           `env:"DB_RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	RetryBackoff     time.Duration `env:"DB_RETRY_BACKOFF" envDefault:"50ms"`
+}
+
+// Query exec modes of DB_QUERY_EXEC_MODE
+const (
+	QueryExecModeCacheStatement = "cache_statement"
+	QueryExecModeCacheDescribe  = "cache_describe"
+	QueryExecModeDescribeExec   = "describe_exec"
+	QueryExecModeExec           = "exec"
+	QueryExecModeSimpleProtocol = "simple_protocol"
+)
+
+// SQLite settings are used when DB_DRIVER is sqlite
+type SQLite struct {
+	// database file, created and migrated on start
+	Path string `env:"SQLITE_PATH" envDefault:"pr-manager.db"`
+}
+
+type Webhooks struct {
+	WorkerEnabled bool          `env:"WEBHOOK_WORKER_ENABLED" envDefault:"true"`
+	PollInterval  time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	BatchSize     int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	MaxAttempts   int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	BackoffBase   time.Duration `env:"WEBHOOK_BACKOFF_BASE" envDefault:"2s"`
+	BackoffMax    time.Duration `env:"WEBHOOK_BACKOFF_MAX" envDefault:"10m"`
+	Timeout       time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
+	// how long outbox events are kept after they are fanned out
+	OutboxRetention       time.Duration `env:"WEBHOOK_OUTBOX_RETENTION" envDefault:"168h"`
+	OutboxCleanupInterval time.Duration `env:"WEBHOOK_OUTBOX_CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+type Integrations struct {
+	GitHubWebhookSecret string `env:"GITHUB_WEBHOOK_SECRET"`
+	GitLabWebhookToken  string `env:"GITLAB_WEBHOOK_TOKEN"`
+}
+
+type Chat struct {
+	SlackWebhookUrl      string        `env:"SLACK_WEBHOOK_URL"`
+	MattermostWebhookUrl string        `env:"MATTERMOST_WEBHOOK_URL"`
+	Username             string        `env:"CHAT_USERNAME" envDefault:"pr-manager"`
+	Timeout              time.Duration `env:"CHAT_TIMEOUT" envDefault:"5s"`
+	QueueSize            int           `env:"CHAT_QUEUE_SIZE" envDefault:"1000"`
+	RatePerSecond        float64       `env:"CHAT_RATE_PER_SECOND" envDefault:"1"`
+	DigestEnabled        bool          `env:"CHAT_DIGEST_ENABLED" envDefault:"false"`
+	// UTC time of day in HH:MM format
+	DigestTime string `env:"CHAT_DIGEST_TIME" envDefault:"09:00"`
+}
+
+type Email struct {
+	DigestEnabled bool `env:"EMAIL_DIGEST_ENABLED" envDefault:"false"`
+	// UTC time of day in HH:MM format
+	DigestTime   string        `env:"EMAIL_DIGEST_TIME" envDefault:"09:00"`
+	SMTPHost     string        `env:"SMTP_HOST" envDefault:"localhost"`
+	SMTPPort     string        `env:"SMTP_PORT" envDefault:"25"`
+	SMTPUsername string        `env:"SMTP_USERNAME"`
+	SMTPPassword string        `env:"SMTP_PASSWORD"`
+	SMTPStartTLS bool          `env:"SMTP_STARTTLS" envDefault:"false"`
+	SMTPTimeout  time.Duration `env:"SMTP_TIMEOUT" envDefault:"10s"`
+	From         string        `env:"EMAIL_FROM" envDefault:"pr-manager@localhost"`
+	// Public base url of the service, used for unsubscribe links
+	PublicUrl string `env:"PUBLIC_URL" envDefault:"http://localhost:8080"`
+}
+
+type Idempotency struct {
+	// how long responses are kept for requests with an Idempotency-Key
+	TTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	// how long a request in progress holds its key, a key of a request
+	// that crashed before its response is taken over after the lease
+	Lease           time.Duration `env:"IDEMPOTENCY_LEASE" envDefault:"1m"`
+	CleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// Tracing exporters
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+)
+
+type Tracing struct {
+	// none, otlp or stdout
+	Exporter string `env:"TRACING_EXPORTER" envDefault:"none"`
+	// OTLP gRPC collector address in host:port format
+	OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317"`
+	OTLPInsecure bool   `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	// share of new traces to sample, from 0 to 1
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+}
+
+type Metrics struct {
+	// comma separated histogram buckets, empty means the common/kit defaults
+	DurationBuckets []float64 `env:"METRICS_DURATION_BUCKETS" envSeparator:","`
+	SizeBuckets     []float64 `env:"METRICS_SIZE_BUCKETS" envSeparator:","`
+}
+
+type Workload struct {
+	// how often the workload gauges are read from the database
+	RefreshInterval time.Duration `env:"WORKLOAD_REFRESH_INTERVAL" envDefault:"30s"`
+	// time to merge and to first verdict cover merges and verdicts within this window
+	MergedWindow time.Duration `env:"WORKLOAD_MERGED_WINDOW" envDefault:"168h"`
+}
+
+// Rate limit backends
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+)
+
+type RateLimit struct {
+	// memory keeps buckets per replica, postgres shares them between
+	// replicas and needs DB_DRIVER=postgres
+	Backend string `env:"RATE_LIMIT_BACKEND" envDefault:"memory"`
+	// limit of routes missing from RATE_LIMIT_ROUTES, empty means no limit
+	Default string `env:"RATE_LIMIT_DEFAULT"`
+	// comma separated route=limit pairs, e.g. /pullRequest/create=10/m
+	Routes map[string]string `env:"RATE_LIMIT_ROUTES" envKeyValSeparator:"="`
+	// how often buckets that refilled completely are deleted
+	CleanupInterval time.Duration `env:"RATE_LIMIT_CLEANUP_INTERVAL" envDefault:"10m"`
+}
+
+// Rate allows Requests at once and Requests per Period after that
+type Rate struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRate reads a limit in "<requests>/<period>" format, e.g. 10/m or 100/30s
+func ParseRate(s string) (Rate, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must be in <requests>/<period> format", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 1 {
+		return Rate{}, fmt.Errorf("rate %q must allow a positive number of requests", s)
+	}
+	switch period {
+	case "s", "m", "h":
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must have a positive period", s)
+	}
+	return Rate{Requests: n, Period: d}, nil
+}
+
+// DefaultRate parses RATE_LIMIT_DEFAULT, nil means no limit
+func (c RateLimit) DefaultRate() (*Rate, error) {
+	if c.Default == "" {
+		return nil, nil
+	}
+	rate, err := ParseRate(c.Default)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	return &rate, nil
+}
+
+// RouteRates parses RATE_LIMIT_ROUTES
+func (c RateLimit) RouteRates() (map[string]Rate, error) {
+	rates := make(map[string]Rate, len(c.Routes))
+	for route, s := range c.Routes {
+		if !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES: route %q must start with /", route)
+		}
+		rate, err := ParseRate(s)
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+		}
+		rates[route] = rate
+	}
+	return rates, nil
 }
 
 func NewConfig() (*Config, error) {
@@ -41,5 +278,211 @@ func NewConfig() (*Config, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
+	if err := cfg.validateHTTP(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateStorage(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateWebhooks(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateIdempotency(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateTracing(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateMetrics(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateWorkload(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := cfg.validateRateLimit(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
 	return cfg, nil
 }
+
+func (c *Config) validateWebhooks() error {
+	if c.Webhooks.OutboxRetention <= 0 {
+		return errors.New("WEBHOOK_OUTBOX_RETENTION must be positive")
+	}
+	if c.Webhooks.OutboxCleanupInterval <= 0 {
+		return errors.New("WEBHOOK_OUTBOX_CLEANUP_INTERVAL must be positive")
+	}
+	if !c.Webhooks.WorkerEnabled {
+		return nil
+	}
+	// the worker ticks every PollInterval and leases deliveries for Timeout
+	if c.Webhooks.PollInterval <= 0 {
+		return errors.New("WEBHOOK_POLL_INTERVAL must be positive")
+	}
+	if c.Webhooks.Timeout <= 0 {
+		return errors.New("WEBHOOK_TIMEOUT must be positive")
+	}
+	if c.Webhooks.BatchSize <= 0 {
+		return errors.New("WEBHOOK_BATCH_SIZE must be positive")
+	}
+	if c.Webhooks.MaxAttempts <= 0 {
+		return errors.New("WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateIdempotency() error {
+	if c.Idempotency.TTL <= 0 {
+		return errors.New("IDEMPOTENCY_TTL must be positive")
+	}
+	if c.Idempotency.Lease <= 0 {
+		return errors.New("IDEMPOTENCY_LEASE must be positive")
+	}
+	if c.Idempotency.CleanupInterval <= 0 {
+		return errors.New("IDEMPOTENCY_CLEANUP_INTERVAL must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateTracing() error {
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterOTLP:
+		if c.Tracing.OTLPEndpoint == "" {
+			return fmt.Errorf("TRACING_OTLP_ENDPOINT is required for TRACING_EXPORTER=%s", TracingExporterOTLP)
+		}
+	default:
+		return fmt.Errorf("unknown TRACING_EXPORTER %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *Config) validateMetrics() error {
+	buckets := []struct {
+		name   string
+		values []float64
+	}{
+		{"METRICS_DURATION_BUCKETS", c.Metrics.DurationBuckets},
+		{"METRICS_SIZE_BUCKETS", c.Metrics.SizeBuckets},
+	}
+	for _, b := range buckets {
+		for i, v := range b.values {
+			if v <= 0 || (i > 0 && v <= b.values[i-1]) {
+				return fmt.Errorf("%s must be positive and increasing", b.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateHTTP() error {
+	if c.HTTP.ReadinessTimeout <= 0 {
+		return errors.New("READINESS_CHECK_TIMEOUT must be positive")
+	}
+	if c.HTTP.ShutdownDrainDelay < 0 {
+		return errors.New("HTTP_SHUTDOWN_DRAIN_DELAY must not be negative")
+	}
+	if c.HTTP.MaxBodyBytes <= 0 {
+		return errors.New("HTTP_MAX_BODY_BYTES must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateWorkload() error {
+	if c.Workload.RefreshInterval <= 0 {
+		return errors.New("WORKLOAD_REFRESH_INTERVAL must be positive")
+	}
+	if c.Workload.MergedWindow <= 0 {
+		return errors.New("WORKLOAD_MERGED_WINDOW must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateRateLimit() error {
+	switch c.RateLimit.Backend {
+	case RateLimitBackendMemory:
+	case RateLimitBackendPostgres:
+		if c.Storage.Driver != DriverPostgres {
+			return fmt.Errorf("RATE_LIMIT_BACKEND=%s needs DB_DRIVER=%s", RateLimitBackendPostgres, DriverPostgres)
+		}
+	default:
+		return fmt.Errorf("unknown RATE_LIMIT_BACKEND %q", c.RateLimit.Backend)
+	}
+	if c.RateLimit.CleanupInterval <= 0 {
+		return errors.New("RATE_LIMIT_CLEANUP_INTERVAL must be positive")
+	}
+	if _, err := c.RateLimit.DefaultRate(); err != nil {
+		return err
+	}
+	_, err := c.RateLimit.RouteRates()
+	return err
+}
+
+func (c *Config) validateStorage() error {
+	switch c.Storage.Driver {
+	case DriverPostgres:
+		required := []struct{ name, value string }{
+			{"DB_USER", c.PostgreSQL.User},
+			{"DB_HOST", c.PostgreSQL.Host},
+			{"DB_PORT", c.PostgreSQL.Port},
+			{"DB_NAME", c.PostgreSQL.Name},
+		}
+		for _, r := range required {
+			if r.value == "" {
+				return fmt.Errorf("%s is required for DB_DRIVER=%s", r.name, DriverPostgres)
+			}
+		}
+		return c.validatePool()
+	case DriverSQLite:
+		if c.SQLite.Path == "" {
+			return fmt.Errorf("SQLITE_PATH is required for DB_DRIVER=%s", DriverSQLite)
+		}
+		return nil
+	case DriverMemory:
+		return nil
+	default:
+		return fmt.Errorf("unknown DB_DRIVER %q", c.Storage.Driver)
+	}
+}
+
+func (c *Config) validatePool() error {
+	pg := c.PostgreSQL
+	if pg.MaxConns < 0 || pg.MinConns < 0 {
+		return errors.New("DB_MAX_CONNS and DB_MIN_CONNS must not be negative")
+	}
+	if pg.MaxConns > 0 && pg.MinConns > pg.MaxConns {
+		return errors.New("DB_MIN_CONNS must not exceed DB_MAX_CONNS")
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"DB_MAX_CONN_LIFETIME", pg.MaxConnLifetime},
+		{"DB_MAX_CONN_IDLE_TIME", pg.MaxConnIdleTime},
+		{"DB_HEALTH_CHECK_PERIOD", pg.HealthCheckPeriod},
+		{"DB_CONNECT_TIMEOUT", pg.ConnectTimeout},
+		{"DB_QUERY_TIMEOUT", pg.QueryTimeout},
+		{"DB_RETRY_BACKOFF", pg.RetryBackoff},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf("%s must not be negative", d.name)
+		}
+	}
+	switch pg.QueryExecMode {
+	case QueryExecModeCacheStatement, QueryExecModeCacheDescribe, QueryExecModeDescribeExec,
+		QueryExecModeExec, QueryExecModeSimpleProtocol:
+	default:
+		return fmt.Errorf("unknown DB_QUERY_EXEC_MODE %q", pg.QueryExecMode)
+	}
+	if pg.StatementCacheCapacity < 0 {
+		return errors.New("DB_STATEMENT_CACHE_CAPACITY must not be negative")
+	}
+	if pg.RetryMaxAttempts < 1 {
+		return errors.New("DB_RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+	return nil
+}
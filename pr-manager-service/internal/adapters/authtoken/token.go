@@ -0,0 +1,60 @@
+// Package authtoken parses the bearer tokens shared by the HTTP and gRPC adapters
+package authtoken
+
+import (
+	"errors"
+	"strings"
+)
+
+// Info is the caller identity carried by a token
+type Info struct {
+	UserId  string
+	IsAdmin bool
+}
+
+var (
+	ErrNoToken       = errors.New("missing Authorization header")
+	ErrInvalidFormat = errors.New("invalid Authorization header format")
+)
+
+// Parse parses an Authorization value:
+//
+//	Bearer admin:<user_id>
+//	Bearer user:<user_id>
+func Parse(header string) (*Info, error) {
+	if header == "" {
+		return nil, ErrNoToken
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, ErrInvalidFormat
+	}
+
+	token := parts[1]
+	tokenParts := strings.SplitN(token, ":", 2)
+	if len(tokenParts) != 2 {
+		return nil, ErrInvalidFormat
+	}
+
+	role := tokenParts[0]
+	userId := tokenParts[1]
+	if userId == "" {
+		return nil, ErrInvalidFormat
+	}
+
+	info := &Info{
+		UserId: userId,
+	}
+
+	switch role {
+	case "admin":
+		info.IsAdmin = true
+	case "user":
+		info.IsAdmin = false
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	return info, nil
+}
@@ -0,0 +1,130 @@
+package chatadapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+var (
+	ErrProviderNotConfigured = errors.New("chat provider is not configured")
+	ErrQueueFull             = errors.New("notification queue is full")
+)
+
+type NotifierConfig struct {
+	SlackWebhookUrl      string
+	MattermostWebhookUrl string
+	Username             string
+	Timeout              time.Duration
+	QueueSize            int
+	// Messages per second across all providers
+	RatePerSecond float64
+}
+
+// Notifier queues notifications and sends them in the background,
+// no faster than the configured rate
+type Notifier struct {
+	senders  map[string]sender
+	queue    chan domain.Notification
+	interval time.Duration
+	logger   usecase.LoggerInterface
+}
+
+var _ usecase.NotifierInterface = (*Notifier)(nil)
+
+func NewNotifier(cfg NotifierConfig, logger usecase.LoggerInterface) *Notifier {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	senders := make(map[string]sender)
+	if cfg.SlackWebhookUrl != "" {
+		senders[domain.ChatProviderSlack] = NewSlackWebhook(cfg.SlackWebhookUrl, cfg.Username, client)
+	}
+	if cfg.MattermostWebhookUrl != "" {
+		senders[domain.ChatProviderMattermost] = NewMattermostWebhook(cfg.MattermostWebhookUrl, cfg.Username, client)
+	}
+
+	var interval time.Duration
+	if cfg.RatePerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.RatePerSecond)
+	}
+
+	return &Notifier{
+		senders:  senders,
+		queue:    make(chan domain.Notification, cfg.QueueSize),
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Notify enqueues the notification without waiting for delivery
+func (n *Notifier) Notify(ctx context.Context, notification domain.Notification) error {
+	if _, ok := n.senders[notification.Recipient.Provider]; !ok {
+		return ErrProviderNotConfigured
+	}
+
+	select {
+	case n.queue <- notification:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run sends queued notifications until ctx is cancelled
+func (n *Notifier) Run(ctx context.Context) {
+	var last time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if pending := len(n.queue); pending > 0 {
+				n.logger.Warn("notifier stopped with pending notifications", map[string]any{
+					"pending": pending,
+				})
+			}
+			return
+		case notification := <-n.queue:
+			if wait := n.interval - time.Since(last); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+			last = time.Now()
+			n.send(ctx, notification)
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, notification domain.Notification) {
+	text, err := render(notification)
+	if err != nil {
+		n.logger.Error("notifier: render error", map[string]any{
+			"kind":    notification.Kind,
+			"user_id": notification.Recipient.UserId,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	s := n.senders[notification.Recipient.Provider]
+	if err := s.Send(ctx, notification.Recipient.Handle, text); err != nil {
+		n.logger.Error("notifier: send error", map[string]any{
+			"kind":     notification.Kind,
+			"user_id":  notification.Recipient.UserId,
+			"provider": notification.Recipient.Provider,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	n.logger.Debug("notification sent", map[string]any{
+		"kind":     notification.Kind,
+		"user_id":  notification.Recipient.UserId,
+		"provider": notification.Recipient.Provider,
+	})
+}
@@ -0,0 +1,176 @@
+package chatadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, map[string]any) {}
+func (noopLogger) Info(string, map[string]any)  {}
+func (noopLogger) Warn(string, map[string]any)  {}
+func (noopLogger) Error(string, map[string]any) {}
+
+type receivedMessage struct {
+	Path    string
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+	At      time.Time
+}
+
+// chatStandIn records messages posted to incoming webhooks
+type chatStandIn struct {
+	mu       sync.Mutex
+	messages []receivedMessage
+	received chan struct{}
+}
+
+func newChatStandIn(t *testing.T) (*chatStandIn, *httptest.Server) {
+	s := &chatStandIn{received: make(chan struct{}, 100)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg receivedMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		msg.Path = r.URL.Path
+		msg.At = time.Now()
+
+		s.mu.Lock()
+		s.messages = append(s.messages, msg)
+		s.mu.Unlock()
+		s.received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *chatStandIn) wait(t *testing.T, n int) []receivedMessage {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i+1)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMessage(nil), s.messages...)
+}
+
+func TestNotifier_DeliversToProviders(t *testing.T) {
+	standIn, srv := newChatStandIn(t)
+
+	n := NewNotifier(NotifierConfig{
+		SlackWebhookUrl:      srv.URL + "/slack",
+		MattermostWebhookUrl: srv.URL + "/mattermost",
+		Timeout:              time.Second,
+		QueueSize:            10,
+	}, noopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	pr := domain.PullRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", StatusId: 1}
+	err := n.Notify(ctx, domain.Notification{
+		Kind:         domain.NotificationReviewerAssigned,
+		Recipient:    domain.ChatHandle{UserId: "u2", Provider: domain.ChatProviderSlack, Handle: "bob"},
+		PullRequests: []domain.PullRequest{pr},
+	})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	err = n.Notify(ctx, domain.Notification{
+		Kind:         domain.NotificationReviewDigest,
+		Recipient:    domain.ChatHandle{UserId: "u3", Provider: domain.ChatProviderMattermost, Handle: "charlie"},
+		PullRequests: []domain.PullRequest{pr},
+	})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	msgs := standIn.wait(t, 2)
+
+	if msgs[0].Path != "/slack" || msgs[0].Channel != "@bob" {
+		t.Fatalf("unexpected slack message: %+v", msgs[0])
+	}
+	if !strings.Contains(msgs[0].Text, "Add search") || !strings.Contains(msgs[0].Text, "pr-1") {
+		t.Fatalf("assigned message does not mention the pull request: %q", msgs[0].Text)
+	}
+
+	if msgs[1].Path != "/mattermost" || msgs[1].Channel != "@charlie" {
+		t.Fatalf("unexpected mattermost message: %+v", msgs[1])
+	}
+	if !strings.Contains(msgs[1].Text, "open reviews (1)") {
+		t.Fatalf("unexpected digest text: %q", msgs[1].Text)
+	}
+}
+
+func TestNotifier_RateLimited(t *testing.T) {
+	standIn, srv := newChatStandIn(t)
+
+	n := NewNotifier(NotifierConfig{
+		SlackWebhookUrl: srv.URL,
+		Timeout:         time.Second,
+		QueueSize:       10,
+		RatePerSecond:   20,
+	}, noopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notification := domain.Notification{
+		Kind:         domain.NotificationReviewerAssigned,
+		Recipient:    domain.ChatHandle{UserId: "u2", Provider: domain.ChatProviderSlack, Handle: "bob"},
+		PullRequests: []domain.PullRequest{{PullRequestId: "pr-1"}},
+	}
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(ctx, notification); err != nil {
+			t.Fatalf("notify: %v", err)
+		}
+	}
+	go n.Run(ctx)
+
+	msgs := standIn.wait(t, 3)
+	for i := 1; i < len(msgs); i++ {
+		// 20 per second is one message per 50ms, allow some scheduling slack
+		if gap := msgs[i].At.Sub(msgs[i-1].At); gap < 40*time.Millisecond {
+			t.Fatalf("messages %d and %d sent %v apart", i-1, i, gap)
+		}
+	}
+}
+
+func TestNotifier_Rejects(t *testing.T) {
+	n := NewNotifier(NotifierConfig{
+		SlackWebhookUrl: "http://127.0.0.1:0",
+		QueueSize:       1,
+	}, noopLogger{})
+
+	notification := domain.Notification{
+		Kind:         domain.NotificationReviewerAssigned,
+		Recipient:    domain.ChatHandle{UserId: "u2", Provider: domain.ChatProviderMattermost, Handle: "bob"},
+		PullRequests: []domain.PullRequest{{PullRequestId: "pr-1"}},
+	}
+	if err := n.Notify(context.Background(), notification); err != ErrProviderNotConfigured {
+		t.Fatalf("expected ErrProviderNotConfigured, got %v", err)
+	}
+
+	notification.Recipient.Provider = domain.ChatProviderSlack
+	if err := n.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if err := n.Notify(context.Background(), notification); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package chatadapter
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"pr-manager-service/internal/domain"
+)
+
+var templates = map[string]*template.Template{
+	domain.NotificationReviewerAssigned: template.Must(template.New("assigned").Parse(
+		`You were assigned to review {{with index .PullRequests 0}}*{{.PullRequestName}}* ({{.PullRequestId}}) by {{.AuthorId}}{{end}}`,
+	)),
+	domain.NotificationReviewDigest: template.Must(template.New("digest").Parse(
+		`Your open reviews ({{len .PullRequests}}):
+{{range .PullRequests}}• *{{.PullRequestName}}* ({{.PullRequestId}}) by {{.AuthorId}}
+{{end}}`,
+	)),
+}
+
+func render(n domain.Notification) (string, error) {
+	tmpl, ok := templates[n.Kind]
+	if !ok {
+		return "", fmt.Errorf("no template for notification kind %q", n.Kind)
+	}
+	if len(n.PullRequests) == 0 {
+		return "", fmt.Errorf("notification %q has no pull requests", n.Kind)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
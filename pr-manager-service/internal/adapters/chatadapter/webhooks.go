@@ -0,0 +1,94 @@
+package chatadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// sender posts a message to a single chat user
+type sender interface {
+	Send(ctx context.Context, handle, text string) error
+}
+
+// SlackWebhook posts messages through a Slack incoming webhook
+type SlackWebhook struct {
+	url      string
+	username string
+	client   *http.Client
+}
+
+func NewSlackWebhook(url, username string, client *http.Client) *SlackWebhook {
+	return &SlackWebhook{url: url, username: username, client: client}
+}
+
+type slackMessageJSON struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+	Mrkdwn   bool   `json:"mrkdwn"`
+}
+
+func (s *SlackWebhook) Send(ctx context.Context, handle, text string) error {
+	return postJSON(ctx, s.client, s.url, slackMessageJSON{
+		Channel:  "@" + handle,
+		Text:     text,
+		Username: s.username,
+		Mrkdwn:   true,
+	})
+}
+
+// MattermostWebhook posts messages through a Mattermost incoming webhook
+type MattermostWebhook struct {
+	url      string
+	username string
+	client   *http.Client
+}
+
+func NewMattermostWebhook(url, username string, client *http.Client) *MattermostWebhook {
+	return &MattermostWebhook{url: url, username: username, client: client}
+}
+
+type mattermostMessageJSON struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+}
+
+func (m *MattermostWebhook) Send(ctx context.Context, handle, text string) error {
+	return postJSON(ctx, m.client, m.url, mattermostMessageJSON{
+		Channel:  "@" + handle,
+		Text:     text,
+		Username: m.username,
+	})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
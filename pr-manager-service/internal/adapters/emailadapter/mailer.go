@@ -0,0 +1,207 @@
+package emailadapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// Require STARTTLS before sending credentials and mail
+	StartTLS bool
+	Timeout  time.Duration
+	// Public base url of the service, used for unsubscribe links
+	PublicUrl string
+}
+
+// SMTPMailer sends review digests as multipart plain text and HTML emails
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+var _ usecase.MailerInterface = (*SMTPMailer)(nil)
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) SendDigest(ctx context.Context, digest domain.EmailDigest) error {
+	msg, err := m.buildDigest(digest)
+	if err != nil {
+		return err
+	}
+	return m.send(ctx, digest.Recipient.Email, msg)
+}
+
+func (m *SMTPMailer) unsubscribeUrl(token string) string {
+	return strings.TrimRight(m.cfg.PublicUrl, "/") + "/email/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+func (m *SMTPMailer) buildDigest(digest domain.EmailDigest) ([]byte, error) {
+	unsubscribeUrl := m.unsubscribeUrl(digest.Recipient.UnsubscribeToken)
+	view := newDigestView(digest, unsubscribeUrl)
+
+	var text, html bytes.Buffer
+	if err := textDigest.Execute(&text, view); err != nil {
+		return nil, err
+	}
+	if err := htmlDigest.Execute(&html, view); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := writePart(mw, "text/plain; charset=utf-8", text.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writePart(mw, "text/html; charset=utf-8", html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	messageId, err := newMessageId(m.cfg.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	headers := []struct{ key, value string }{
+		{"From", m.cfg.From},
+		{"To", digest.Recipient.Email},
+		{"Subject", mime.QEncoding.Encode("utf-8", fmt.Sprintf(subjectTemplate, len(digest.PullRequests)))},
+		{"Date", digest.GeneratedAt.Format(time.RFC1123Z)},
+		{"Message-ID", messageId},
+		{"MIME-Version", "1.0"},
+		{"List-Unsubscribe", "<" + unsubscribeUrl + ">"},
+		{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h.key, h.value)
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+func writePart(mw *multipart.Writer, contentType string, content []byte) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write(content); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func newMessageId(from string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	domainPart := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domainPart = strings.TrimRight(from[at+1:], ">")
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domainPart + ">", nil
+}
+
+func (m *SMTPMailer) send(ctx context.Context, to string, msg []byte) error {
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+
+	dialer := net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	if m.cfg.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return err
+		}
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(envelopeAddress(m.cfg.From)); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// Extracts the bare address from "Name <addr>"
+func envelopeAddress(from string) string {
+	if start := strings.LastIndex(from, "<"); start >= 0 {
+		if end := strings.LastIndex(from, ">"); end > start {
+			return from[start+1 : end]
+		}
+	}
+	return from
+}
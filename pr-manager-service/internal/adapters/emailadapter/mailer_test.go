@@ -0,0 +1,185 @@
+package emailadapter
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type sinkMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpSink is a minimal local SMTP server that accepts every message
+func newSMTPSink(t *testing.T) (string, <-chan sinkMessage) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	messages := make(chan sinkMessage, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, messages)
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, messages chan<- sinkMessage) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+
+	_ = tp.PrintfLine("220 sink ready")
+	var msg sinkMessage
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 sink")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg.From = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			_ = tp.PrintfLine("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.To = append(msg.To, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			_ = tp.PrintfLine("250 ok")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			messages <- msg
+			msg = sinkMessage{}
+			_ = tp.PrintfLine("250 queued")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSMTPMailer_SendDigest(t *testing.T) {
+	addr, messages := newSMTPSink(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host:      host,
+		Port:      port,
+		From:      "PR Manager <prm@example.com>",
+		Timeout:   2 * time.Second,
+		PublicUrl: "https://prm.example.com/",
+	})
+
+	now := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	digest := domain.EmailDigest{
+		Recipient: domain.EmailSubscription{
+			UserId:           "u2",
+			Email:            "bob@example.com",
+			DigestEnabled:    true,
+			UnsubscribeToken: "tok123",
+		},
+		PullRequests: []domain.PullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", CreatedAt: now.Add(-50 * time.Hour)},
+			{PullRequestId: "pr-2", PullRequestName: "Fix <login>", AuthorId: "u3", CreatedAt: now.Add(-90 * time.Minute)},
+		},
+		GeneratedAt: now,
+	}
+
+	if err := mailer.SendDigest(context.Background(), digest); err != nil {
+		t.Fatalf("send digest: %v", err)
+	}
+
+	var got sinkMessage
+	select {
+	case got = <-messages:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("sink received no message")
+	}
+
+	if got.From != "prm@example.com" || len(got.To) != 1 || got.To[0] != "bob@example.com" {
+		t.Fatalf("unexpected envelope: %+v", got)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.Data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if u := msg.Header.Get("List-Unsubscribe"); u != "<https://prm.example.com/email/unsubscribe?token=tok123>" {
+		t.Fatalf("unexpected List-Unsubscribe: %q", u)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q: %v", mediaType, err)
+	}
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		// multipart.Reader decodes quoted-printable transparently
+		body, _ := io.ReadAll(bufio.NewReader(p))
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+
+	text := parts["text/plain"]
+	for _, want := range []string{"Add search (pr-1)", "author: u1, open for 2d 2h", "open for 1h 30m", "https://prm.example.com/email/unsubscribe?token=tok123"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("text part does not contain %q:\n%s", want, text)
+		}
+	}
+
+	html := parts["text/html"]
+	if !strings.Contains(html, "Fix &lt;login&gt;") {
+		t.Fatalf("html part is not escaped:\n%s", html)
+	}
+	if !strings.Contains(html, `href="https://prm.example.com/email/unsubscribe?token=tok123"`) {
+		t.Fatalf("html part has no unsubscribe link:\n%s", html)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Second:              "less than a minute",
+		12 * time.Minute:              "12m",
+		5*time.Hour + 10*time.Minute:  "5h 10m",
+		76*time.Hour + 59*time.Minute: "3d 4h",
+	}
+	for d, want := range tests {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package emailadapter
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+const subjectTemplate = "Pending reviews: %d open pull request(s)"
+
+var textDigest = texttemplate.Must(texttemplate.New("digest.txt").Parse(
+	`Hi {{.UserId}},
+
+you have {{len .PullRequests}} open review assignment(s):
+
+{{range .PullRequests}}- {{.Name}} ({{.Id}})
+  author: {{.AuthorId}}, open for {{.Age}}
+{{end}}
+To stop receiving this digest open {{.UnsubscribeUrl}}
+`))
+
+var htmlDigest = htmltemplate.Must(htmltemplate.New("digest.html").Parse(
+	`<!DOCTYPE html>
+<html>
+<body>
+<p>Hi {{.UserId}},</p>
+<p>you have {{len .PullRequests}} open review assignment(s):</p>
+<table cellpadding="4">
+<tr><th align="left">Pull request</th><th align="left">Author</th><th align="left">Open for</th></tr>
+{{range .PullRequests}}<tr><td>{{.Name}} ({{.Id}})</td><td>{{.AuthorId}}</td><td>{{.Age}}</td></tr>
+{{end}}</table>
+<p><a href="{{.UnsubscribeUrl}}">Unsubscribe</a> from this digest.</p>
+</body>
+</html>
+`))
+
+type digestView struct {
+	UserId         string
+	PullRequests   []pullRequestView
+	UnsubscribeUrl string
+}
+
+type pullRequestView struct {
+	Id       string
+	Name     string
+	AuthorId string
+	Age      string
+}
+
+func newDigestView(digest domain.EmailDigest, unsubscribeUrl string) digestView {
+	prs := make([]pullRequestView, 0, len(digest.PullRequests))
+	for _, pr := range digest.PullRequests {
+		prs = append(prs, pullRequestView{
+			Id:       pr.PullRequestId,
+			Name:     pr.PullRequestName,
+			AuthorId: pr.AuthorId,
+			Age:      formatAge(digest.GeneratedAt.Sub(pr.CreatedAt)),
+		})
+	}
+	return digestView{
+		UserId:         digest.Recipient.UserId,
+		PullRequests:   prs,
+		UnsubscribeUrl: unsubscribeUrl,
+	}
+}
+
+// Formats a duration as "3d 4h", "5h 10m" or "12m"
+func formatAge(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
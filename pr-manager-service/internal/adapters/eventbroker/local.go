@@ -0,0 +1,62 @@
+package eventbroker
+
+import (
+	"context"
+	"sync"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+// Buffer of a single subscription, events are dropped for slow subscribers
+const subscriberBuffer = 64
+
+// LocalBroker fans out events to subscribers of the current process
+type LocalBroker struct {
+	mu     sync.RWMutex
+	nextId int
+	subs   map[int]chan domain.Event
+}
+
+var _ usecase.EventBrokerInterface = (*LocalBroker)(nil)
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		subs: make(map[int]chan domain.Event),
+	}
+}
+
+func (b *LocalBroker) Publish(ctx context.Context, ev domain.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *LocalBroker) Subscribe() (<-chan domain.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextId
+	b.nextId++
+	ch := make(chan domain.Event, subscriberBuffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,139 @@
+package eventbroker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres channel shared by all replicas of the service
+const notifyChannel = "pr_manager_events"
+
+// PostgresBroker publishes events through Postgres NOTIFY, so subscribers of
+// every replica receive them. Events of this replica come back through
+// LISTEN as well and are delivered to local subscribers only once.
+type PostgresBroker struct {
+	pool   *pgxpool.Pool
+	local  *LocalBroker
+	logger usecase.LoggerInterface
+}
+
+var _ usecase.EventBrokerInterface = (*PostgresBroker)(nil)
+
+func NewPostgresBroker(pool *pgxpool.Pool, logger usecase.LoggerInterface) *PostgresBroker {
+	return &PostgresBroker{
+		pool:   pool,
+		local:  NewLocalBroker(),
+		logger: logger,
+	}
+}
+
+type eventJSON struct {
+	EventType       string    `json:"event_type"`
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorId        string    `json:"author_id"`
+	ReviewerId      string    `json:"reviewer_id,omitempty"`
+	OldReviewerId   string    `json:"old_reviewer_id,omitempty"`
+	Reviewers       []string  `json:"reviewers"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, ev domain.Event) error {
+	payload, err := json.Marshal(eventJSON{
+		EventType:       ev.EventType,
+		PullRequestId:   ev.PullRequestId,
+		PullRequestName: ev.PullRequestName,
+		AuthorId:        ev.AuthorId,
+		ReviewerId:      ev.ReviewerId,
+		OldReviewerId:   ev.OldReviewerId,
+		Reviewers:       ev.Reviewers,
+		OccurredAt:      ev.OccurredAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = b.pool.Exec(ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBroker) Subscribe() (<-chan domain.Event, func()) {
+	return b.local.Subscribe()
+}
+
+// Listen receives notifications of all replicas until ctx is cancelled,
+// reconnecting after connection failures. The backoff grows while LISTEN
+// fails and starts over once it succeeds.
+func (b *PostgresBroker) Listen(ctx context.Context) {
+	const initialBackoff = time.Second
+	backoff := initialBackoff
+	for {
+		listening, err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if listening {
+			backoff = initialBackoff
+		}
+
+		b.logger.Error("event broker listen error, reconnecting", map[string]any{
+			"error":    err.Error(),
+			"retry_in": backoff.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// listen reports whether LISTEN succeeded before the connection failed
+func (b *PostgresBroker) listen(ctx context.Context) (bool, error) {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+notifyChannel)
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var ev eventJSON
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			b.logger.Warn("event broker: invalid notification payload", map[string]any{
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		_ = b.local.Publish(ctx, domain.Event{
+			EventType:       ev.EventType,
+			PullRequestId:   ev.PullRequestId,
+			PullRequestName: ev.PullRequestName,
+			AuthorId:        ev.AuthorId,
+			ReviewerId:      ev.ReviewerId,
+			OldReviewerId:   ev.OldReviewerId,
+			Reviewers:       ev.Reviewers,
+			OccurredAt:      ev.OccurredAt,
+		})
+	}
+}
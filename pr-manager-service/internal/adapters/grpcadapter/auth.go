@@ -0,0 +1,122 @@
+package grpcadapter
+
+import (
+	"context"
+	"strings"
+
+	"pr-manager-service/internal/adapters/authtoken"
+	pb "pr-manager-service/internal/adapters/grpcadapter/prmanagerv1"
+
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type access int
+
+const (
+	accessPublic access = iota
+	accessAnyAuth
+	accessAdmin
+)
+
+// Same roles as the HTTP endpoints
+var methodAccess = map[string]access{
+	pb.PRManagerService_CreateTeam_FullMethodName:        accessPublic,
+	pb.PRManagerService_GetTeam_FullMethodName:           accessAnyAuth,
+	pb.PRManagerService_SetIsActive_FullMethodName:       accessAdmin,
+	pb.PRManagerService_GetUserReviews_FullMethodName:    accessAnyAuth,
+	pb.PRManagerService_StreamUserReviews_FullMethodName: accessAnyAuth,
+	pb.PRManagerService_CreatePullRequest_FullMethodName: accessAdmin,
+	pb.PRManagerService_MergePullRequest_FullMethodName:  accessAdmin,
+	pb.PRManagerService_ReassignReviewer_FullMethodName:  accessAdmin,
+	pb.PRManagerService_GetStats_FullMethodName:          accessPublic,
+}
+
+// Methods of other services (health) are public, unknown methods of
+// the PR manager service require an admin
+func accessFor(fullMethod string) access {
+	if a, ok := methodAccess[fullMethod]; ok {
+		return a
+	}
+	if strings.HasPrefix(fullMethod, "/"+pb.PRManagerService_ServiceDesc.ServiceName+"/") {
+		return accessAdmin
+	}
+	return accessPublic
+}
+
+type authInfoKey struct{}
+
+func authFromContext(ctx context.Context) (*authtoken.Info, bool) {
+	info, ok := ctx.Value(authInfoKey{}).(*authtoken.Info)
+	return info, ok
+}
+
+// Checks the authorization metadata and stores the caller in the context
+func authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	required := accessFor(fullMethod)
+	if required == accessPublic {
+		return ctx, nil
+	}
+
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	info, err := authtoken.Parse(header)
+	if err != nil {
+		if required == accessAdmin {
+			return nil, status.Error(codes.Unauthenticated, "admin token required")
+		}
+		return nil, status.Error(codes.Unauthenticated, "auth token required")
+	}
+
+	if required == accessAdmin && !info.IsAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin token required")
+	}
+
+	ctx = kitlogger.ContextWithCallerID(ctx, info.UserId)
+	return context.WithValue(ctx, authInfoKey{}, info), nil
+}
+
+func unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Only admins may act on behalf of other users
+func requireSelfOrAdmin(ctx context.Context, userId string) error {
+	info, ok := authFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "auth token required")
+	}
+	if !info.IsAdmin && info.UserId != userId {
+		return status.Error(codes.PermissionDenied, "forbidden for this user_id")
+	}
+	return nil
+}
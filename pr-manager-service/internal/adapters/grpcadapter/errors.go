@@ -0,0 +1,79 @@
+package grpcadapter
+
+import (
+	"database/sql"
+	"errors"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reported in google.rpc.ErrorInfo, reasons match the HTTP error codes
+const errorDomain = "pr-manager-service"
+
+const (
+	errorCodeTeamExists  = "TEAM_EXISTS"
+	errorCodePrExists    = "PR_EXISTS"
+	errorCodePrMerged    = "PR_MERGED"
+	errorCodeNotAssigned = "NOT_ASSIGNED"
+	errorCodeNoCandidate = "NO_CANDIDATE"
+	errorCodeNotFound    = "NOT_FOUND"
+	errorCodeValidation  = "VALIDATION"
+	errorCodeInternal    = "INTERNAL_ERROR"
+	errorCodeNotConfig   = "NOT_CONFIGURED"
+	errorCodeStale       = "STALE_VERSION"
+)
+
+func newStatusError(code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// Maps usecase errors to gRPC statuses
+func mapError(err error) error {
+	switch {
+	case usecase.IsValidationError(err):
+		return newStatusError(codes.InvalidArgument, errorCodeValidation, err.Error())
+
+	case errors.Is(err, usecase.ErrTeamAlreadyExists):
+		return newStatusError(codes.AlreadyExists, errorCodeTeamExists, err.Error())
+
+	case errors.Is(err, usecase.ErrPullRequestAlreadyExists):
+		return newStatusError(codes.AlreadyExists, errorCodePrExists, err.Error())
+
+	case errors.Is(err, usecase.ErrStaleVersion):
+		return newStatusError(codes.Aborted, errorCodeStale, err.Error())
+
+	case errors.Is(err, domain.ErrEditMergedPR):
+		return newStatusError(codes.FailedPrecondition, errorCodePrMerged, err.Error())
+
+	case errors.Is(err, usecase.ErrReviewerNotAssigned):
+		return newStatusError(codes.FailedPrecondition, errorCodeNotAssigned, err.Error())
+
+	case errors.Is(err, usecase.ErrNoCandidateInTeam),
+		errors.Is(err, domain.ErrNoAvailableCandidates):
+		return newStatusError(codes.FailedPrecondition, errorCodeNoCandidate, err.Error())
+
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, usecase.ErrNotFound),
+		errors.Is(err, usecase.ErrIdentityNotMapped):
+		return newStatusError(codes.NotFound, errorCodeNotFound, err.Error())
+
+	case errors.Is(err, usecase.ErrNotConfigured):
+		return newStatusError(codes.Unimplemented, errorCodeNotConfig, err.Error())
+
+	default:
+		return newStatusError(codes.Internal, errorCodeInternal, "internal error")
+	}
+}
@@ -0,0 +1,193 @@
+package grpcadapter
+
+import (
+	"context"
+	"time"
+
+	pb "pr-manager-service/internal/adapters/grpcadapter/prmanagerv1"
+	"pr-manager-service/internal/usecase"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Teams
+
+func (h *GRPCHandler) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.CreateTeamResponse, error) {
+	team := req.GetTeam()
+	in := usecase.CreateTeamInput{
+		TeamName: team.GetTeamName(),
+		Members:  make([]usecase.TeamMemberDTO, 0, len(team.GetMembers())),
+	}
+	for _, m := range team.GetMembers() {
+		in.Members = append(in.Members, usecase.TeamMemberDTO{
+			UserId:   m.GetUserId(),
+			UserName: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		})
+	}
+
+	out, err := h.svc.CreateTeam(ctx, in)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.CreateTeamResponse{
+		Team: mapTeamToProto(out.TeamName, out.Members),
+	}, nil
+}
+
+func (h *GRPCHandler) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	out, err := h.svc.GetTeam(ctx, usecase.GetTeamInput{TeamName: req.GetTeamName()})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.GetTeamResponse{
+		Team: mapTeamToProto(out.TeamName, out.Members),
+	}, nil
+}
+
+// Users
+
+func (h *GRPCHandler) SetIsActive(ctx context.Context, req *pb.SetIsActiveRequest) (*pb.SetIsActiveResponse, error) {
+	out, err := h.svc.SetIsActive(ctx, usecase.SetIsActiveInput{
+		UserId:   req.GetUserId(),
+		IsActive: req.GetIsActive(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.SetIsActiveResponse{
+		User: &pb.User{
+			UserId:   out.UserId,
+			Username: out.UserName,
+			TeamName: out.TeamName,
+			IsActive: out.IsActive,
+		},
+	}, nil
+}
+
+func (h *GRPCHandler) GetUserReviews(ctx context.Context, req *pb.GetUserReviewsRequest) (*pb.GetUserReviewsResponse, error) {
+	// only admin can get reviews for other users
+	if err := requireSelfOrAdmin(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	out, err := h.svc.GetUserReviews(ctx, usecase.GetUserReviewsInput{UserId: req.GetUserId()})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	prs := make([]*pb.PullRequestShort, 0, len(out.PullRequests))
+	for _, pr := range out.PullRequests {
+		prs = append(prs, &pb.PullRequestShort{
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			Status:          mapStatusToProto(pr.Status),
+		})
+	}
+
+	return &pb.GetUserReviewsResponse{
+		UserId:       out.UserId,
+		PullRequests: prs,
+	}, nil
+}
+
+func (h *GRPCHandler) StreamUserReviews(req *pb.StreamUserReviewsRequest, stream pb.PRManagerService_StreamUserReviewsServer) error {
+	ctx := stream.Context()
+
+	userId := req.GetUserId()
+	if userId == "" {
+		if info, ok := authFromContext(ctx); ok {
+			userId = info.UserId
+		}
+	}
+
+	// only admin can watch the queue of other users
+	if err := requireSelfOrAdmin(ctx, userId); err != nil {
+		return err
+	}
+
+	events, err := h.svc.SubscribeReviewStream(ctx, usecase.SubscribeReviewStreamInput{UserId: userId})
+	if err != nil {
+		return mapError(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.streamsCtx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			err := stream.Send(&pb.StreamUserReviewsResponse{
+				Kind:            mapReviewEventKindToProto(ev.Kind),
+				PullRequestId:   ev.PullRequestId,
+				PullRequestName: ev.PullRequestName,
+				AuthorId:        ev.AuthorId,
+				OccurredAt:      timestamppb.New(ev.OccurredAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Pull requests
+
+func (h *GRPCHandler) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.CreatePullRequestResponse, error) {
+	out, err := h.svc.CreatePullRequest(ctx, usecase.CreatePullRequestInput{
+		PullRequestId:   req.GetPullRequestId(),
+		PullRequestName: req.GetPullRequestName(),
+		AuthorId:        req.GetAuthorId(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.CreatePullRequestResponse{Pr: mapPullRequestToProto(out.PR)}, nil
+}
+
+func (h *GRPCHandler) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.MergePullRequestResponse, error) {
+	out, err := h.svc.MergePullRequest(ctx, usecase.MergePullRequestInput{
+		PullRequestId:   req.GetPullRequestId(),
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.MergePullRequestResponse{Pr: mapPullRequestToProto(out.PR)}, nil
+}
+
+func (h *GRPCHandler) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	out, err := h.svc.ReassignReviewer(ctx, usecase.ReassignReviewerInput{
+		PullRequestId:   req.GetPullRequestId(),
+		OldUserId:       req.GetOldUserId(),
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &pb.ReassignReviewerResponse{
+		Pr:         mapPullRequestToProto(out.PR),
+		ReplacedBy: out.ReplacedBy,
+	}, nil
+}
+
+// Stats
+
+func (h *GRPCHandler) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	return &pb.GetStatsResponse{
+		Service: h.appName,
+		Version: h.version,
+		Time:    timestamppb.New(time.Now().UTC()),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package grpcadapter
+
+import (
+	pb "pr-manager-service/internal/adapters/grpcadapter/prmanagerv1"
+	"pr-manager-service/internal/usecase"
+)
+
+func mapTeamToProto(teamName string, members []usecase.TeamMemberDTO) *pb.Team {
+	result := make([]*pb.TeamMember, 0, len(members))
+	for _, m := range members {
+		result = append(result, &pb.TeamMember{
+			UserId:   m.UserId,
+			Username: m.UserName,
+			IsActive: m.IsActive,
+		})
+	}
+	return &pb.Team{
+		TeamName: teamName,
+		Members:  result,
+	}
+}
+
+func mapPullRequestToProto(pr usecase.PullRequestDTO) *pb.PullRequest {
+	return &pb.PullRequest{
+		PullRequestId:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorId,
+		Status:            mapStatusToProto(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		Version:           pr.Version,
+	}
+}
+
+func mapStatusToProto(status string) pb.PullRequestStatus {
+	switch status {
+	case "OPEN":
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case "MERGED":
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func mapReviewEventKindToProto(kind string) pb.ReviewEventKind {
+	switch kind {
+	case usecase.ReviewStreamAssign:
+		return pb.ReviewEventKind_REVIEW_EVENT_KIND_ASSIGN
+	case usecase.ReviewStreamUnassign:
+		return pb.ReviewEventKind_REVIEW_EVENT_KIND_UNASSIGN
+	case usecase.ReviewStreamMerge:
+		return pb.ReviewEventKind_REVIEW_EVENT_KIND_MERGE
+	default:
+		return pb.ReviewEventKind_REVIEW_EVENT_KIND_UNSPECIFIED
+	}
+}
@@ -0,0 +1,61 @@
+package grpcadapter
+
+import (
+	"context"
+
+	pb "pr-manager-service/internal/adapters/grpcadapter/prmanagerv1"
+	"pr-manager-service/internal/usecase"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type GRPCHandler struct {
+	pb.UnimplementedPRManagerServiceServer
+
+	svc     *usecase.Service
+	appName string
+	version string
+
+	// streams are closed when it is done, so graceful stop does not wait for them
+	streamsCtx context.Context
+}
+
+// ServerOption configures optional parts of the gRPC handler
+type ServerOption func(*GRPCHandler)
+
+// WithStreamsContext bounds the lifetime of streaming calls
+func WithStreamsContext(ctx context.Context) ServerOption {
+	return func(h *GRPCHandler) {
+		h.streamsCtx = ctx
+	}
+}
+
+func NewGRPCHandler(svc *usecase.Service, appName, version string, opts ...ServerOption) *GRPCHandler {
+	h := &GRPCHandler{
+		svc:        svc,
+		appName:    appName,
+		version:    version,
+		streamsCtx: context.Background(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// NewServer creates a gRPC server with the PR manager and health services
+func NewServer(svc *usecase.Service, appName, version string, opts ...ServerOption) *grpc.Server {
+	h := NewGRPCHandler(svc, appName, version, opts...)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(streamAuthInterceptor),
+	)
+
+	pb.RegisterPRManagerServiceServer(server, h)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	return server
+}
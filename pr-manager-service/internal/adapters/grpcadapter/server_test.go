@@ -0,0 +1,357 @@
+package grpcadapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/adapters/eventbroker"
+	pb "pr-manager-service/internal/adapters/grpcadapter/prmanagerv1"
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeStore keeps a single team with its pull requests in memory
+type fakeStore struct {
+	mu    sync.Mutex
+	users map[string]domain.User
+	prs   map[string]*domain.PullRequest
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users: map[string]domain.User{
+			"u1": {UserId: "u1", UserName: "Alice", IsActive: true},
+			"u2": {UserId: "u2", UserName: "Bob", IsActive: true},
+			"u3": {UserId: "u3", UserName: "Charlie", IsActive: true},
+		},
+		prs: map[string]*domain.PullRequest{},
+	}
+}
+
+func (f *fakeStore) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetUser(ctx context.Context, userId string) (*domain.User, error) {
+	u, ok := f.users[userId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &u, nil
+}
+
+func (f *fakeStore) SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetTeamName(ctx context.Context, userId string) (string, error) {
+	return "payments", nil
+}
+
+func (f *fakeStore) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pr.Version = 1
+	f.prs[pr.PullRequestId] = pr
+	return nil
+}
+
+func (f *fakeStore) GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pr, ok := f.prs[prId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return pr, nil
+}
+
+func (f *fakeStore) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (*domain.PullRequest, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pr, ok := f.prs[prId]
+	if !ok {
+		return nil, false, sql.ErrNoRows
+	}
+	if expectedVersion != 0 && pr.Version != expectedVersion {
+		return nil, false, usecase.ErrStaleVersion
+	}
+	merged := pr.StatusId != 2
+	if merged {
+		pr.StatusId = 2
+		pr.Version++
+	}
+	result := *pr
+	return &result, merged, nil
+}
+
+func (f *fakeStore) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []domain.PullRequest
+	for _, pr := range f.prs {
+		for _, r := range pr.AssignedReviewers {
+			if r == userId {
+				result = append(result, *pr)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	return []domain.User{f.users["u1"], f.users["u2"], f.users["u3"]}, nil
+}
+
+type noopLogger struct{}
+
+func (l *noopLogger) Debug(string, map[string]any) {}
+func (l *noopLogger) Info(string, map[string]any)  {}
+func (l *noopLogger) Warn(string, map[string]any)  {}
+func (l *noopLogger) Error(string, map[string]any) {}
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) IncTeamCreated()                     {}
+func (m *noopMetrics) IncUserActivated()                   {}
+func (m *noopMetrics) IncUserDeactivated()                 {}
+func (m *noopMetrics) IncPullRequestCreated()              {}
+func (m *noopMetrics) IncPullRequestMerged()               {}
+func (m *noopMetrics) IncPullRequestReassigned()           {}
+func (m *noopMetrics) SetWorkload(*domain.Workload)        {}
+func (m *noopMetrics) IncBusinessOperation(string, string) {}
+
+func newTestClient(t *testing.T) pb.PRManagerServiceClient {
+	t.Helper()
+
+	store := newFakeStore()
+	svc := usecase.NewService(store, store, store, &noopLogger{}, &noopMetrics{},
+		usecase.WithEventBroker(eventbroker.NewLocalBroker()),
+	)
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(svc, "test", "test")
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewPRManagerServiceClient(conn)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func errorReason(t *testing.T, err error) string {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	req := &pb.CreatePullRequestRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1"}
+
+	_, err := client.CreatePullRequest(ctx, req)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without token, got %v", err)
+	}
+
+	_, err = client.CreatePullRequest(withToken(ctx, "user:u1"), req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for user token, got %v", err)
+	}
+
+	_, err = client.GetUserReviews(withToken(ctx, "user:u1"), &pb.GetUserReviewsRequest{UserId: "u2"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for reviews of other user, got %v", err)
+	}
+
+	stats, err := client.GetStats(ctx, &pb.GetStatsRequest{})
+	if err != nil || stats.GetService() != "test" {
+		t.Fatalf("expected public stats, got %v, %v", stats, err)
+	}
+}
+
+func TestPullRequestLifecycle(t *testing.T) {
+	client := newTestClient(t)
+	ctx := withToken(context.Background(), "admin:u1")
+
+	created, err := client.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	pr := created.GetPr()
+	if pr.GetStatus() != pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN || len(pr.GetAssignedReviewers()) != 2 {
+		t.Fatalf("unexpected pull request: %v", pr)
+	}
+
+	reviewer := pr.GetAssignedReviewers()[0]
+	reviews, err := client.GetUserReviews(withToken(context.Background(), "user:"+reviewer), &pb.GetUserReviewsRequest{UserId: reviewer})
+	if err != nil {
+		t.Fatalf("get reviews: %v", err)
+	}
+	if len(reviews.GetPullRequests()) != 1 || reviews.GetPullRequests()[0].GetPullRequestId() != "pr-1" {
+		t.Fatalf("unexpected reviews: %v", reviews)
+	}
+
+	_, err = client.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{PullRequestId: "pr-2"})
+	if status.Code(err) != codes.InvalidArgument || errorReason(t, err) != errorCodeValidation {
+		t.Fatalf("expected InvalidArgument VALIDATION, got %v", err)
+	}
+
+	_, err = client.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{PullRequestId: "missing", OldUserId: "u2"})
+	if status.Code(err) != codes.NotFound || errorReason(t, err) != errorCodeNotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestMergePullRequest_ExpectedVersion(t *testing.T) {
+	client := newTestClient(t)
+	ctx := withToken(context.Background(), "admin:u1")
+
+	created, err := client.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.GetPr().GetVersion() != 1 {
+		t.Fatalf("expected version 1, got %d", created.GetPr().GetVersion())
+	}
+
+	_, err = client.MergePullRequest(ctx, &pb.MergePullRequestRequest{PullRequestId: "pr-1", ExpectedVersion: 2})
+	if status.Code(err) != codes.Aborted || errorReason(t, err) != errorCodeStale {
+		t.Fatalf("expected Aborted STALE_VERSION, got %v", err)
+	}
+
+	merged, err := client.MergePullRequest(ctx, &pb.MergePullRequestRequest{PullRequestId: "pr-1", ExpectedVersion: 1})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	pr := merged.GetPr()
+	if pr.GetStatus() != pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED || pr.GetVersion() != 2 {
+		t.Fatalf("expected a merged pull request with version 2, got %v", pr)
+	}
+
+	_, err = client.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{
+		PullRequestId:   "pr-1",
+		OldUserId:       pr.GetAssignedReviewers()[0],
+		ExpectedVersion: 1,
+	})
+	if status.Code(err) != codes.Aborted || errorReason(t, err) != errorCodeStale {
+		t.Fatalf("expected Aborted STALE_VERSION, got %v", err)
+	}
+}
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{usecase.ErrTeamNameRequired, codes.InvalidArgument, errorCodeValidation},
+		{usecase.ErrTeamAlreadyExists, codes.AlreadyExists, errorCodeTeamExists},
+		{usecase.ErrPullRequestAlreadyExists, codes.AlreadyExists, errorCodePrExists},
+		{domain.ErrEditMergedPR, codes.FailedPrecondition, errorCodePrMerged},
+		{usecase.ErrReviewerNotAssigned, codes.FailedPrecondition, errorCodeNotAssigned},
+		{domain.ErrNoAvailableCandidates, codes.FailedPrecondition, errorCodeNoCandidate},
+		{sql.ErrNoRows, codes.NotFound, errorCodeNotFound},
+		{usecase.ErrNotConfigured, codes.Unimplemented, errorCodeNotConfig},
+		{fmt.Errorf("connection refused"), codes.Internal, errorCodeInternal},
+	}
+	for _, tt := range tests {
+		err := mapError(tt.err)
+		if status.Code(err) != tt.wantCode || errorReason(t, err) != tt.wantReason {
+			t.Errorf("mapError(%v) = %v, want %v %s", tt.err, err, tt.wantCode, tt.wantReason)
+		}
+	}
+}
+
+func TestStreamUserReviews(t *testing.T) {
+	client := newTestClient(t)
+	admin := withToken(context.Background(), "admin:u1")
+
+	ctx, cancel := context.WithTimeout(withToken(context.Background(), "user:u2"), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamUserReviews(ctx, &pb.StreamUserReviewsRequest{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+
+	received := make(chan *pb.StreamUserReviewsResponse, 1)
+	go func() {
+		ev, err := stream.Recv()
+		if err == nil {
+			received <- ev
+		}
+	}()
+
+	// the subscription is registered asynchronously, so create pull requests until one arrives
+	for i := 0; ; i++ {
+		_, err := client.CreatePullRequest(admin, &pb.CreatePullRequestRequest{
+			PullRequestId:   fmt.Sprintf("pr-%d", i),
+			PullRequestName: "Add search",
+			AuthorId:        "u1",
+		})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		select {
+		case ev := <-received:
+			if ev.GetKind() != pb.ReviewEventKind_REVIEW_EVENT_KIND_ASSIGN || ev.GetAuthorId() != "u1" {
+				t.Fatalf("unexpected event: %v", ev)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatalf("no event received")
+		}
+	}
+}
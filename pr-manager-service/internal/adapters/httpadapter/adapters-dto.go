@@ -23,6 +23,13 @@ type userJSON struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type reviewStreamEventJSON struct {
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorId        string `json:"author_id"`
+	OccurredAt      string `json:"occurred_at"`
+}
+
 type pullRequestCreateJSON struct {
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
@@ -53,6 +60,54 @@ type pullRequestShortJSON struct {
 	Status          string `json:"status"`
 }
 
+type webhookSubscriptionCreateJSON struct {
+	Url        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+type webhookSubscriptionIdJSON struct {
+	Id int64 `json:"id"`
+}
+
+type webhookSubscriptionJSON struct {
+	Id         int64    `json:"id"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+}
+
+type identityJSON struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserId   string `json:"user_id"`
+}
+
+type chatHandleJSON struct {
+	UserId   string `json:"user_id"`
+	Provider string `json:"provider"`
+	Handle   string `json:"handle"`
+}
+
+type emailSubscriptionJSON struct {
+	UserId        string `json:"user_id"`
+	Email         string `json:"email"`
+	DigestEnabled bool   `json:"digest_enabled"`
+}
+
+type rosterUserChangeJSON struct {
+	UserId      string `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	Username    string `json:"username"`
+	OldIsActive bool   `json:"old_is_active"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type rosterMembershipJSON struct {
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
 // ErrorResponse по OpenAPI.
 
 type errorBodyJSON struct {
@@ -92,6 +147,69 @@ type reassignResponseJSON struct {
 	ReplacedBy string          `json:"replaced_by"`
 }
 
+type webhookSubscriptionResponseJSON struct {
+	Subscription webhookSubscriptionJSON `json:"subscription"`
+}
+
+type webhookSubscriptionsResponseJSON struct {
+	Subscriptions []webhookSubscriptionJSON `json:"subscriptions"`
+}
+
+type identityResponseJSON struct {
+	Identity identityJSON `json:"identity"`
+}
+
+type chatHandleResponseJSON struct {
+	ChatHandle chatHandleJSON `json:"chat_handle"`
+}
+
+type emailSubscriptionResponseJSON struct {
+	EmailSubscription emailSubscriptionJSON `json:"email_subscription"`
+}
+
+type unsubscribeResponseJSON struct {
+	UserId        string `json:"user_id"`
+	DigestEnabled bool   `json:"digest_enabled"`
+}
+
+type providerEventResponseJSON struct {
+	Result        string `json:"result"`
+	PullRequestId string `json:"pull_request_id,omitempty"`
+}
+
+type importRosterResponseJSON struct {
+	DryRun           bool                   `json:"dry_run"`
+	TeamsCreated     []string               `json:"teams_created"`
+	UsersCreated     []teamMemberJSON       `json:"users_created"`
+	UsersUpdated     []rosterUserChangeJSON `json:"users_updated"`
+	MembershipsAdded []rosterMembershipJSON `json:"memberships_added"`
+}
+
+type restoreSnapshotResponseJSON struct {
+	Restored restoredSnapshotJSON `json:"restored"`
+}
+
+type restoredSnapshotJSON struct {
+	SchemaVersion int64 `json:"schema_version"`
+	Teams         int   `json:"teams"`
+	Users         int   `json:"users"`
+	Memberships   int   `json:"memberships"`
+	PullRequests  int   `json:"pull_requests"`
+	Assignments   int   `json:"assignments"`
+}
+
 type healthResponseJSON struct {
 	Status string `json:"status"`
 }
+
+type dependencyStatusJSON struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type readinessResponseJSON struct {
+	Status string                 `json:"status"`
+	Checks []dependencyStatusJSON `json:"checks,omitempty"`
+}
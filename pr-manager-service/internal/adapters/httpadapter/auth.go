@@ -1,60 +1,38 @@
 package httpadapter
 
 import (
-	"errors"
 	"net/http"
-	"strings"
-)
 
-type authInfo struct {
-	UserId  string
-	IsAdmin bool
-}
+	"pr-manager-service/internal/adapters/authtoken"
 
-var (
-	errNoAuthHeader   = errors.New("missing Authorization header")
-	errInvalidAuthFmt = errors.New("invalid Authorization header format")
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
 )
 
+type authInfo = authtoken.Info
+
 // Authorization: Bearer admin:<user_id>
 // Authorization: Bearer user:<user_id>
 func parseAuthHeader(r *http.Request) (*authInfo, error) {
-	header := r.Header.Get("Authorization")
-	if header == "" {
-		return nil, errNoAuthHeader
-	}
-
-	parts := strings.SplitN(header, " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-		return nil, errInvalidAuthFmt
-	}
-
-	token := parts[1]
-	tokenParts := strings.SplitN(token, ":", 2)
-	if len(tokenParts) != 2 {
-		return nil, errInvalidAuthFmt
-	}
-
-	role := tokenParts[0]
-	userId := tokenParts[1]
-	if userId == "" {
-		return nil, errInvalidAuthFmt
-	}
-
-	info := &authInfo{
-		UserId: userId,
-	}
+	return authtoken.Parse(r.Header.Get("Authorization"))
+}
 
-	switch role {
-	case "admin":
-		info.IsAdmin = true
-	case "user":
-		info.IsAdmin = false
-	default:
-		return nil, errInvalidAuthFmt
+// Identifies the caller of a token, an admin and a user with the same id are different callers
+func authScope(info *authInfo) string {
+	if info.IsAdmin {
+		return "admin:" + info.UserId
 	}
+	return "user:" + info.UserId
+}
 
-	return info, nil
+// UserContext puts the user id of a well-formed Authorization header into
+// the request context for logging, handlers still check the role
+func UserContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info, err := parseAuthHeader(r); err == nil {
+			r = r.WithContext(kitlogger.ContextWithCallerID(r.Context(), info.UserId))
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 func requireAdmin(w http.ResponseWriter, r *http.Request) (*authInfo, bool) {
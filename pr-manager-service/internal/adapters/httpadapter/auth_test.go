@@ -0,0 +1,40 @@
+package httpadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
+)
+
+func TestUserContext(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "Bearer admin:u1", want: "u1"},
+		{header: "Bearer user:u2", want: "u2"},
+		{header: "", want: ""},
+		{header: "Basic dTE6cGFzcw==", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var got string
+			h := UserContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = kitlogger.CallerIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Fatalf("expected user id %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
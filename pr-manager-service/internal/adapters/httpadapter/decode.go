@@ -0,0 +1,88 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Decodes a single JSON object of the request body into v. Unknown fields,
+// trailing data and mistyped fields are rejected with 400 naming the field,
+// bodies over the LimitBody limit with 413.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	var maxBytesErr *http.MaxBytesError
+	if err == nil {
+		if err = dec.Decode(&struct{}{}); err == io.EOF {
+			return true
+		}
+		if !errors.As(err, &maxBytesErr) {
+			err = errTrailingData
+		}
+	}
+
+	if errors.As(err, &maxBytesErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, errorCodeValidation,
+			fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+		return false
+	}
+	writeError(w, http.StatusBadRequest, errorCodeValidation, decodeErrorMessage(err))
+	return false
+}
+
+var errTrailingData = errors.New("request body must contain a single JSON object")
+
+func decodeErrorMessage(err error) string {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, errTrailingData):
+		return err.Error()
+	case errors.Is(err, io.EOF):
+		return "request body is empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "invalid json: unexpected end of body"
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("invalid json at offset %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Sprintf("request body must be %s", jsonKind(typeErr.Type))
+		}
+		return fmt.Sprintf("field %q must be %s", typeErr.Field, jsonKind(typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no type for this error
+		return strings.TrimPrefix(err.Error(), "json: ")
+	default:
+		return "invalid json: " + err.Error()
+	}
+}
+
+// Names the JSON type of a Go type for error messages
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	default:
+		return "an object"
+	}
+}
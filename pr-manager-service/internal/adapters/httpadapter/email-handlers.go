@@ -0,0 +1,74 @@
+package httpadapter
+
+import (
+	"net/http"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// POST /users/setEmail
+func (h *HTTPHandler) handleSetEmailSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req emailSubscriptionJSON
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	in := usecase.SetEmailSubscriptionInput{
+		UserId:        req.UserId,
+		Email:         req.Email,
+		DigestEnabled: req.DigestEnabled,
+	}
+
+	out, err := h.svc.SetEmailSubscription(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := emailSubscriptionResponseJSON{
+		EmailSubscription: emailSubscriptionJSON{
+			UserId:        out.UserId,
+			Email:         out.Email,
+			DigestEnabled: out.DigestEnabled,
+		},
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GET|POST /email/unsubscribe?token=...
+// The token from the digest email is the only credential, POST serves
+// one-click unsubscribe from mail clients (RFC 8058).
+func (h *HTTPHandler) handleUnsubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	in := usecase.UnsubscribeEmailInput{
+		Token: r.URL.Query().Get("token"),
+	}
+
+	out, err := h.svc.UnsubscribeEmail(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := unsubscribeResponseJSON{
+		UserId:        out.UserId,
+		DigestEnabled: false,
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
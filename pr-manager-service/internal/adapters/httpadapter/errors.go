@@ -20,6 +20,12 @@ const (
 	errorCodeNotFound    = "NOT_FOUND"
 	errorCodeValidation  = "VALIDATION"
 	errorCodeInternal    = "INTERNAL_ERROR"
+	errorCodeNotConfig   = "NOT_CONFIGURED"
+	errorCodeNotEmpty    = "DATABASE_NOT_EMPTY"
+	errorCodeStale       = "STALE_VERSION"
+	errorCodeKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+	errorCodeInProgress  = "IDEMPOTENCY_IN_PROGRESS"
+	errorCodeRateLimited = "RATE_LIMITED"
 )
 
 // Write JSON to http response
@@ -42,12 +48,7 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 
 func writeMappedError(w http.ResponseWriter, err error) {
 	// Validation errors
-	if errors.Is(err, usecase.ErrTeamNameRequired) ||
-		errors.Is(err, usecase.ErrUserIdRequired) ||
-		errors.Is(err, usecase.ErrPullRequestIdRequired) ||
-		errors.Is(err, usecase.ErrPullRequestNameRequired) ||
-		errors.Is(err, usecase.ErrAuthorIdRequired) ||
-		errors.Is(err, usecase.ErrOldUserIdRequired) {
+	if usecase.IsValidationError(err) {
 		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
 		return
 	}
@@ -64,6 +65,12 @@ func writeMappedError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	// STALE_VERSION
+	if errors.Is(err, usecase.ErrStaleVersion) {
+		writeError(w, http.StatusPreconditionFailed, errorCodeStale, err.Error())
+		return
+	}
+
 	// PR_MERGED
 	if errors.Is(err, domain.ErrEditMergedPR) {
 		writeError(w, http.StatusConflict, errorCodePrMerged, err.Error())
@@ -85,11 +92,36 @@ func writeMappedError(w http.ResponseWriter, err error) {
 
 	// NOT_FOUND
 	if errors.Is(err, sql.ErrNoRows) ||
-		errors.Is(err, usecase.ErrNotFound) {
+		errors.Is(err, usecase.ErrNotFound) ||
+		errors.Is(err, usecase.ErrIdentityNotMapped) {
 		writeError(w, http.StatusNotFound, errorCodeNotFound, err.Error())
 		return
 	}
 
+	// DATABASE_NOT_EMPTY
+	if errors.Is(err, usecase.ErrDatabaseNotEmpty) {
+		writeError(w, http.StatusConflict, errorCodeNotEmpty, err.Error())
+		return
+	}
+
+	// IDEMPOTENCY_KEY_REUSED
+	if errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+		writeError(w, http.StatusUnprocessableEntity, errorCodeKeyReused, err.Error())
+		return
+	}
+
+	// IDEMPOTENCY_IN_PROGRESS
+	if errors.Is(err, usecase.ErrIdempotencyInProgress) {
+		writeError(w, http.StatusConflict, errorCodeInProgress, err.Error())
+		return
+	}
+
+	// NOT_CONFIGURED
+	if errors.Is(err, usecase.ErrNotConfigured) {
+		writeError(w, http.StatusNotImplemented, errorCodeNotConfig, err.Error())
+		return
+	}
+
 	// Other - internal
 	writeError(w, http.StatusInternalServerError, errorCodeInternal, "internal error")
 }
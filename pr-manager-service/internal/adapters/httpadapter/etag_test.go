@@ -0,0 +1,118 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr bool
+	}{
+		{header: "", want: 0},
+		{header: "*", want: 0},
+		{header: `"3"`, want: 3},
+		{header: ` "12" `, want: 12},
+		{header: `W/"3"`, wantErr: true},
+		{header: `"3", "4"`, wantErr: true},
+		{header: "3", wantErr: true},
+		{header: `"abc"`, wantErr: true},
+		{header: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			got, err := parseIfMatch(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPullRequestHandlers_IfMatch(t *testing.T) {
+	store := inmemory.NewStore()
+	teams := inmemory.NewTeamRepository(store)
+	err := teams.CreateTeam(context.Background(), "backend", []domain.User{
+		{UserId: "u1", UserName: "Alice", IsActive: true},
+		{UserId: "u2", UserName: "Bob", IsActive: true},
+		{UserId: "u3", UserName: "Carol", IsActive: true},
+		{UserId: "u4", UserName: "Dave", IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	svc := usecase.NewService(teams, inmemory.NewUserRepository(store), inmemory.NewPullRequestRepository(store),
+		&noopLogger{}, &noopMetrics{})
+	h := NewRouter(svc, "test", "test", withContract(t))
+
+	send := func(path, body, ifMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin:u1")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	errorCode := func(rec *httptest.ResponseRecorder) string {
+		t.Helper()
+		var resp errorResponseJSON
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		return resp.Error.Code
+	}
+
+	rec := send("/pullRequest/create", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`, "")
+	if rec.Code != http.StatusCreated || rec.Header().Get("ETag") != `"1"` {
+		t.Fatalf("create: expected 201 with ETag \"1\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	reassign := `{"pull_request_id":"pr-1","old_user_id":"u2"}`
+	rec = send("/pullRequest/reassign", reassign, `"1"`)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("reassign: expected 200 with ETag \"2\", got %d %q: %s", rec.Code, rec.Header().Get("ETag"), rec.Body)
+	}
+
+	// the second admin still holds version 1
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, `"1"`)
+	if rec.Code != http.StatusPreconditionFailed || errorCode(rec) != errorCodeStale {
+		t.Fatalf("merge with a stale ETag: expected 412 %s, got %d", errorCodeStale, rec.Code)
+	}
+
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, `W/"2"`)
+	if rec.Code != http.StatusBadRequest || errorCode(rec) != errorCodeValidation {
+		t.Fatalf("merge with a weak ETag: expected 400 %s, got %d", errorCodeValidation, rec.Code)
+	}
+
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, `"2"`)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"3"` {
+		t.Fatalf("merge: expected 200 with ETag \"3\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// without If-Match the last write wins as before
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"3"` {
+		t.Fatalf("repeated merge: expected 200 with ETag \"3\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
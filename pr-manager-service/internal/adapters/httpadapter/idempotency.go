@@ -0,0 +1,140 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"pr-manager-service/internal/usecase"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// Bodies of requests with an Idempotency-Key are read upfront for the fingerprint
+const maxIdempotentBodyBytes = 1 << 20
+
+// Headers saved with a response and replayed to repeated requests
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// Wraps a mutating handler, so requests repeated with the same Idempotency-Key
+// get the saved response instead of running again. Server errors are not
+// saved, the request can be retried with the same key.
+func (h *HTTPHandler) withIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" || r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+
+		// keys are scoped by caller, the handler rejects requests without a token
+		info, err := parseAuthHeader(r)
+		if err != nil {
+			next(w, r)
+			return
+		}
+		scope := authScope(info)
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || len(body) > maxIdempotentBodyBytes {
+			writeError(w, http.StatusRequestEntityTooLarge, errorCodeValidation, "request body is too large")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		in := usecase.BeginIdempotentRequestInput{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: requestFingerprint(r, body),
+		}
+		out, err := h.svc.BeginIdempotentRequest(r.Context(), in)
+		if err != nil {
+			writeMappedError(w, err)
+			return
+		}
+		if out.Replay != nil {
+			writeReplay(w, out.Replay)
+			return
+		}
+
+		// the outcome is saved even if the client went away meanwhile
+		ctx := context.WithoutCancel(r.Context())
+		completed := false
+		defer func() {
+			if !completed {
+				_ = h.svc.AbortIdempotentRequest(ctx, scope, key)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		resp := usecase.StoredResponseDTO{
+			StatusCode: rec.status,
+			Headers:    make(map[string]string),
+			Body:       rec.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				resp.Headers[name] = value
+			}
+		}
+		err = h.svc.CompleteIdempotentRequest(ctx, usecase.CompleteIdempotentRequestInput{
+			Scope:    scope,
+			Key:      key,
+			Response: resp,
+		})
+		completed = err == nil
+	}
+}
+
+// Identifies the request a key was first used with
+func requestFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	_, _ = io.WriteString(sum, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+	_, _ = sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func writeReplay(w http.ResponseWriter, resp *usecase.StoredResponseDTO) {
+	for name, value := range resp.Headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
+
+// Passes the response through and keeps a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
@@ -0,0 +1,104 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	store := inmemory.NewStore()
+	teams := inmemory.NewTeamRepository(store)
+	err := teams.CreateTeam(context.Background(), "backend", []domain.User{
+		{UserId: "u1", UserName: "Alice", IsActive: true},
+		{UserId: "u2", UserName: "Bob", IsActive: true},
+		{UserId: "u3", UserName: "Carol", IsActive: true},
+		{UserId: "u4", UserName: "Dave", IsActive: true},
+		{UserId: "u5", UserName: "Eve", IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	svc := usecase.NewService(teams, inmemory.NewUserRepository(store), inmemory.NewPullRequestRepository(store),
+		&noopLogger{}, &noopMetrics{},
+		usecase.WithIdempotency(inmemory.NewIdempotencyRepository(store), time.Hour, time.Minute),
+	)
+	h := NewRouter(svc, "test", "test", withContract(t))
+
+	send := func(path, body, token, key string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	create := `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`
+	first := send("/pullRequest/create", create, "admin:u1", "create-1")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", first.Code, first.Body)
+	}
+
+	// the retry gets the saved response instead of PR_EXISTS
+	retry := send("/pullRequest/create", create, "admin:u1", "create-1")
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retried create: expected the saved 201, got %d: %s", retry.Code, retry.Body)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || retry.Header().Get("ETag") != `"1"` {
+		t.Fatalf("retried create: unexpected headers %v", retry.Header())
+	}
+
+	var created pullRequestResponseJSON
+	if err := json.Unmarshal(first.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+	reassign := `{"pull_request_id":"pr-1","old_user_id":"` + created.PR.AssignedReviewers[0] + `"}`
+
+	first = send("/pullRequest/reassign", reassign, "admin:u1", "reassign-1")
+	if first.Code != http.StatusOK {
+		t.Fatalf("reassign: expected 200, got %d: %s", first.Code, first.Body)
+	}
+	for i := 0; i < 3; i++ {
+		retry = send("/pullRequest/reassign", reassign, "admin:u1", "reassign-1")
+		if retry.Code != http.StatusOK || retry.Body.String() != first.Body.String() {
+			t.Fatalf("retried reassign picked another reviewer: %s, then %s", first.Body, retry.Body)
+		}
+	}
+	reviews, err := svc.GetUserReviews(context.Background(), usecase.GetUserReviewsInput{UserId: created.PR.AssignedReviewers[0]})
+	if err != nil || len(reviews.PullRequests) != 0 {
+		t.Fatalf("the old reviewer must be replaced once, got %+v, %v", reviews, err)
+	}
+
+	// the same key with another body
+	rec := send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, "admin:u1", "reassign-1")
+	var resp errorResponseJSON
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || resp.Error.Code != errorCodeKeyReused {
+		t.Fatalf("reused key: expected 422 %s, got %d %s", errorCodeKeyReused, rec.Code, resp.Error.Code)
+	}
+
+	// keys are scoped by caller
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, "admin:u2", "reassign-1")
+	if rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("another admin: expected a fresh 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = send("/pullRequest/merge", `{"pull_request_id":"pr-1"}`, "admin:u1", "bad\tkey")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid key: expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,168 @@
+package httpadapter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// Providers send payloads of a few hundred KB at most
+const maxProviderPayloadBytes = 5 << 20
+
+// POST /integrations/github/webhook
+func (h *HTTPHandler) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxProviderPayloadBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, "unable to read body")
+		return
+	}
+
+	if !verifyGitHubSignature(h.githubWebhookSecret, r.Header.Get("X-Hub-Signature-256"), body) {
+		writeError(w, http.StatusUnauthorized, errorCodeNotFound, "invalid signature")
+		return
+	}
+
+	var in usecase.ProviderPullRequestEventInput
+	switch r.Header.Get("X-GitHub-Event") {
+	case "pull_request":
+		in, err = parseGitHubPullRequestEvent(body)
+	case "pull_request_review":
+		in, err = parseGitHubPullRequestReviewEvent(body)
+	default:
+		// ping and other events are acknowledged without processing
+		writeJSON(w, http.StatusOK, providerEventResponseJSON{Result: usecase.ProviderEventIgnored})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+		return
+	}
+
+	h.handleProviderEvent(w, r, in)
+}
+
+// POST /integrations/gitlab/webhook
+func (h *HTTPHandler) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !verifyGitLabToken(h.gitlabWebhookToken, r.Header.Get("X-Gitlab-Token")) {
+		writeError(w, http.StatusUnauthorized, errorCodeNotFound, "invalid token")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxProviderPayloadBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, "unable to read body")
+		return
+	}
+
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		writeJSON(w, http.StatusOK, providerEventResponseJSON{Result: usecase.ProviderEventIgnored})
+		return
+	}
+
+	in, err := parseGitLabMergeRequestEvent(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+		return
+	}
+
+	h.handleProviderEvent(w, r, in)
+}
+
+func (h *HTTPHandler) handleProviderEvent(w http.ResponseWriter, r *http.Request, in usecase.ProviderPullRequestEventInput) {
+	out, err := h.svc.HandleProviderPullRequestEvent(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := providerEventResponseJSON{
+		Result:        out.Result,
+		PullRequestId: in.PullRequestId,
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// POST /integrations/identities/set
+func (h *HTTPHandler) handleSetIdentity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req identityJSON
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	in := usecase.SetIdentityInput{
+		Provider: req.Provider,
+		Login:    req.Login,
+		UserId:   req.UserId,
+	}
+
+	out, err := h.svc.SetIdentity(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := identityResponseJSON{
+		Identity: identityJSON{
+			Provider: out.Provider,
+			Login:    out.Login,
+			UserId:   out.UserId,
+		},
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// X-Hub-Signature-256: sha256=<hex hmac of the body>
+func verifyGitHubSignature(secret, header string, body []byte) bool {
+	if secret == "" {
+		return false
+	}
+
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// X-Gitlab-Token carries the configured secret token as is
+func verifyGitLabToken(token, header string) bool {
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(header)) == 1
+}
@@ -0,0 +1,331 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"pr-manager-service/internal/adapters/webhookadapter"
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+const (
+	testGitHubSecret = "gh-secret"
+	testGitLabToken  = "gl-token"
+)
+
+// fakeStore keeps a single team with its pull requests in memory
+type fakeStore struct {
+	users      map[string]domain.User
+	team       string
+	prs        map[string]*domain.PullRequest
+	identities map[string]string
+	// first verdicts by <pull request id>/<user id>
+	verdicts map[string]bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users: map[string]domain.User{
+			"u1": {UserId: "u1", UserName: "Alice", IsActive: true},
+			"u2": {UserId: "u2", UserName: "Bob", IsActive: true},
+			"u3": {UserId: "u3", UserName: "Charlie", IsActive: true},
+		},
+		team: "payments",
+		prs:  map[string]*domain.PullRequest{},
+		identities: map[string]string{
+			"github/alice-gh":   "u1",
+			"github/bob-gh":     "u2",
+			"gitlab/alice-gl":   "u1",
+			"gitlab/charlie-gl": "u3",
+		},
+		verdicts: map[string]bool{},
+	}
+}
+
+func (f *fakeStore) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetUser(ctx context.Context, userId string) (*domain.User, error) {
+	u, ok := f.users[userId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &u, nil
+}
+
+func (f *fakeStore) SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetTeamName(ctx context.Context, userId string) (string, error) {
+	return f.team, nil
+}
+
+func (f *fakeStore) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error {
+	f.prs[pr.PullRequestId] = pr
+	return nil
+}
+
+func (f *fakeStore) GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	pr, ok := f.prs[prId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return pr, nil
+}
+
+func (f *fakeStore) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (*domain.PullRequest, bool, error) {
+	pr, ok := f.prs[prId]
+	if !ok {
+		return nil, false, sql.ErrNoRows
+	}
+	merged := pr.StatusId != 2
+	pr.StatusId = 2
+	return pr, merged, nil
+}
+
+func (f *fakeStore) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error {
+	panic("not used in this test")
+}
+
+func (f *fakeStore) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	return []domain.User{f.users["u1"], f.users["u2"], f.users["u3"]}, nil
+}
+
+func (f *fakeStore) SetIdentity(ctx context.Context, identity domain.Identity) error {
+	f.identities[identity.Provider+"/"+identity.Login] = identity.UserId
+	return nil
+}
+
+func (f *fakeStore) GetUserIdByLogin(ctx context.Context, provider, login string) (string, error) {
+	userId, ok := f.identities[provider+"/"+login]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return userId, nil
+}
+
+func (f *fakeStore) RecordFirstVerdict(ctx context.Context, prId, userId string) (bool, error) {
+	pr, ok := f.prs[prId]
+	if !ok || !slices.Contains(pr.AssignedReviewers, userId) || f.verdicts[prId+"/"+userId] {
+		return false, nil
+	}
+	f.verdicts[prId+"/"+userId] = true
+	return true, nil
+}
+
+type noopLogger struct{}
+
+func (l *noopLogger) Debug(string, map[string]any) {}
+func (l *noopLogger) Info(string, map[string]any)  {}
+func (l *noopLogger) Warn(string, map[string]any)  {}
+func (l *noopLogger) Error(string, map[string]any) {}
+
+type noopMetrics struct{}
+
+func (m *noopMetrics) IncTeamCreated()                     {}
+func (m *noopMetrics) IncUserActivated()                   {}
+func (m *noopMetrics) IncUserDeactivated()                 {}
+func (m *noopMetrics) IncPullRequestCreated()              {}
+func (m *noopMetrics) IncPullRequestMerged()               {}
+func (m *noopMetrics) IncPullRequestReassigned()           {}
+func (m *noopMetrics) SetWorkload(*domain.Workload)        {}
+func (m *noopMetrics) IncBusinessOperation(string, string) {}
+
+func newIntegrationTestRouter(t *testing.T, store *fakeStore) http.Handler {
+	svc := usecase.NewService(store, store, store, &noopLogger{}, &noopMetrics{},
+		usecase.WithIdentities(store),
+		usecase.WithReviewVerdicts(store),
+	)
+	return NewRouter(svc, "test", "test",
+		WithIntegrationSecrets(testGitHubSecret, testGitLabToken),
+		withContract(t),
+	)
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func replayGitHub(t *testing.T, h http.Handler, event, fixture string, sign bool) *httptest.ResponseRecorder {
+	t.Helper()
+	body := readFixture(t, fixture)
+	req := httptest.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if sign {
+		req.Header.Set("X-Hub-Signature-256", webhookadapter.Sign(testGitHubSecret, body))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func replayGitLab(t *testing.T, h http.Handler, fixture, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := readFixture(t, fixture)
+	req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/webhook", bytes.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeProviderResponse(t *testing.T, rec *httptest.ResponseRecorder) providerEventResponseJSON {
+	t.Helper()
+	var resp providerEventResponseJSON
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestGitHubWebhook_Lifecycle(t *testing.T) {
+	store := newFakeStore()
+	h := newIntegrationTestRouter(t, store)
+	prId := "github:acme/payments#42"
+
+	steps := []struct {
+		name       string
+		event      string
+		fixture    string
+		wantResult string
+		wantStatus int
+	}{
+		{name: "ping", event: "ping", fixture: "github/ping.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{name: "opened", event: "pull_request", fixture: "github/pull_request_opened.json", wantResult: usecase.ProviderEventCreated, wantStatus: 1},
+		{name: "redelivered opened", event: "pull_request", fixture: "github/pull_request_opened.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{name: "closed without merge", event: "pull_request", fixture: "github/pull_request_closed.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{name: "reopened", event: "pull_request", fixture: "github/pull_request_reopened.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{name: "review comment", event: "pull_request_review", fixture: "github/pull_request_review_commented.json", wantResult: usecase.ProviderEventIgnored},
+		{name: "approved", event: "pull_request_review", fixture: "github/pull_request_review_approved.json", wantResult: usecase.ProviderEventReviewed},
+		{name: "redelivered approved", event: "pull_request_review", fixture: "github/pull_request_review_approved.json", wantResult: usecase.ProviderEventIgnored},
+		{name: "merged", event: "pull_request", fixture: "github/pull_request_merged.json", wantResult: usecase.ProviderEventMerged, wantStatus: 2},
+	}
+
+	for _, step := range steps {
+		rec := replayGitHub(t, h, step.event, step.fixture, true)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", step.name, rec.Code, rec.Body.String())
+		}
+		resp := decodeProviderResponse(t, rec)
+		if resp.Result != step.wantResult {
+			t.Fatalf("%s: expected result %q, got %q", step.name, step.wantResult, resp.Result)
+		}
+
+		if step.event != "pull_request" {
+			continue
+		}
+		pr, ok := store.prs[prId]
+		if !ok {
+			t.Fatalf("%s: expected pr %s to exist", step.name, prId)
+		}
+		if pr.StatusId != step.wantStatus {
+			t.Fatalf("%s: expected status id %d, got %d", step.name, step.wantStatus, pr.StatusId)
+		}
+	}
+
+	if !store.verdicts[prId+"/u2"] {
+		t.Fatalf("expected the first verdict of u2 to be recorded")
+	}
+
+	pr := store.prs[prId]
+	if pr.AuthorId != "u1" {
+		t.Fatalf("expected author u1, got %s", pr.AuthorId)
+	}
+	if pr.PullRequestName != "Add search by merchant id" {
+		t.Fatalf("unexpected pr name %q", pr.PullRequestName)
+	}
+	for _, r := range pr.AssignedReviewers {
+		if r == "u1" {
+			t.Fatalf("author should not be in assigned reviewers")
+		}
+	}
+}
+
+func TestGitHubWebhook_InvalidSignature(t *testing.T) {
+	store := newFakeStore()
+	h := newIntegrationTestRouter(t, store)
+
+	rec := replayGitHub(t, h, "pull_request", "github/pull_request_opened.json", false)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rec.Code)
+	}
+	if len(store.prs) != 0 {
+		t.Fatalf("expected no pull requests to be created")
+	}
+}
+
+func TestGitHubWebhook_UnmappedAuthor(t *testing.T) {
+	store := newFakeStore()
+	delete(store.identities, "github/alice-gh")
+	h := newIntegrationTestRouter(t, store)
+
+	rec := replayGitHub(t, h, "pull_request", "github/pull_request_opened.json", true)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestGitLabWebhook_Lifecycle(t *testing.T) {
+	store := newFakeStore()
+	h := newIntegrationTestRouter(t, store)
+	prId := "gitlab:acme/payments!7"
+
+	rec := replayGitLab(t, h, "gitlab/merge_request_open.json", "wrong-token")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 for wrong token, got %d", rec.Code)
+	}
+
+	steps := []struct {
+		fixture    string
+		wantResult string
+		wantStatus int
+	}{
+		{fixture: "gitlab/merge_request_open.json", wantResult: usecase.ProviderEventCreated, wantStatus: 1},
+		{fixture: "gitlab/merge_request_update.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{fixture: "gitlab/merge_request_approval.json", wantResult: usecase.ProviderEventReviewed, wantStatus: 1},
+		{fixture: "gitlab/merge_request_approval.json", wantResult: usecase.ProviderEventIgnored, wantStatus: 1},
+		{fixture: "gitlab/merge_request_merge.json", wantResult: usecase.ProviderEventMerged, wantStatus: 2},
+	}
+
+	for _, step := range steps {
+		rec := replayGitLab(t, h, step.fixture, testGitLabToken)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", step.fixture, rec.Code, rec.Body.String())
+		}
+		resp := decodeProviderResponse(t, rec)
+		if resp.Result != step.wantResult {
+			t.Fatalf("%s: expected result %q, got %q", step.fixture, step.wantResult, resp.Result)
+		}
+		if resp.PullRequestId != prId {
+			t.Fatalf("%s: expected pull_request_id %q, got %q", step.fixture, prId, resp.PullRequestId)
+		}
+		if store.prs[prId].StatusId != step.wantStatus {
+			t.Fatalf("%s: expected status id %d, got %d", step.fixture, step.wantStatus, store.prs[prId].StatusId)
+		}
+	}
+}
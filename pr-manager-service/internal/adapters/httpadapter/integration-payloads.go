@@ -0,0 +1,154 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+// Only fields used by the service are decoded from provider payloads
+
+type githubPullRequestEventJSON struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type githubPullRequestReviewEventJSON struct {
+	Action string `json:"action"`
+	Review struct {
+		State string `json:"state"`
+		User  struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"review"`
+	PullRequest struct {
+		Number int64  `json:"number"`
+		Title  string `json:"title"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type gitlabMergeRequestEventJSON struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		Iid    int64  `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// github:<owner>/<repo>#<number>
+func githubPullRequestId(repoFullName string, number int64) string {
+	return fmt.Sprintf("%s:%s#%d", domain.ProviderGitHub, repoFullName, number)
+}
+
+// gitlab:<namespace>/<project>!<iid>
+func gitlabPullRequestId(projectPath string, iid int64) string {
+	return fmt.Sprintf("%s:%s!%d", domain.ProviderGitLab, projectPath, iid)
+}
+
+func parseGitHubPullRequestEvent(body []byte) (usecase.ProviderPullRequestEventInput, error) {
+	var ev githubPullRequestEventJSON
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return usecase.ProviderPullRequestEventInput{}, err
+	}
+
+	action := ev.Action
+	switch ev.Action {
+	case "opened":
+		action = usecase.ProviderActionOpened
+	case "reopened":
+		action = usecase.ProviderActionReopened
+	case "closed":
+		action = usecase.ProviderActionClosed
+		if ev.PullRequest.Merged {
+			action = usecase.ProviderActionMerged
+		}
+	}
+
+	return usecase.ProviderPullRequestEventInput{
+		Provider:        domain.ProviderGitHub,
+		Action:          action,
+		PullRequestId:   githubPullRequestId(ev.Repository.FullName, ev.Number),
+		PullRequestName: ev.PullRequest.Title,
+		AuthorLogin:     ev.PullRequest.User.Login,
+	}, nil
+}
+
+// Approvals and change requests are verdicts, comments are not
+func parseGitHubPullRequestReviewEvent(body []byte) (usecase.ProviderPullRequestEventInput, error) {
+	var ev githubPullRequestReviewEventJSON
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return usecase.ProviderPullRequestEventInput{}, err
+	}
+
+	action := ev.Action
+	if ev.Action == "submitted" && (ev.Review.State == "approved" || ev.Review.State == "changes_requested") {
+		action = usecase.ProviderActionReviewed
+	}
+
+	return usecase.ProviderPullRequestEventInput{
+		Provider:        domain.ProviderGitHub,
+		Action:          action,
+		PullRequestId:   githubPullRequestId(ev.Repository.FullName, ev.PullRequest.Number),
+		PullRequestName: ev.PullRequest.Title,
+		ReviewerLogin:   ev.Review.User.Login,
+	}, nil
+}
+
+// GitLab merge request hooks carry the acting user only, for "open" and
+// "reopen" this is the author of the merge request, for approvals the reviewer
+func parseGitLabMergeRequestEvent(body []byte) (usecase.ProviderPullRequestEventInput, error) {
+	var ev gitlabMergeRequestEventJSON
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return usecase.ProviderPullRequestEventInput{}, err
+	}
+
+	action := ev.ObjectAttributes.Action
+	switch ev.ObjectAttributes.Action {
+	case "open":
+		action = usecase.ProviderActionOpened
+	case "reopen":
+		action = usecase.ProviderActionReopened
+	case "close":
+		action = usecase.ProviderActionClosed
+	case "merge":
+		action = usecase.ProviderActionMerged
+	case "approval", "approved":
+		// "approval" is sent per approver, "approved" once the last one approves
+		action = usecase.ProviderActionReviewed
+	}
+
+	in := usecase.ProviderPullRequestEventInput{
+		Provider:        domain.ProviderGitLab,
+		Action:          action,
+		PullRequestId:   gitlabPullRequestId(ev.Project.PathWithNamespace, ev.ObjectAttributes.Iid),
+		PullRequestName: ev.ObjectAttributes.Title,
+	}
+	if action == usecase.ProviderActionReviewed {
+		in.ReviewerLogin = ev.User.Username
+	} else {
+		in.AuthorLogin = ev.User.Username
+	}
+	return in, nil
+}
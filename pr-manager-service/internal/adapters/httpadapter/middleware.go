@@ -0,0 +1,114 @@
+package httpadapter
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
+)
+
+// DefaultMaxBodyBytes bounds JSON request bodies unless WithMaxBodyBytes is given
+const DefaultMaxBodyBytes = 1 << 20
+
+// Middleware wraps a handler
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps next with the middlewares, the first one runs first
+func Chain(next http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+	return next
+}
+
+// Recover turns a panic of the handler into a 500 INTERNAL_ERROR response
+// and logs it with the stack trace. http.ErrAbortHandler is re-panicked,
+// handlers use it to drop the connection of a broken streaming response.
+func Recover(l kitlogger.ContextLoggerInterface) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pw := &panicWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				l.ErrorCtx(r.Context(), "panic in http handler", map[string]any{
+					"panic":       fmt.Sprint(rec),
+					"stack":       string(debug.Stack()),
+					"http.method": r.Method,
+					"http.path":   r.URL.Path,
+				})
+
+				// the client already got a part of the response, only
+				// dropping the connection tells it the response is broken
+				if pw.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+				writeError(w, http.StatusInternalServerError, errorCodeInternal, "internal error")
+			}()
+			next.ServeHTTP(pw, r)
+		})
+	}
+}
+
+// panicWriter remembers whether the response was started
+type panicWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *panicWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *panicWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach Flush of the underlying writer
+func (w *panicWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// LimitBody fails reads of bodies longer than maxBytes, decodeJSON
+// reports them with 413
+func LimitBody(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireJSON rejects bodies of another Content-Type with 415.
+// A body without Content-Type is read as JSON and gets the header, so
+// the contract check sees the same media type. An empty body is left
+// to the handler, which reports it as missing.
+func RequireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case r.ContentLength == 0:
+		case contentType == "":
+			r.Header.Set("Content-Type", "application/json")
+		default:
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+				writeError(w, http.StatusUnsupportedMediaType, errorCodeValidation, "Content-Type must be application/json")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,146 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+type panicLogger struct {
+	noopLogger
+	params map[string]any
+}
+
+func (l *panicLogger) DebugCtx(context.Context, string, map[string]any) {}
+func (l *panicLogger) InfoCtx(context.Context, string, map[string]any)  {}
+func (l *panicLogger) WarnCtx(context.Context, string, map[string]any)  {}
+
+func (l *panicLogger) ErrorCtx(_ context.Context, _ string, params map[string]any) {
+	l.params = params
+}
+
+func TestRecover(t *testing.T) {
+	l := &panicLogger{}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), Recover(l))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	var resp errorResponseJSON
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if rec.Code != http.StatusInternalServerError || resp.Error.Code != errorCodeInternal {
+		t.Fatalf("expected 500 INTERNAL_ERROR, got %d %+v", rec.Code, resp)
+	}
+	if l.params["panic"] != "boom" || !strings.Contains(l.params["stack"].(string), "TestRecover") {
+		t.Fatalf("expected the panic logged with a stack, got %v", l.params)
+	}
+}
+
+func TestRecover_AbortHandler(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		// the snapshot export aborts a broken stream on purpose
+		"abort": func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		},
+		// a started response can't become a 500 anymore
+		"started response": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if rec := recover(); rec != http.ErrAbortHandler {
+					t.Fatalf("expected http.ErrAbortHandler, got %v", rec)
+				}
+			}()
+			h := Recover(&panicLogger{})(handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+}
+
+func TestRequireJSON_DefaultContentType(t *testing.T) {
+	var got string
+	h := RequireJSON(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Content-Type")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", strings.NewReader(`{"user_id":"u1"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "application/json" {
+		t.Fatalf("expected Content-Type application/json, got %q", got)
+	}
+}
+
+func TestJSONBody(t *testing.T) {
+	store := inmemory.NewStore()
+	teams := inmemory.NewTeamRepository(store)
+	err := teams.CreateTeam(context.Background(), "backend", []domain.User{
+		{UserId: "u1", UserName: "Alice", IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	svc := usecase.NewService(teams, inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+	h := NewRouter(svc, "test", "test", WithMaxBodyBytes(128))
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"valid", "application/json; charset=utf-8", `{"user_id":"u1","is_active":false}`, http.StatusOK, ""},
+		{"no content type", "", `{"user_id":"u1","is_active":true}`, http.StatusOK, ""},
+		{"form", "application/x-www-form-urlencoded", `user_id=u1`, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"unknown field", "application/json", `{"user_id":"u1","active":true}`, http.StatusBadRequest, `unknown field "active"`},
+		{"wrong type", "application/json", `{"user_id":"u1","is_active":"yes"}`, http.StatusBadRequest, `field "is_active" must be a boolean`},
+		{"not an object", "application/json", `[]`, http.StatusBadRequest, "request body must be an object"},
+		{"syntax", "application/json", `{"user_id":}`, http.StatusBadRequest, "invalid json at offset 12"},
+		{"truncated", "application/json", `{"user_id":"u1"`, http.StatusBadRequest, "invalid json: unexpected end of body"},
+		{"trailing data", "application/json", `{"user_id":"u1","is_active":true} {}`, http.StatusBadRequest, "request body must contain a single JSON object"},
+		{"empty", "application/json", ``, http.StatusBadRequest, "request body is empty"},
+		{"empty with another content type", "text/plain", ``, http.StatusBadRequest, "request body is empty"},
+		{"too large", "application/json", `{"user_id":"` + strings.Repeat("u", 200) + `"}`, http.StatusRequestEntityTooLarge, "request body exceeds 128 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users/setIsActive", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer admin:u1")
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			var resp errorResponseJSON
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Error.Code != errorCodeValidation || resp.Error.Message != tt.wantMessage {
+				t.Fatalf("expected VALIDATION %q, got %+v", tt.wantMessage, resp.Error)
+			}
+		})
+	}
+}
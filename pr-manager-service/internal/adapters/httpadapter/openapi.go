@@ -0,0 +1,260 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// OpenAPIValidator checks requests against the OpenAPI contract before
+// they reach the handlers. With WithResponseValidation it also checks
+// every response, which tests use to catch handlers drifting from the contract.
+type OpenAPIValidator struct {
+	doc *openapi3.T
+	// nil unless responses are validated
+	onResponseError func(r *http.Request, err error)
+}
+
+// OpenAPIOption configures the validator
+type OpenAPIOption func(*OpenAPIValidator)
+
+// WithResponseValidation reports responses the contract doesn't describe,
+// as well as routes missing from it. Responses are buffered, except event
+// streams, so it is meant for tests.
+func WithResponseValidation(onError func(r *http.Request, err error)) OpenAPIOption {
+	return func(v *OpenAPIValidator) {
+		v.onResponseError = onError
+	}
+}
+
+// LoadOpenAPIValidator reads the contract from a YAML or JSON file
+func LoadOpenAPIValidator(path string, opts ...OpenAPIOption) (*OpenAPIValidator, error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAPIValidator(spec, opts...)
+}
+
+func NewOpenAPIValidator(spec []byte, opts ...OpenAPIOption) (*OpenAPIValidator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	v := &OpenAPIValidator{doc: doc}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// WithOpenAPI validates requests of every route against the contract
+func WithOpenAPI(v *OpenAPIValidator) RouterOption {
+	return func(h *HTTPHandler) {
+		h.openAPI = v
+	}
+}
+
+// Validates requests of the ServeMux pattern, which is the path of the
+// contract. Bodies are validated only for JSON endpoints, after LimitBody.
+func (v *OpenAPIValidator) requests(pattern string, validateBody bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		item := v.doc.Paths.Value(pattern)
+		if item == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			input := v.validationInput(r, pattern, item)
+			if input == nil {
+				// the handler answers 405
+				next.ServeHTTP(w, r)
+				return
+			}
+			input.Options.ExcludeRequestBody = !validateBody
+
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					writeError(w, http.StatusRequestEntityTooLarge, errorCodeValidation,
+						fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+					return
+				}
+				writeError(w, http.StatusBadRequest, errorCodeValidation, openAPIErrorMessage(err))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Checks responses of the ServeMux pattern with WithResponseValidation,
+// including the ones of the middlewares placed after it
+func (v *OpenAPIValidator) responses(pattern string) Middleware {
+	return func(next http.Handler) http.Handler {
+		if v.onResponseError == nil {
+			return next
+		}
+		item := v.doc.Paths.Value(pattern)
+		if item == nil {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				v.onResponseError(r, fmt.Errorf("route %s is not described by the contract", pattern))
+				next.ServeHTTP(w, r)
+			})
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			input := v.validationInput(r, pattern, item)
+			if input == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &contractRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if err := v.validateResponse(r.Context(), input, rec); err != nil {
+				v.onResponseError(r, err)
+			}
+			rec.flushBuffered()
+		})
+	}
+}
+
+// validationInput returns nil if the contract has no operation for the method
+func (v *OpenAPIValidator) validationInput(r *http.Request, pattern string, item *openapi3.PathItem) *openapi3filter.RequestValidationInput {
+	op := item.GetOperation(r.Method)
+	if op == nil {
+		return nil
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request: r,
+		Route: &routers.Route{
+			Spec:      v.doc,
+			Path:      pattern,
+			PathItem:  item,
+			Method:    r.Method,
+			Operation: op,
+		},
+		Options: &openapi3filter.Options{
+			// handlers check the token and the role
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			SkipSettingDefaults: true,
+		},
+	}
+}
+
+func (v *OpenAPIValidator) validateResponse(ctx context.Context, req *openapi3filter.RequestValidationInput, rec *contractRecorder) error {
+	if rec.streaming {
+		return nil
+	}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: req,
+		Status:                 rec.statusCode(),
+		Header:                 rec.Header(),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	}
+	input.SetBodyBytes(rec.body.Bytes())
+	return openapi3filter.ValidateResponse(ctx, input)
+}
+
+// Formats a request validation error for the VALIDATION response
+func openAPIErrorMessage(err error) string {
+	var (
+		reqErr    *openapi3filter.RequestError
+		schemaErr *openapi3.SchemaError
+	)
+	hasSchemaErr := errors.As(err, &schemaErr)
+	if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+		reason := reqErr.Reason
+		if hasSchemaErr {
+			reason = schemaErr.Reason
+		} else if reqErr.Err != nil {
+			reason = reqErr.Err.Error()
+		}
+		return fmt.Sprintf("parameter %q: %s", reqErr.Parameter.Name, reason)
+	}
+	if hasSchemaErr {
+		if path := schemaErr.JSONPointer(); len(path) > 0 {
+			if schemaErr.SchemaField == "required" {
+				return fmt.Sprintf("field %q is required", strings.Join(path, "."))
+			}
+			return fmt.Sprintf("field %q: %s", strings.Join(path, "."), schemaErr.Reason)
+		}
+		return "request body: " + schemaErr.Reason
+	}
+	return err.Error()
+}
+
+// contractRecorder buffers the response until it is validated.
+// Event streams are passed through, they never end in tests.
+type contractRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (rec *contractRecorder) WriteHeader(code int) {
+	if rec.status != 0 {
+		return
+	}
+	rec.status = code
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		rec.streaming = true
+		rec.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (rec *contractRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if rec.streaming {
+		return rec.ResponseWriter.Write(b)
+	}
+	return rec.body.Write(b)
+}
+
+// FlushError is found by http.ResponseController before Unwrap
+func (rec *contractRecorder) FlushError() error {
+	if !rec.streaming {
+		return nil
+	}
+	return http.NewResponseController(rec.ResponseWriter).Flush()
+}
+
+func (rec *contractRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *contractRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+func (rec *contractRecorder) flushBuffered() {
+	if rec.streaming {
+		return
+	}
+	rec.ResponseWriter.WriteHeader(rec.statusCode())
+	_, _ = rec.ResponseWriter.Write(rec.body.Bytes())
+}
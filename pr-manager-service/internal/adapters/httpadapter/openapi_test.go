@@ -0,0 +1,204 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+// The contract the handlers are checked against
+const contractPath = "../../../../docs/contracts/pr-manager-service-openapi.yml"
+
+// withContract fails the test on every response the contract doesn't describe
+func withContract(t *testing.T) RouterOption {
+	t.Helper()
+	v, err := LoadOpenAPIValidator(contractPath, WithResponseValidation(func(r *http.Request, err error) {
+		t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+	}))
+	if err != nil {
+		t.Fatalf("load contract: %v", err)
+	}
+	return WithOpenAPI(v)
+}
+
+func TestContract_Handlers(t *testing.T) {
+	store := inmemory.NewStore()
+	svc := usecase.NewService(inmemory.NewTeamRepository(store), inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+	h := NewRouter(svc, "test", "test", withContract(t))
+
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+	}{
+		{http.MethodPost, "/team/add", `{"team_name":"backend","members":[` +
+			`{"user_id":"u1","username":"Alice","is_active":true},` +
+			`{"user_id":"u2","username":"Bob","is_active":true},` +
+			`{"user_id":"u3","username":"Carol","is_active":true},` +
+			`{"user_id":"u4","username":"Dave","is_active":true}]}`, http.StatusCreated},
+		{http.MethodPost, "/team/add", `{"team_name":"backend","members":[]}`, http.StatusBadRequest},
+		{http.MethodGet, "/team/get?team_name=backend", "", http.StatusOK},
+		{http.MethodGet, "/team/get?team_name=frontend", "", http.StatusNotFound},
+		{http.MethodPost, "/users/setIsActive", `{"user_id":"u4","is_active":false}`, http.StatusOK},
+		{http.MethodPost, "/pullRequest/create", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`, http.StatusCreated},
+		{http.MethodPost, "/pullRequest/create", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`, http.StatusConflict},
+		{http.MethodPost, "/pullRequest/reassign", `{"pull_request_id":"pr-1","old_user_id":"u1"}`, http.StatusConflict},
+		{http.MethodGet, "/users/getReview?user_id=u2", "", http.StatusOK},
+		{http.MethodPost, "/pullRequest/merge", `{"pull_request_id":"pr-1"}`, http.StatusOK},
+		{http.MethodPost, "/pullRequest/merge", `{"pull_request_id":"pr-2"}`, http.StatusNotFound},
+		{http.MethodGet, "/webhooks/list", "", http.StatusNotImplemented},
+		{http.MethodPost, "/users/setChatHandle", `{"user_id":"u2","provider":"slack","handle":"bob"}`, http.StatusNotImplemented},
+		{http.MethodGet, "/email/unsubscribe?token=t1", "", http.StatusNotImplemented},
+		{http.MethodGet, "/admin/export?format=csv", "", http.StatusNotImplemented},
+		{http.MethodGet, "/admin/snapshot", "", http.StatusNotImplemented},
+		{http.MethodGet, "/stats", "", http.StatusOK},
+		{http.MethodGet, "/health", "", http.StatusOK},
+		{http.MethodGet, "/users/getReview", "", http.StatusBadRequest},
+		{http.MethodPost, "/users/setIsActive", `{"user_id":"u4"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer admin:u1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.path, tt.wantStatus, rec.Code, rec.Body)
+		}
+	}
+}
+
+type failingTeamRepo struct {
+	usecase.TeamRepositoryInterface
+}
+
+func (failingTeamRepo) GetTeam(context.Context, string) (*domain.Team, []domain.User, error) {
+	return nil, nil, errors.New("connection refused")
+}
+
+// Errors of the middlewares and the storage are described by the contract too
+func TestContract_ErrorStatuses(t *testing.T) {
+	store := inmemory.NewStore()
+	svc := usecase.NewService(failingTeamRepo{inmemory.NewTeamRepository(store)}, inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+	h := NewRouter(svc, "test", "test", withContract(t), WithMaxBodyBytes(64))
+
+	tests := []struct {
+		name, method, path, body string
+		token, contentType       string
+		wantStatus               int
+	}{
+		{"no token", http.MethodPost, "/pullRequest/merge", `{"pull_request_id":"pr-1"}`, "", "application/json", http.StatusUnauthorized},
+		{"not json", http.MethodPost, "/pullRequest/merge", `pull_request_id=pr-1`, "admin:u1", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"too large", http.MethodPost, "/pullRequest/merge", `{"pull_request_id":"` + strings.Repeat("p", 64) + `"}`, "admin:u1", "application/json", http.StatusRequestEntityTooLarge},
+		{"storage error", http.MethodGet, "/team/get?team_name=backend", "", "admin:u1", "", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidator_Requests(t *testing.T) {
+	store := inmemory.NewStore()
+	svc := usecase.NewService(inmemory.NewTeamRepository(store), inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+	v, err := LoadOpenAPIValidator(contractPath)
+	if err != nil {
+		t.Fatalf("load contract: %v", err)
+	}
+	h := NewRouter(svc, "test", "test", WithOpenAPI(v))
+
+	tests := []struct {
+		name, method, path, body string
+		wantMessage              string
+	}{
+		{"missing query parameter", http.MethodGet, "/team/get", "",
+			`parameter "team_name": value is required but missing`},
+		{"missing field", http.MethodPost, "/pullRequest/create", `{"pull_request_id":"pr-1","author_id":"u1"}`,
+			`field "pull_request_name" is required`},
+		{"wrong enum", http.MethodPost, "/integrations/identities/set", `{"provider":"bitbucket","login":"a","user_id":"u1"}`,
+			`field "provider": value is not one of the allowed values ["github","gitlab"]`},
+		{"nested field", http.MethodPost, "/team/add", `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":"yes"}]}`,
+			`field "members.0.is_active": value must be a boolean`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer admin:u1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			var resp errorResponseJSON
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if rec.Code != http.StatusBadRequest || resp.Error.Code != errorCodeValidation || resp.Error.Message != tt.wantMessage {
+				t.Fatalf("expected 400 VALIDATION %q, got %d %+v", tt.wantMessage, rec.Code, resp.Error)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidator_ReportsUndescribedResponses(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info: { title: test, version: "1" }
+paths:
+  /health:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [ status ]
+                properties:
+                  status: { type: integer }
+`)
+	var reported []string
+	v, err := NewOpenAPIValidator(spec, WithResponseValidation(func(r *http.Request, err error) {
+		reported = append(reported, r.URL.Path)
+	}))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	store := inmemory.NewStore()
+	svc := usecase.NewService(inmemory.NewTeamRepository(store), inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+	h := NewRouter(svc, "test", "test", WithOpenAPI(v))
+
+	for _, path := range []string{"/health", "/livez"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		// the response still reaches the client
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, rec.Code)
+		}
+	}
+	// status is a string, /livez is missing from the spec
+	if len(reported) != 2 || reported[0] != "/health" || reported[1] != "/livez" {
+		t.Fatalf("expected both responses reported, got %v", reported)
+	}
+}
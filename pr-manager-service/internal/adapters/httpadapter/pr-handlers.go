@@ -1,7 +1,6 @@
 package httpadapter
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"pr-manager-service/internal/usecase"
@@ -20,8 +19,7 @@ func (h *HTTPHandler) handleCreatePullRequest(w http.ResponseWriter, r *http.Req
 	}
 
 	var req pullRequestCreateJSON
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -41,6 +39,7 @@ func (h *HTTPHandler) handleCreatePullRequest(w http.ResponseWriter, r *http.Req
 		PR: mapPullRequestDTOToJSON(out.PR),
 	}
 
+	setETag(w, out.PR.Version)
 	writeJSON(w, http.StatusCreated, resp)
 }
 
@@ -56,14 +55,20 @@ func (h *HTTPHandler) handleMergePullRequest(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
+		return
+	}
+
 	var req pullRequestIdJSON
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	in := usecase.MergePullRequestInput{
-		PullRequestId: req.PullRequestId,
+		PullRequestId:   req.PullRequestId,
+		ExpectedVersion: expectedVersion,
 	}
 
 	out, err := h.svc.MergePullRequest(r.Context(), in)
@@ -76,6 +81,7 @@ func (h *HTTPHandler) handleMergePullRequest(w http.ResponseWriter, r *http.Requ
 		PR: mapPullRequestDTOToJSON(out.PR),
 	}
 
+	setETag(w, out.PR.Version)
 	writeJSON(w, http.StatusOK, resp)
 }
 
@@ -91,15 +97,21 @@ func (h *HTTPHandler) handleReassignReviewer(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
+		return
+	}
+
 	var req reassignRequestJSON
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	in := usecase.ReassignReviewerInput{
-		PullRequestId: req.PullRequestId,
-		OldUserId:     req.OldUserId,
+		PullRequestId:   req.PullRequestId,
+		OldUserId:       req.OldUserId,
+		ExpectedVersion: expectedVersion,
 	}
 
 	out, err := h.svc.ReassignReviewer(r.Context(), in)
@@ -113,6 +125,7 @@ func (h *HTTPHandler) handleReassignReviewer(w http.ResponseWriter, r *http.Requ
 		ReplacedBy: out.ReplacedBy,
 	}
 
+	setETag(w, out.PR.Version)
 	writeJSON(w, http.StatusOK, resp)
 }
 
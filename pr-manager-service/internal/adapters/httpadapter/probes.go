@@ -0,0 +1,122 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Dependency statuses of /readyz
+const (
+	probeStatusOk           = "ok"
+	probeStatusFailing      = "failing"
+	probeStatusShuttingDown = "shutting_down"
+)
+
+// ReadinessCheck is a dependency the service can't serve requests without
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Readiness runs the dependency checks of /readyz. It reports not ready
+// once SetShuttingDown is called, so load balancers drain the instance
+// before the server stops accepting connections.
+type Readiness struct {
+	checks       []ReadinessCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewReadiness bounds every check by timeout
+func NewReadiness(timeout time.Duration, checks ...ReadinessCheck) *Readiness {
+	return &Readiness{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// SetShuttingDown makes /readyz fail from now on
+func (r *Readiness) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Runs the checks concurrently, results keep the order of the checks
+func (r *Readiness) run(ctx context.Context) (bool, []dependencyStatusJSON) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	statuses := make([]dependencyStatusJSON, len(r.checks))
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := c.Check(ctx)
+			statuses[i] = dependencyStatusJSON{
+				Name:      c.Name,
+				Status:    probeStatusOk,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				statuses[i].Status = probeStatusFailing
+				statuses[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	ready := true
+	for _, s := range statuses {
+		if s.Status != probeStatusOk {
+			ready = false
+		}
+	}
+	return ready, statuses
+}
+
+// WithReadiness sets the dependency checks of /readyz, without it
+// /readyz only reflects graceful shutdown
+func WithReadiness(readiness *Readiness) RouterOption {
+	return func(h *HTTPHandler) {
+		h.readiness = readiness
+	}
+}
+
+// GET /livez
+// The process is up and serving HTTP, dependencies are not checked
+func (h *HTTPHandler) handleLivez(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, healthResponseJSON{Status: probeStatusOk})
+}
+
+// GET /readyz
+func (h *HTTPHandler) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.readiness.shuttingDown.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, readinessResponseJSON{Status: probeStatusShuttingDown})
+		return
+	}
+
+	ready, statuses := h.readiness.run(r.Context())
+	resp := readinessResponseJSON{
+		Status: probeStatusOk,
+		Checks: statuses,
+	}
+	status := http.StatusOK
+	if !ready {
+		resp.Status = probeStatusFailing
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,69 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+func TestProbes(t *testing.T) {
+	store := inmemory.NewStore()
+	svc := usecase.NewService(inmemory.NewTeamRepository(store), inmemory.NewUserRepository(store),
+		inmemory.NewPullRequestRepository(store), &noopLogger{}, &noopMetrics{})
+
+	var dbErr error
+	readiness := NewReadiness(time.Second,
+		ReadinessCheck{Name: "db", Check: func(context.Context) error { return dbErr }},
+		ReadinessCheck{Name: "cache", Check: func(context.Context) error { return nil }},
+	)
+	h := NewRouter(svc, "test", "test", WithReadiness(readiness), withContract(t))
+
+	get := func(path string) (int, readinessResponseJSON) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp readinessResponseJSON
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := get("/readyz")
+	if code != http.StatusOK || resp.Status != probeStatusOk || len(resp.Checks) != 2 {
+		t.Fatalf("expected ready with 2 checks, got %d %+v", code, resp)
+	}
+	if resp.Checks[0].Name != "db" || resp.Checks[1].Name != "cache" {
+		t.Fatalf("expected checks in order, got %+v", resp.Checks)
+	}
+
+	dbErr = errors.New("connection refused")
+	code, resp = get("/readyz")
+	if code != http.StatusServiceUnavailable || resp.Status != probeStatusFailing {
+		t.Fatalf("expected 503 when a dependency fails, got %d %+v", code, resp)
+	}
+	if resp.Checks[0].Status != probeStatusFailing || resp.Checks[0].Error != "connection refused" ||
+		resp.Checks[1].Status != probeStatusOk {
+		t.Fatalf("expected only db failing, got %+v", resp.Checks)
+	}
+
+	dbErr = nil
+	readiness.SetShuttingDown()
+	code, resp = get("/readyz")
+	if code != http.StatusServiceUnavailable || resp.Status != probeStatusShuttingDown {
+		t.Fatalf("expected 503 during shutdown, got %d %+v", code, resp)
+	}
+
+	// liveness does not depend on dependencies or shutdown
+	code, resp = get("/livez")
+	if code != http.StatusOK || resp.Status != probeStatusOk {
+		t.Fatalf("expected live, got %d %+v", code, resp)
+	}
+}
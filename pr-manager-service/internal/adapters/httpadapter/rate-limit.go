@@ -0,0 +1,71 @@
+package httpadapter
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// RateLimits are token buckets of every caller per route. A caller is the
+// subject of the token, requests without a valid token are counted by client IP.
+type RateLimits struct {
+	// Default applies to routes missing from Routes, zero Burst means no limit
+	Default usecase.RateLimitDTO
+	// limits by route pattern, e.g. "/pullRequest/create"
+	Routes map[string]usecase.RateLimitDTO
+}
+
+// WithRateLimits rejects requests over the limit with 429 RATE_LIMITED.
+// Probes are never limited.
+func WithRateLimits(limits RateLimits) RouterOption {
+	return func(h *HTTPHandler) {
+		h.rateLimits = limits
+	}
+}
+
+// Takes a token of the caller for the route before the handler runs. When
+// the limiter fails the request is let through, the service logs the error.
+func (h *HTTPHandler) rateLimit(pattern string) Middleware {
+	limit, ok := h.rateLimits.Routes[pattern]
+	if !ok {
+		limit = h.rateLimits.Default
+	}
+	if limit.Burst == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			out, err := h.svc.TakeRateLimitToken(r.Context(), usecase.TakeRateLimitTokenInput{
+				Scope: rateLimitScope(r),
+				Route: pattern,
+				Limit: limit,
+			})
+			if err != nil || out.Allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// whole seconds, rounded up so a retry is not early
+			retryAfter := max(int(math.Ceil(out.RetryAfter.Seconds())), 1)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeError(w, http.StatusTooManyRequests, errorCodeRateLimited,
+				fmt.Sprintf("rate limit of %s exceeded, retry after %d seconds", pattern, retryAfter))
+		})
+	}
+}
+
+func rateLimitScope(r *http.Request) string {
+	if info, err := parseAuthHeader(r); err == nil {
+		return authScope(info)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
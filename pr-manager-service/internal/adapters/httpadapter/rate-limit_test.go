@@ -0,0 +1,106 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/usecase"
+)
+
+func TestRateLimits(t *testing.T) {
+	store := inmemory.NewStore()
+	teams := inmemory.NewTeamRepository(store)
+	err := teams.CreateTeam(context.Background(), "backend", []domain.User{
+		{UserId: "u1", UserName: "Alice", IsActive: true},
+		{UserId: "u2", UserName: "Bob", IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	svc := usecase.NewService(teams, inmemory.NewUserRepository(store), inmemory.NewPullRequestRepository(store),
+		&noopLogger{}, &noopMetrics{},
+		usecase.WithRateLimits(inmemory.NewRateLimitRepository(store)),
+	)
+	h := NewRouter(svc, "test", "test", withContract(t), WithRateLimits(RateLimits{
+		Default: usecase.RateLimitDTO{Burst: 1, Period: time.Minute},
+		Routes: map[string]usecase.RateLimitDTO{
+			"/pullRequest/create": {Burst: 2, Period: time.Hour},
+		},
+	}))
+
+	send := func(method, target, body, token, remoteAddr string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	create := func(prId, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		body := `{"pull_request_id":"` + prId + `","pull_request_name":"Add search","author_id":"u1"}`
+		return send(http.MethodPost, "/pullRequest/create", body, token, "10.0.0.1:40000")
+	}
+	expectLimited := func(rec *httptest.ResponseRecorder, retryAfter string) {
+		t.Helper()
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429, got %d: %s", rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Retry-After"); got != retryAfter {
+			t.Fatalf("expected Retry-After %s, got %q", retryAfter, got)
+		}
+		var resp errorResponseJSON
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if resp.Error.Code != errorCodeRateLimited {
+			t.Fatalf("expected RATE_LIMITED, got %+v", resp.Error)
+		}
+	}
+
+	// the route limit replaces the default one
+	for _, prId := range []string{"pr-1", "pr-2"} {
+		if rec := create(prId, "admin:u1"); rec.Code != http.StatusCreated {
+			t.Fatalf("create %s: expected 201, got %d: %s", prId, rec.Code, rec.Body)
+		}
+	}
+	// a token comes back every 30 minutes
+	expectLimited(create("pr-3", "admin:u1"), "1800")
+
+	// other callers and routes have their own buckets
+	if rec := create("pr-3", "admin:u2"); rec.Code != http.StatusCreated {
+		t.Fatalf("create as another caller: expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := create("pr-4", "user:u1"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("create as a user with the same id: expected 401, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := send(http.MethodGet, "/team/get?team_name=backend", "", "admin:u1", "10.0.0.1:40000"); rec.Code != http.StatusOK {
+		t.Fatalf("get team: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// requests without a token are counted by client ip
+	if rec := send(http.MethodGet, "/users/getReview?user_id=u1", "", "", "10.0.0.1:40000"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous request: expected 401, got %d: %s", rec.Code, rec.Body)
+	}
+	expectLimited(send(http.MethodGet, "/users/getReview?user_id=u1", "", "", "10.0.0.1:40001"), "60")
+	if rec := send(http.MethodGet, "/users/getReview?user_id=u1", "", "", "10.0.0.2:40000"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous request from another ip: expected 401, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// probes are never limited
+	for i := 0; i < 3; i++ {
+		if rec := send(http.MethodGet, "/livez", "", "", "10.0.0.1:40000"); rec.Code != http.StatusOK {
+			t.Fatalf("livez: expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+}
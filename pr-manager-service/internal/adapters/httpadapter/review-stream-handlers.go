@@ -0,0 +1,90 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// Keeps proxies from closing an idle stream
+const reviewStreamHeartbeat = 15 * time.Second
+
+// GET /users/reviewStream
+func (h *HTTPHandler) handleReviewStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// for users or admins
+	auth, ok := requireAnyAuth(w, r)
+	if !ok {
+		return
+	}
+
+	userId := auth.UserId
+
+	// only admin can watch the queue of other users
+	if queryUserId := r.URL.Query().Get("user_id"); queryUserId != "" && queryUserId != userId {
+		if !auth.IsAdmin {
+			writeError(w, http.StatusUnauthorized, errorCodeNotFound, "forbidden for this user_id")
+			return
+		}
+		userId = queryUserId
+	}
+
+	events, err := h.svc.SubscribeReviewStream(r.Context(), usecase.SubscribeReviewStreamInput{UserId: userId})
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(reviewStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.streamsCtx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(reviewStreamEventJSON{
+				PullRequestId:   ev.PullRequestId,
+				PullRequestName: ev.PullRequestName,
+				AuthorId:        ev.AuthorId,
+				OccurredAt:      ev.OccurredAt.UTC().Format(time.RFC3339),
+			})
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Kind, data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
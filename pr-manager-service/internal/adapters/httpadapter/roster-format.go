@@ -0,0 +1,239 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"pr-manager-service/internal/usecase"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Roster formats of /admin/import and /admin/export
+const (
+	rosterFormatYAML = "yaml"
+	rosterFormatCSV  = "csv"
+)
+
+var rosterContentTypes = map[string]string{
+	rosterFormatYAML: "application/yaml",
+	rosterFormatCSV:  "text/csv",
+}
+
+var rosterCSVHeader = []string{"team_name", "user_id", "username", "is_active"}
+
+var errUnknownRosterFormat = errors.New("format must be yaml or csv")
+
+// YAML roster:
+//
+//	teams:
+//	  - team_name: backend
+//	    members:
+//	      - user_id: u1
+//	        username: Alice
+//	        is_active: true
+type rosterYAML struct {
+	Teams []rosterTeamYAML `yaml:"teams"`
+}
+
+type rosterTeamYAML struct {
+	TeamName string             `yaml:"team_name"`
+	Members  []rosterMemberYAML `yaml:"members"`
+}
+
+type rosterMemberYAML struct {
+	UserId   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	// omitted means active
+	IsActive *bool `yaml:"is_active"`
+}
+
+// Picks the format from ?format=, then from Content-Type, YAML by default
+func rosterFormat(query, contentType string) (string, error) {
+	if query != "" {
+		if _, ok := rosterContentTypes[query]; !ok {
+			return "", errUnknownRosterFormat
+		}
+		return query, nil
+	}
+	if strings.HasPrefix(contentType, rosterContentTypes[rosterFormatCSV]) {
+		return rosterFormatCSV, nil
+	}
+	return rosterFormatYAML, nil
+}
+
+func decodeRoster(format string, r io.Reader) ([]usecase.RosterTeamDTO, error) {
+	if format == rosterFormatCSV {
+		return decodeRosterCSV(r)
+	}
+	return decodeRosterYAML(r)
+}
+
+func encodeRoster(format string, teams []usecase.RosterTeamDTO) ([]byte, error) {
+	if format == rosterFormatCSV {
+		return encodeRosterCSV(teams)
+	}
+	return encodeRosterYAML(teams)
+}
+
+func decodeRosterYAML(r io.Reader) ([]usecase.RosterTeamDTO, error) {
+	var doc rosterYAML
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+
+	teams := make([]usecase.RosterTeamDTO, 0, len(doc.Teams))
+	for _, t := range doc.Teams {
+		team := usecase.RosterTeamDTO{
+			TeamName: t.TeamName,
+			Members:  make([]usecase.TeamMemberDTO, 0, len(t.Members)),
+		}
+		for _, m := range t.Members {
+			isActive := true
+			if m.IsActive != nil {
+				isActive = *m.IsActive
+			}
+			team.Members = append(team.Members, usecase.TeamMemberDTO{
+				UserId:   m.UserId,
+				UserName: m.Username,
+				IsActive: isActive,
+			})
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+func encodeRosterYAML(teams []usecase.RosterTeamDTO) ([]byte, error) {
+	doc := rosterYAML{Teams: make([]rosterTeamYAML, 0, len(teams))}
+	for _, t := range teams {
+		team := rosterTeamYAML{
+			TeamName: t.TeamName,
+			Members:  make([]rosterMemberYAML, 0, len(t.Members)),
+		}
+		for _, m := range t.Members {
+			isActive := m.IsActive
+			team.Members = append(team.Members, rosterMemberYAML{
+				UserId:   m.UserId,
+				Username: m.UserName,
+				IsActive: &isActive,
+			})
+		}
+		doc.Teams = append(doc.Teams, team)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CSV roster has one row per membership, a team without members is a row
+// with empty user columns:
+//
+//	team_name,user_id,username,is_active
+//	backend,u1,Alice,true
+//	empty-team,,,
+func decodeRosterCSV(r io.Reader) ([]usecase.RosterTeamDTO, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(rosterCSVHeader)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []usecase.RosterTeamDTO{}, nil
+		}
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	for i, name := range rosterCSVHeader {
+		if strings.TrimSpace(header[i]) != name {
+			return nil, fmt.Errorf("invalid csv: header must be %s", strings.Join(rosterCSVHeader, ","))
+		}
+	}
+
+	var teams []usecase.RosterTeamDTO
+	index := make(map[string]int)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+
+		teamName, userId, username, active := record[0], record[1], record[2], record[3]
+
+		// rows of one team do not have to be adjacent
+		i, ok := index[teamName]
+		if !ok {
+			i = len(teams)
+			index[teamName] = i
+			teams = append(teams, usecase.RosterTeamDTO{
+				TeamName: teamName,
+				Members:  []usecase.TeamMemberDTO{},
+			})
+		}
+		if userId == "" && username == "" {
+			continue
+		}
+
+		isActive := true
+		if active != "" {
+			isActive, err = strconv.ParseBool(active)
+			if err != nil {
+				line, _ := cr.FieldPos(3)
+				return nil, fmt.Errorf("invalid csv: line %d: is_active must be true or false", line)
+			}
+		}
+		teams[i].Members = append(teams[i].Members, usecase.TeamMemberDTO{
+			UserId:   userId,
+			UserName: username,
+			IsActive: isActive,
+		})
+	}
+	return teams, nil
+}
+
+func encodeRosterCSV(teams []usecase.RosterTeamDTO) ([]byte, error) {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+
+	if err := cw.Write(rosterCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, t := range teams {
+		if len(t.Members) == 0 {
+			if err := cw.Write([]string{t.TeamName, "", "", ""}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, m := range t.Members {
+			record := []string{t.TeamName, m.UserId, m.UserName, strconv.FormatBool(m.IsActive)}
+			if err := cw.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,90 @@
+package httpadapter
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pr-manager-service/internal/usecase"
+)
+
+func TestRosterFormats_RoundTrip(t *testing.T) {
+	teams := []usecase.RosterTeamDTO{
+		{TeamName: "backend", Members: []usecase.TeamMemberDTO{
+			{UserId: "u1", UserName: "Alice", IsActive: true},
+			{UserId: "u2", UserName: "Bob, Jr.", IsActive: false},
+		}},
+		{TeamName: "empty", Members: []usecase.TeamMemberDTO{}},
+	}
+
+	for _, format := range []string{rosterFormatYAML, rosterFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			body, err := encodeRoster(format, teams)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			got, err := decodeRoster(format, bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("decode: %v\n%s", err, body)
+			}
+			if !reflect.DeepEqual(got, teams) {
+				t.Fatalf("roster does not round-trip:\n got %+v\nwant %+v\n%s", got, teams, body)
+			}
+		})
+	}
+}
+
+func TestDecodeRoster_Defaults(t *testing.T) {
+	yamlRoster := `
+teams:
+  - team_name: backend
+    members:
+      - user_id: u1
+        username: Alice
+`
+	teams, err := decodeRoster(rosterFormatYAML, strings.NewReader(yamlRoster))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(teams) != 1 || !teams[0].Members[0].IsActive {
+		t.Fatalf("omitted is_active must mean active, got %+v", teams)
+	}
+
+	csvRoster := "team_name,user_id,username,is_active\nbackend,u1,Alice,\nfrontend,u2,Bob,false\nbackend,u3,Carol,true\n"
+	teams, err = decodeRoster(rosterFormatCSV, strings.NewReader(csvRoster))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(teams) != 2 || len(teams[0].Members) != 2 || !teams[0].Members[0].IsActive || teams[1].Members[0].IsActive {
+		t.Fatalf("unexpected csv roster %+v", teams)
+	}
+}
+
+func TestDecodeRoster_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		body   string
+	}{
+		{"unknown yaml field", rosterFormatYAML, "teams:\n  - name: backend\n"},
+		{"bad csv header", rosterFormatCSV, "team,user,name,active\n"},
+		{"bad is_active", rosterFormatCSV, "team_name,user_id,username,is_active\nbackend,u1,Alice,yes-please\n"},
+		{"wrong column count", rosterFormatCSV, "team_name,user_id,username,is_active\nbackend,u1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeRoster(tt.format, strings.NewReader(tt.body)); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+
+	if _, err := rosterFormat("xml", ""); err == nil {
+		t.Fatalf("expected unknown format error")
+	}
+	if format, _ := rosterFormat("", "text/csv; charset=utf-8"); format != rosterFormatCSV {
+		t.Fatalf("expected csv from content type, got %s", format)
+	}
+}
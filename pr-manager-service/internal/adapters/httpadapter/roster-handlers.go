@@ -0,0 +1,126 @@
+package httpadapter
+
+import (
+	"net/http"
+	"strconv"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// Upper bound of an uploaded roster
+const maxRosterBytes = 8 << 20
+
+// POST /admin/import?format=yaml|csv&dry_run=true
+func (h *HTTPHandler) handleImportRoster(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := rosterFormat(query.Get("format"), r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
+		return
+	}
+
+	dryRun := false
+	if v := query.Get("dry_run"); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorCodeValidation, "dry_run must be true or false")
+			return
+		}
+	}
+
+	teams, err := decodeRoster(format, http.MaxBytesReader(w, r.Body, maxRosterBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
+		return
+	}
+
+	out, err := h.svc.ImportRoster(r.Context(), usecase.ImportRosterInput{
+		Teams:  teams,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapImportRosterOutputToJSON(out))
+}
+
+// GET /admin/export?format=yaml|csv
+func (h *HTTPHandler) handleExportRoster(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	format, err := rosterFormat(r.URL.Query().Get("format"), "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, err.Error())
+		return
+	}
+
+	out, err := h.svc.ExportRoster(r.Context())
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	body, err := encodeRoster(format, out.Teams)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errorCodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", rosterContentTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="roster.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func mapImportRosterOutputToJSON(out *usecase.ImportRosterOutput) importRosterResponseJSON {
+	resp := importRosterResponseJSON{
+		DryRun:           out.DryRun,
+		TeamsCreated:     out.TeamsCreated,
+		UsersCreated:     make([]teamMemberJSON, 0, len(out.UsersCreated)),
+		UsersUpdated:     make([]rosterUserChangeJSON, 0, len(out.UsersUpdated)),
+		MembershipsAdded: make([]rosterMembershipJSON, 0, len(out.MembershipsAdded)),
+	}
+	for _, u := range out.UsersCreated {
+		resp.UsersCreated = append(resp.UsersCreated, teamMemberJSON{
+			UserId:   u.UserId,
+			Username: u.UserName,
+			IsActive: u.IsActive,
+		})
+	}
+	for _, u := range out.UsersUpdated {
+		resp.UsersUpdated = append(resp.UsersUpdated, rosterUserChangeJSON{
+			UserId:      u.UserId,
+			OldUsername: u.OldUserName,
+			Username:    u.UserName,
+			OldIsActive: u.OldIsActive,
+			IsActive:    u.IsActive,
+		})
+	}
+	for _, m := range out.MembershipsAdded {
+		resp.MembershipsAdded = append(resp.MembershipsAdded, rosterMembershipJSON{
+			TeamName: m.TeamName,
+			UserId:   m.UserId,
+		})
+	}
+	return resp
+}
@@ -1,7 +1,9 @@
 package httpadapter
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"pr-manager-service/internal/usecase"
 )
@@ -10,37 +12,124 @@ type HTTPHandler struct {
 	svc     *usecase.Service
 	appName string
 	version string
+
+	githubWebhookSecret string
+	gitlabWebhookToken  string
+
+	// streams are closed when it is done, so graceful shutdown does not wait for them
+	streamsCtx context.Context
+
+	readiness *Readiness
+
+	maxBodyBytes int64
+	openAPI      *OpenAPIValidator
+	rateLimits   RateLimits
+}
+
+// RouterOption configures optional parts of the HTTP handler
+type RouterOption func(*HTTPHandler)
+
+// WithIntegrationSecrets sets secrets used to verify GitHub and GitLab webhooks.
+// An empty secret rejects every request of that provider.
+func WithIntegrationSecrets(githubWebhookSecret, gitlabWebhookToken string) RouterOption {
+	return func(h *HTTPHandler) {
+		h.githubWebhookSecret = githubWebhookSecret
+		h.gitlabWebhookToken = gitlabWebhookToken
+	}
 }
 
-func NewHTTPHandler(svc *usecase.Service, appName, version string) *HTTPHandler {
-	return &HTTPHandler{
-		svc:     svc,
-		appName: appName,
-		version: version,
+// WithMaxBodyBytes bounds the bodies of JSON endpoints, the roster import,
+// the snapshot restore and provider webhooks have their own limits
+func WithMaxBodyBytes(maxBytes int64) RouterOption {
+	return func(h *HTTPHandler) {
+		h.maxBodyBytes = maxBytes
+	}
+}
+
+// WithStreamsContext bounds the lifetime of long-lived streaming responses
+func WithStreamsContext(ctx context.Context) RouterOption {
+	return func(h *HTTPHandler) {
+		h.streamsCtx = ctx
+	}
+}
+
+func NewHTTPHandler(svc *usecase.Service, appName, version string, opts ...RouterOption) *HTTPHandler {
+	h := &HTTPHandler{
+		svc:        svc,
+		appName:    appName,
+		version:    version,
+		streamsCtx: context.Background(),
+		readiness:  NewReadiness(time.Second),
+
+		maxBodyBytes: DefaultMaxBodyBytes,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-func NewRouter(svc *usecase.Service, appName, version string) *http.ServeMux {
-	h := NewHTTPHandler(svc, appName, version)
+func NewRouter(svc *usecase.Service, appName, version string, opts ...RouterOption) *http.ServeMux {
+	h := NewHTTPHandler(svc, appName, version, opts...)
 
 	mux := http.NewServeMux()
 
+	// Requests are checked against the OpenAPI contract when WithOpenAPI is given,
+	// JSON bodies after their size and Content-Type, and then rate limited.
+	// Responses are checked first, so the ones of every middleware are covered.
+	// Probes are not limited.
+	route := func(pattern string, next http.HandlerFunc) {
+		mux.Handle(pattern, Chain(next, h.contractResponses(pattern), h.contract(pattern, false), h.rateLimit(pattern)))
+	}
+	jsonRoute := func(pattern string, next http.HandlerFunc) {
+		mux.Handle(pattern, Chain(next, h.contractResponses(pattern), LimitBody(h.maxBodyBytes), RequireJSON,
+			h.contract(pattern, true), h.rateLimit(pattern)))
+	}
+	probe := func(pattern string, next http.HandlerFunc) {
+		mux.Handle(pattern, Chain(next, h.contractResponses(pattern), h.contract(pattern, false)))
+	}
+
+	// Mutating endpoints wrapped with withIdempotency accept an Idempotency-Key
 	// Teams
-	mux.HandleFunc("/team/add", h.handleCreateTeam)
-	mux.HandleFunc("/team/get", h.handleGetTeam)
+	jsonRoute("/team/add", h.withIdempotency(h.handleCreateTeam))
+	route("/team/get", h.handleGetTeam)
 
 	// Users
-	mux.HandleFunc("/users/setIsActive", h.handleSetIsActive)
-	mux.HandleFunc("/users/getReview", h.handleGetUserReviews)
+	jsonRoute("/users/setIsActive", h.withIdempotency(h.handleSetIsActive))
+	route("/users/getReview", h.handleGetUserReviews)
+	route("/users/reviewStream", h.handleReviewStream)
+	jsonRoute("/users/setChatHandle", h.withIdempotency(h.handleSetChatHandle))
+	jsonRoute("/users/setEmail", h.withIdempotency(h.handleSetEmailSubscription))
+
+	// Email
+	route("/email/unsubscribe", h.handleUnsubscribeEmail)
 
 	// PullRequests
-	mux.HandleFunc("/pullRequest/create", h.handleCreatePullRequest)
-	mux.HandleFunc("/pullRequest/merge", h.handleMergePullRequest)
-	mux.HandleFunc("/pullRequest/reassign", h.handleReassignReviewer)
+	jsonRoute("/pullRequest/create", h.withIdempotency(h.handleCreatePullRequest))
+	jsonRoute("/pullRequest/merge", h.withIdempotency(h.handleMergePullRequest))
+	jsonRoute("/pullRequest/reassign", h.withIdempotency(h.handleReassignReviewer))
+
+	// Webhooks
+	jsonRoute("/webhooks/add", h.withIdempotency(h.handleCreateWebhookSubscription))
+	route("/webhooks/list", h.handleListWebhookSubscriptions)
+	jsonRoute("/webhooks/delete", h.withIdempotency(h.handleDeleteWebhookSubscription))
+
+	// Integrations
+	route("/integrations/github/webhook", h.handleGitHubWebhook)
+	route("/integrations/gitlab/webhook", h.handleGitLabWebhook)
+	jsonRoute("/integrations/identities/set", h.withIdempotency(h.handleSetIdentity))
+
+	// Admin
+	route("/admin/import", h.handleImportRoster)
+	route("/admin/export", h.handleExportRoster)
+	route("/admin/snapshot", h.handleExportSnapshot)
+	route("/admin/restore", h.handleRestoreSnapshot)
 
 	// Stats / Health
-	mux.HandleFunc("/stats", h.handleStats)
-	mux.HandleFunc("/health", h.handleHealth)
+	route("/stats", h.handleStats)
+	probe("/health", h.handleHealth)
+	probe("/livez", h.handleLivez)
+	probe("/readyz", h.handleReadyz)
 
 	return mux
 }
@@ -52,3 +141,19 @@ func NewServer(addr string, handler http.Handler) *http.Server {
 		Handler: handler,
 	}
 }
+
+// Validates requests of the route against the contract, a no-op without WithOpenAPI
+func (h *HTTPHandler) contract(pattern string, validateBody bool) Middleware {
+	if h.openAPI == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return h.openAPI.requests(pattern, validateBody)
+}
+
+// Validates responses of the route, a no-op unless WithResponseValidation is given
+func (h *HTTPHandler) contractResponses(pattern string) Middleware {
+	if h.openAPI == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return h.openAPI.responses(pattern)
+}
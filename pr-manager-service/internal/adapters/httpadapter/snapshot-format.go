@@ -0,0 +1,259 @@
+package httpadapter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+// Snapshot layout:
+//
+//	{"format_version":2,"schema_version":5,"created_at":"...",
+//	 "teams":[...],"users":[...],"memberships":[...],"pull_requests":[...],"assignments":[...]}
+type snapshotJSON struct {
+	FormatVersion int                       `json:"format_version"`
+	SchemaVersion int64                     `json:"schema_version"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	Teams         []snapshotTeamJSON        `json:"teams"`
+	Users         []snapshotUserJSON        `json:"users"`
+	Memberships   []rosterMembershipJSON    `json:"memberships"`
+	PullRequests  []snapshotPullRequestJSON `json:"pull_requests"`
+	Assignments   []snapshotAssignmentJSON  `json:"assignments"`
+}
+
+type snapshotTeamJSON struct {
+	TeamName string `json:"team_name"`
+}
+
+type snapshotUserJSON struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type snapshotPullRequestJSON struct {
+	PullRequestId     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorId          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	NeedMoreReviewers bool       `json:"need_more_reviewers"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	Version           int64      `json:"version"`
+}
+
+type snapshotAssignmentJSON struct {
+	PullRequestId  string     `json:"pull_request_id"`
+	UserId         string     `json:"user_id"`
+	Slot           int        `json:"slot"`
+	CreatedAt      time.Time  `json:"created_at"`
+	FirstVerdictAt *time.Time `json:"first_verdict_at"`
+}
+
+type snapshotHeaderJSON struct {
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion int64     `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Pull request status ids, 1 - OPEN, 2 - MERGED
+var (
+	snapshotStatuses  = map[int]string{1: "OPEN", 2: "MERGED"}
+	snapshotStatusIds = map[string]int{"OPEN": 1, "MERGED": 2}
+	snapshotSections  = []string{"teams", "users", "memberships", "pull_requests", "assignments"}
+)
+
+const snapshotBufferSize = 32 << 10
+
+const (
+	sectionTeams = iota
+	sectionUsers
+	sectionMemberships
+	sectionPullRequests
+	sectionAssignments
+)
+
+// snapshotJSONWriter writes the snapshot as it is read from the database,
+// without holding it in memory
+type snapshotJSONWriter struct {
+	w       *bufio.Writer
+	section int
+	first   bool
+}
+
+var _ usecase.SnapshotWriterInterface = (*snapshotJSONWriter)(nil)
+
+func newSnapshotJSONWriter(w io.Writer) *snapshotJSONWriter {
+	return &snapshotJSONWriter{
+		w:       bufio.NewWriterSize(w, snapshotBufferSize),
+		section: -1,
+	}
+}
+
+func (sw *snapshotJSONWriter) WriteHeader(h domain.SnapshotHeader) error {
+	header, err := json.Marshal(snapshotHeaderJSON{
+		FormatVersion: h.FormatVersion,
+		SchemaVersion: h.SchemaVersion,
+		CreatedAt:     h.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	// the header object is left open for the sections
+	_, err = sw.w.Write(header[:len(header)-1])
+	return err
+}
+
+func (sw *snapshotJSONWriter) WriteTeam(t domain.Team) error {
+	return sw.writeRecord(sectionTeams, snapshotTeamJSON{TeamName: t.TeamName})
+}
+
+func (sw *snapshotJSONWriter) WriteUser(u domain.User) error {
+	return sw.writeRecord(sectionUsers, snapshotUserJSON{
+		UserId:   u.UserId,
+		Username: u.UserName,
+		IsActive: u.IsActive,
+	})
+}
+
+func (sw *snapshotJSONWriter) WriteMembership(m domain.Membership) error {
+	return sw.writeRecord(sectionMemberships, rosterMembershipJSON{
+		TeamName: m.TeamName,
+		UserId:   m.UserId,
+	})
+}
+
+func (sw *snapshotJSONWriter) WritePullRequest(pr domain.SnapshotPullRequest) error {
+	return sw.writeRecord(sectionPullRequests, snapshotPullRequestJSON{
+		PullRequestId:     pr.PullRequestId,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorId,
+		Status:            snapshotStatuses[pr.StatusId],
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+		Version:           pr.Version,
+	})
+}
+
+func (sw *snapshotJSONWriter) WriteAssignment(a domain.ReviewerAssignment) error {
+	return sw.writeRecord(sectionAssignments, snapshotAssignmentJSON{
+		PullRequestId:  a.PullRequestId,
+		UserId:         a.UserId,
+		Slot:           a.Slot,
+		CreatedAt:      a.CreatedAt,
+		FirstVerdictAt: a.FirstVerdictAt,
+	})
+}
+
+// Close writes the sections that had no records and ends the object
+func (sw *snapshotJSONWriter) Close() error {
+	if err := sw.enterSection(len(snapshotSections) - 1); err != nil {
+		return err
+	}
+	if _, err := sw.w.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+// Opens the section and every empty one before it
+func (sw *snapshotJSONWriter) enterSection(section int) error {
+	for sw.section < section {
+		if sw.section >= 0 {
+			if err := sw.w.WriteByte(']'); err != nil {
+				return err
+			}
+		}
+		sw.section++
+		if _, err := sw.w.WriteString(`,"` + snapshotSections[sw.section] + `":[`); err != nil {
+			return err
+		}
+		sw.first = true
+	}
+	return nil
+}
+
+func (sw *snapshotJSONWriter) writeRecord(section int, v any) error {
+	if err := sw.enterSection(section); err != nil {
+		return err
+	}
+	if !sw.first {
+		if err := sw.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	sw.first = false
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = sw.w.Write(data)
+	return err
+}
+
+func decodeSnapshot(r io.Reader) (*domain.Snapshot, error) {
+	var doc snapshotJSON
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	snap := &domain.Snapshot{
+		Header: domain.SnapshotHeader{
+			FormatVersion: doc.FormatVersion,
+			SchemaVersion: doc.SchemaVersion,
+			CreatedAt:     doc.CreatedAt,
+		},
+		Teams:        make([]domain.Team, 0, len(doc.Teams)),
+		Users:        make([]domain.User, 0, len(doc.Users)),
+		Memberships:  make([]domain.Membership, 0, len(doc.Memberships)),
+		PullRequests: make([]domain.SnapshotPullRequest, 0, len(doc.PullRequests)),
+		Assignments:  make([]domain.ReviewerAssignment, 0, len(doc.Assignments)),
+	}
+	for _, t := range doc.Teams {
+		snap.Teams = append(snap.Teams, domain.Team{TeamName: t.TeamName})
+	}
+	for _, u := range doc.Users {
+		snap.Users = append(snap.Users, domain.User{
+			UserId:   u.UserId,
+			UserName: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+	for _, m := range doc.Memberships {
+		snap.Memberships = append(snap.Memberships, domain.Membership{
+			TeamName: m.TeamName,
+			UserId:   m.UserId,
+		})
+	}
+	for _, pr := range doc.PullRequests {
+		// an unknown status maps to 0 and is rejected by the usecase
+		snap.PullRequests = append(snap.PullRequests, domain.SnapshotPullRequest{
+			PullRequestId:     pr.PullRequestId,
+			PullRequestName:   pr.PullRequestName,
+			AuthorId:          pr.AuthorId,
+			StatusId:          snapshotStatusIds[pr.Status],
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			Version:           pr.Version,
+		})
+	}
+	for _, a := range doc.Assignments {
+		snap.Assignments = append(snap.Assignments, domain.ReviewerAssignment{
+			PullRequestId:  a.PullRequestId,
+			UserId:         a.UserId,
+			Slot:           a.Slot,
+			CreatedAt:      a.CreatedAt,
+			FirstVerdictAt: a.FirstVerdictAt,
+		})
+	}
+	return snap, nil
+}
@@ -0,0 +1,107 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+func writeTestSnapshot(t *testing.T, snap *domain.Snapshot) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	sw := newSnapshotJSONWriter(&buf)
+	steps := []error{sw.WriteHeader(snap.Header)}
+	for _, team := range snap.Teams {
+		steps = append(steps, sw.WriteTeam(team))
+	}
+	for _, u := range snap.Users {
+		steps = append(steps, sw.WriteUser(u))
+	}
+	for _, m := range snap.Memberships {
+		steps = append(steps, sw.WriteMembership(m))
+	}
+	for _, pr := range snap.PullRequests {
+		steps = append(steps, sw.WritePullRequest(pr))
+	}
+	for _, a := range snap.Assignments {
+		steps = append(steps, sw.WriteAssignment(a))
+	}
+	steps = append(steps, sw.Close())
+
+	for _, err := range steps {
+		if err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestSnapshotJSON_RoundTrip(t *testing.T) {
+	created := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	merged := created.Add(time.Hour)
+
+	snap := &domain.Snapshot{
+		Header: domain.SnapshotHeader{FormatVersion: 1, SchemaVersion: 5, CreatedAt: created},
+		Teams:  []domain.Team{{TeamName: "backend"}, {TeamName: "empty"}},
+		Users: []domain.User{
+			{UserId: "u1", UserName: "Alice", IsActive: true},
+			{UserId: "u2", UserName: "Bob", IsActive: false},
+		},
+		Memberships: []domain.Membership{{TeamName: "backend", UserId: "u1"}},
+		PullRequests: []domain.SnapshotPullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", StatusId: 1, NeedMoreReviewers: true, CreatedAt: created, Version: 1},
+			{PullRequestId: "pr-2", PullRequestName: "Fix bug", AuthorId: "u1", StatusId: 2, CreatedAt: created, MergedAt: &merged, Version: 3},
+		},
+		Assignments: []domain.ReviewerAssignment{
+			{PullRequestId: "pr-1", UserId: "u2", Slot: 1, CreatedAt: created, FirstVerdictAt: &merged},
+			{PullRequestId: "pr-2", UserId: "u2", Slot: 1, CreatedAt: created},
+		},
+	}
+
+	body := writeTestSnapshot(t, snap)
+	if !json.Valid(body) {
+		t.Fatalf("snapshot is not valid json:\n%s", body)
+	}
+
+	got, err := decodeSnapshot(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("decode: %v\n%s", err, body)
+	}
+	if !reflect.DeepEqual(got, snap) {
+		t.Fatalf("snapshot does not round-trip:\n got %+v\nwant %+v", got, snap)
+	}
+}
+
+func TestSnapshotJSON_EmptySections(t *testing.T) {
+	snap := &domain.Snapshot{
+		Header: domain.SnapshotHeader{FormatVersion: 1, SchemaVersion: 5, CreatedAt: time.Now().UTC()},
+		Users:  []domain.User{{UserId: "u1", UserName: "Alice", IsActive: true}},
+	}
+	body := writeTestSnapshot(t, snap)
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("snapshot is not valid json: %v\n%s", err, body)
+	}
+	for _, section := range snapshotSections {
+		if _, ok := doc[section]; !ok {
+			t.Fatalf("section %s is missing:\n%s", section, body)
+		}
+	}
+	if string(doc["teams"]) != "[]" || string(doc["assignments"]) != "[]" {
+		t.Fatalf("empty sections must be empty arrays:\n%s", body)
+	}
+}
+
+func TestDecodeSnapshot_UnknownField(t *testing.T) {
+	_, err := decodeSnapshot(strings.NewReader(`{"format_version":1,"schema_version":5,"webhooks":[]}`))
+	if err == nil {
+		t.Fatalf("expected an error for an unknown section")
+	}
+}
@@ -0,0 +1,92 @@
+package httpadapter
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// Upper bound of an uploaded snapshot
+const maxSnapshotBytes = 512 << 20
+
+// countingWriter tells whether any part of the response body was sent
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// GET /admin/snapshot
+func (h *HTTPHandler) handleExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition",
+		`attachment; filename="snapshot-`+time.Now().UTC().Format("20060102T150405Z")+`.json"`)
+
+	body := &countingWriter{w: w}
+	err := h.svc.ExportSnapshot(r.Context(), newSnapshotJSONWriter(body))
+	if err != nil {
+		if body.n == 0 {
+			w.Header().Del("Content-Disposition")
+			writeMappedError(w, err)
+			return
+		}
+		// the status is already sent, break the connection so the client
+		// does not take a truncated snapshot for a complete one
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// POST /admin/restore
+func (h *HTTPHandler) handleRestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	snap, err := decodeSnapshot(http.MaxBytesReader(w, r.Body, maxSnapshotBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid snapshot json: "+err.Error())
+		return
+	}
+
+	out, err := h.svc.RestoreSnapshot(r.Context(), usecase.RestoreSnapshotInput{Snapshot: snap})
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := restoreSnapshotResponseJSON{
+		Restored: restoredSnapshotJSON{
+			SchemaVersion: out.SchemaVersion,
+			Teams:         out.Teams,
+			Users:         out.Users,
+			Memberships:   out.Memberships,
+			PullRequests:  out.PullRequests,
+			Assignments:   out.Assignments,
+		},
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -1,7 +1,6 @@
 package httpadapter
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"pr-manager-service/internal/usecase"
@@ -15,8 +14,7 @@ func (h *HTTPHandler) handleCreateTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req teamJSON
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -0,0 +1,26 @@
+package httpadapter
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// TraceHandler starts a server span for every request, continuing the trace
+// of an incoming W3C traceparent header. Spans are named after the mux
+// pattern, not the raw path, to keep their cardinality bounded.
+func TraceHandler(mux *http.ServeMux, next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + routePattern(mux, r)
+		}),
+	)
+}
+
+// routePattern returns the mux pattern serving r or "unmatched"
+func routePattern(mux *http.ServeMux, r *http.Request) string {
+	if _, pattern := mux.Handler(r); pattern != "" {
+		return pattern
+	}
+	return "unmatched"
+}
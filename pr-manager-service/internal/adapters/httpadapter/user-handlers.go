@@ -1,7 +1,6 @@
 package httpadapter
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"pr-manager-service/internal/usecase"
@@ -20,8 +19,7 @@ func (h *HTTPHandler) handleSetIsActive(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req setIsActiveRequestJSON
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errorCodeValidation, "invalid json")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -48,6 +46,46 @@ func (h *HTTPHandler) handleSetIsActive(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// POST /users/setChatHandle
+func (h *HTTPHandler) handleSetChatHandle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req chatHandleJSON
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	in := usecase.SetChatHandleInput{
+		UserId:   req.UserId,
+		Provider: req.Provider,
+		Handle:   req.Handle,
+	}
+
+	out, err := h.svc.SetChatHandle(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := chatHandleResponseJSON{
+		ChatHandle: chatHandleJSON{
+			UserId:   out.UserId,
+			Provider: out.Provider,
+			Handle:   out.Handle,
+		},
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // GET /users/getReview?user_id=...
 func (h *HTTPHandler) handleGetUserReviews(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -0,0 +1,111 @@
+package httpadapter
+
+import (
+	"net/http"
+
+	"pr-manager-service/internal/usecase"
+)
+
+// POST /webhooks/add
+func (h *HTTPHandler) handleCreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req webhookSubscriptionCreateJSON
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	in := usecase.CreateWebhookSubscriptionInput{
+		Url:        req.Url,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}
+
+	out, err := h.svc.CreateWebhookSubscription(r.Context(), in)
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := webhookSubscriptionResponseJSON{
+		Subscription: mapWebhookSubscriptionDTOToJSON(out.Subscription),
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// GET /webhooks/list
+func (h *HTTPHandler) handleListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	out, err := h.svc.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	resp := webhookSubscriptionsResponseJSON{
+		Subscriptions: make([]webhookSubscriptionJSON, 0, len(out.Subscriptions)),
+	}
+	for _, sub := range out.Subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, mapWebhookSubscriptionDTOToJSON(sub))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// POST /webhooks/delete
+func (h *HTTPHandler) handleDeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only for admins
+	if _, ok := requireAdmin(w, r); !ok {
+		return
+	}
+
+	var req webhookSubscriptionIdJSON
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	in := usecase.DeleteWebhookSubscriptionInput{Id: req.Id}
+
+	if err := h.svc.DeleteWebhookSubscription(r.Context(), in); err != nil {
+		writeMappedError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func mapWebhookSubscriptionDTOToJSON(sub usecase.WebhookSubscriptionDTO) webhookSubscriptionJSON {
+	eventTypes := sub.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return webhookSubscriptionJSON{
+		Id:         sub.Id,
+		Url:        sub.Url,
+		EventTypes: eventTypes,
+		IsActive:   sub.IsActive,
+	}
+}
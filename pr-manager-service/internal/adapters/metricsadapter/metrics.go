@@ -3,17 +3,29 @@ package metricsadapter
 import (
 	"pr-manager-service/internal/usecase"
 
+	"github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type Metrics struct {
+	service string
+
 	teamCreated     prometheus.Counter
 	userActivated   prometheus.Counter
 	userDeactivated prometheus.Counter
 	prCreated       prometheus.Counter
 	prMerged        prometheus.Counter
 	prReassigned    prometheus.Counter
+
+	// workload, refreshed by SetWorkload
+	openAssignmentsByUser *gaugeSnapshot
+	openAssignmentsByTeam *gaugeSnapshot
+	openPullRequests      prometheus.Gauge
+	unfilledPullRequests  prometheus.Gauge
+	openPullRequestAge    *durationHistogram
+	timeToMerge           *durationHistogram
+	timeToFirstVerdict    *durationHistogram
 }
 
 var _ usecase.MetricsInterface = (*Metrics)(nil)
@@ -25,7 +37,8 @@ func NewMetrics(serviceName string) *Metrics {
 		"service": serviceName,
 	}
 
-	return &Metrics{
+	m := &Metrics{
+		service: serviceName,
 		teamCreated: promauto.NewCounter(prometheus.CounterOpts{
 			Namespace:   constNamespace,
 			Name:        "teams_created_total",
@@ -62,7 +75,52 @@ func NewMetrics(serviceName string) *Metrics {
 			Help:        "Total number of reviewer reassignments",
 			ConstLabels: commonLabels,
 		}),
+		openAssignmentsByUser: newGaugeSnapshot(
+			prometheus.BuildFQName(constNamespace, "", "open_review_assignments"),
+			"Open pull requests assigned to the reviewer",
+			"user_id",
+			commonLabels,
+		),
+		openAssignmentsByTeam: newGaugeSnapshot(
+			prometheus.BuildFQName(constNamespace, "", "team_open_review_assignments"),
+			"Open review assignments of the team members",
+			"team_name",
+			commonLabels,
+		),
+		openPullRequests: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace:   constNamespace,
+			Name:        "open_pull_requests",
+			Help:        "Number of open pull requests",
+			ConstLabels: commonLabels,
+		}),
+		unfilledPullRequests: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace:   constNamespace,
+			Name:        "unfilled_pull_requests",
+			Help:        "Open pull requests with fewer than two reviewers",
+			ConstLabels: commonLabels,
+		}),
+		openPullRequestAge: newDurationHistogram(
+			prometheus.BuildFQName(constNamespace, "", "open_pull_request_age_seconds"),
+			"Age of the open pull requests",
+			commonLabels,
+		),
+		timeToMerge: newDurationHistogram(
+			prometheus.BuildFQName(constNamespace, "", "time_to_merge_seconds"),
+			"Time from creation to merge of recently merged pull requests",
+			commonLabels,
+		),
+		timeToFirstVerdict: newDurationHistogram(
+			prometheus.BuildFQName(constNamespace, "", "time_to_first_verdict_seconds"),
+			"Time from assignment to the first approval or change request of recent verdicts",
+			commonLabels,
+		),
 	}
+	prometheus.MustRegister(
+		m.openAssignmentsByUser, m.openAssignmentsByTeam,
+		m.openPullRequestAge, m.timeToMerge, m.timeToFirstVerdict,
+	)
+
+	return m
 }
 
 func (m *Metrics) IncTeamCreated() {
@@ -88,3 +146,8 @@ func (m *Metrics) IncPullRequestMerged() {
 func (m *Metrics) IncPullRequestReassigned() {
 	m.prReassigned.Inc()
 }
+
+// IncBusinessOperation counts in business_operations_total of common/kit
+func (m *Metrics) IncBusinessOperation(operation, result string) {
+	metrics.IncBusinessOperation(m.service, operation, result)
+}
@@ -0,0 +1,104 @@
+package metricsadapter
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStater is the part of *pgxpool.Pool the pool collector reads
+type PoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolCollector reads the pool statistics on every scrape
+type poolCollector struct {
+	pool PoolStater
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquires             *prometheus.Desc
+	canceledAcquires     *prometheus.Desc
+	emptyAcquires        *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireDuration *prometheus.Desc
+	newConns             *prometheus.Desc
+	lifetimeDestroyed    *prometheus.Desc
+	idleDestroyed        *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*poolCollector)(nil)
+
+// RegisterPoolCollector exports the statistics of the Postgres pool
+func RegisterPoolCollector(serviceName string, pool PoolStater) {
+	prometheus.MustRegister(newPoolCollector(serviceName, pool))
+}
+
+func newPoolCollector(serviceName string, pool PoolStater) *poolCollector {
+	commonLabels := prometheus.Labels{
+		"service": serviceName,
+	}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("pr_manager", "db_pool", name), help, nil, commonLabels)
+	}
+
+	return &poolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_conns", "Connections currently acquired from the pool"),
+		idleConns:            desc("idle_conns", "Idle connections in the pool"),
+		constructingConns:    desc("constructing_conns", "Connections being established"),
+		totalConns:           desc("total_conns", "Connections in the pool, acquired, idle and constructing"),
+		maxConns:             desc("max_conns", "Maximum size of the pool"),
+		acquires:             desc("acquires_total", "Successful acquires from the pool"),
+		canceledAcquires:     desc("canceled_acquires_total", "Acquires canceled by the context"),
+		emptyAcquires:        desc("empty_acquires_total", "Acquires that waited for a connection"),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Total time spent acquiring connections"),
+		emptyAcquireDuration: desc("empty_acquire_wait_seconds_total", "Total time acquires waited for a connection"),
+		newConns:             desc("new_conns_total", "Connections opened by the pool"),
+		lifetimeDestroyed:    desc("max_lifetime_destroyed_total", "Connections closed by DB_MAX_CONN_LIFETIME"),
+		idleDestroyed:        desc("max_idle_destroyed_total", "Connections closed by DB_MAX_CONN_IDLE_TIME"),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.canceledAcquires
+	ch <- c.emptyAcquires
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireDuration
+	ch <- c.newConns
+	ch <- c.lifetimeDestroyed
+	ch <- c.idleDestroyed
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquiredConns, float64(s.AcquiredConns()))
+	gauge(c.idleConns, float64(s.IdleConns()))
+	gauge(c.constructingConns, float64(s.ConstructingConns()))
+	gauge(c.totalConns, float64(s.TotalConns()))
+	gauge(c.maxConns, float64(s.MaxConns()))
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.canceledAcquires, float64(s.CanceledAcquireCount()))
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount()))
+	counter(c.acquireDuration, s.AcquireDuration().Seconds())
+	counter(c.emptyAcquireDuration, s.EmptyAcquireWaitTime().Seconds())
+	counter(c.newConns, float64(s.NewConnsCount()))
+	counter(c.lifetimeDestroyed, float64(s.MaxLifetimeDestroyCount()))
+	counter(c.idleDestroyed, float64(s.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,44 @@
+package metricsadapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPoolCollector(t *testing.T) {
+	// the pool connects lazily, so no server is needed
+	cfg, err := pgxpool.ParseConfig("postgres://user@127.0.0.1:1/db?pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("new pool: %v", err)
+	}
+	defer pool.Close()
+
+	c := newPoolCollector("test", pool)
+	if n := testutil.CollectAndCount(c); n != 13 {
+		t.Fatalf("expected 13 metrics, got %d", n)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() == "pr_manager_db_pool_max_conns" {
+			if got := f.GetMetric()[0].GetGauge().GetValue(); got != 7 {
+				t.Fatalf("expected max_conns 7, got %v", got)
+			}
+			return
+		}
+	}
+	t.Fatal("pr_manager_db_pool_max_conns is missing")
+}
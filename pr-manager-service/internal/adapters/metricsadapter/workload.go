@@ -0,0 +1,122 @@
+package metricsadapter
+
+import (
+	"sync"
+	"time"
+
+	"pr-manager-service/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// durationBuckets of review ages in seconds, from an hour to a month
+var durationBuckets = []float64{
+	(time.Hour).Seconds(),
+	(4 * time.Hour).Seconds(),
+	(12 * time.Hour).Seconds(),
+	(24 * time.Hour).Seconds(),
+	(2 * 24 * time.Hour).Seconds(),
+	(4 * 24 * time.Hour).Seconds(),
+	(7 * 24 * time.Hour).Seconds(),
+	(14 * 24 * time.Hour).Seconds(),
+	(30 * 24 * time.Hour).Seconds(),
+}
+
+// durationHistogram exposes the latest snapshot of durations as a histogram.
+// It is replaced on every refresh, so unlike an observed histogram it shows
+// the current distribution and is read without rate().
+type durationHistogram struct {
+	desc *prometheus.Desc
+
+	mu      sync.Mutex
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+var _ prometheus.Collector = (*durationHistogram)(nil)
+
+func newDurationHistogram(name, help string, constLabels prometheus.Labels) *durationHistogram {
+	return &durationHistogram{
+		desc:    prometheus.NewDesc(name, help, nil, constLabels),
+		buckets: make(map[float64]uint64),
+	}
+}
+
+func (h *durationHistogram) set(durations []time.Duration) {
+	buckets := make(map[float64]uint64, len(durationBuckets))
+	var sum float64
+	for _, d := range durations {
+		seconds := d.Seconds()
+		sum += seconds
+		for _, upper := range durationBuckets {
+			if seconds <= upper {
+				buckets[upper]++
+			}
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count = uint64(len(durations))
+	h.sum = sum
+	h.buckets = buckets
+}
+
+func (h *durationHistogram) Describe(ch chan<- *prometheus.Desc) {
+	ch <- h.desc
+}
+
+func (h *durationHistogram) Collect(ch chan<- prometheus.Metric) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch <- prometheus.MustNewConstHistogram(h.desc, h.count, h.sum, h.buckets)
+}
+
+// gaugeSnapshot exposes the latest snapshot of counts as gauges with one
+// label. The snapshot is swapped at once, so a scrape never sees the gauges
+// half updated and labels missing from the new snapshot are dropped.
+type gaugeSnapshot struct {
+	desc *prometheus.Desc
+
+	mu     sync.Mutex
+	values map[string]int
+}
+
+var _ prometheus.Collector = (*gaugeSnapshot)(nil)
+
+func newGaugeSnapshot(name, help, label string, constLabels prometheus.Labels) *gaugeSnapshot {
+	return &gaugeSnapshot{
+		desc: prometheus.NewDesc(name, help, []string{label}, constLabels),
+	}
+}
+
+func (g *gaugeSnapshot) set(values map[string]int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values = values
+}
+
+func (g *gaugeSnapshot) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+func (g *gaugeSnapshot) Collect(ch chan<- prometheus.Metric) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for label, value := range g.values {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, float64(value), label)
+	}
+}
+
+// SetWorkload replaces the workload gauges, reviewers and teams missing
+// from the snapshot are dropped
+func (m *Metrics) SetWorkload(w *domain.Workload) {
+	m.openAssignmentsByUser.set(w.OpenAssignmentsByUser)
+	m.openAssignmentsByTeam.set(w.OpenAssignmentsByTeam)
+	m.openPullRequests.Set(float64(len(w.OpenPullRequestAges)))
+	m.unfilledPullRequests.Set(float64(w.UnfilledPullRequests))
+	m.openPullRequestAge.set(w.OpenPullRequestAges)
+	m.timeToMerge.set(w.MergeDurations)
+	m.timeToFirstVerdict.set(w.FirstVerdictDurations)
+}
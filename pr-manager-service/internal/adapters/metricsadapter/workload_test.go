@@ -0,0 +1,77 @@
+package metricsadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestDurationHistogram(t *testing.T) {
+	h := newDurationHistogram("test_age_seconds", "test", nil)
+
+	collect := func() *dto.Histogram {
+		t.Helper()
+		ch := make(chan prometheus.Metric, 1)
+		h.Collect(ch)
+		var m dto.Metric
+		if err := (<-ch).Write(&m); err != nil {
+			t.Fatalf("write metric: %v", err)
+		}
+		return m.GetHistogram()
+	}
+
+	h.set([]time.Duration{30 * time.Minute, 3 * time.Hour, 60 * 24 * time.Hour})
+	got := collect()
+	if got.GetSampleCount() != 3 {
+		t.Fatalf("expected 3 samples, got %d", got.GetSampleCount())
+	}
+	wantCumulative := map[float64]uint64{
+		time.Hour.Seconds():             1,
+		(4 * time.Hour).Seconds():       2,
+		(30 * 24 * time.Hour).Seconds(): 2,
+	}
+	for _, b := range got.GetBucket() {
+		if want, ok := wantCumulative[b.GetUpperBound()]; ok && b.GetCumulativeCount() != want {
+			t.Fatalf("bucket le=%v: expected %d, got %d", b.GetUpperBound(), want, b.GetCumulativeCount())
+		}
+	}
+
+	// a new snapshot replaces the previous one
+	h.set(nil)
+	if got := collect(); got.GetSampleCount() != 0 || got.GetSampleSum() != 0 {
+		t.Fatalf("expected an empty histogram, got %+v", got)
+	}
+}
+
+func TestGaugeSnapshot(t *testing.T) {
+	g := newGaugeSnapshot("test_open_review_assignments", "test", "user_id", nil)
+
+	collect := func() map[string]float64 {
+		t.Helper()
+		ch := make(chan prometheus.Metric, 10)
+		g.Collect(ch)
+		close(ch)
+		got := make(map[string]float64)
+		for metric := range ch {
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("write metric: %v", err)
+			}
+			got[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+		return got
+	}
+
+	g.set(map[string]int{"u1": 2, "u2": 1})
+	if got := collect(); len(got) != 2 || got["u1"] != 2 || got["u2"] != 1 {
+		t.Fatalf("unexpected gauges %v", got)
+	}
+
+	// a reviewer missing from the new snapshot is dropped
+	g.set(map[string]int{"u1": 3})
+	if got := collect(); len(got) != 1 || got["u1"] != 3 {
+		t.Fatalf("unexpected gauges after refresh %v", got)
+	}
+}
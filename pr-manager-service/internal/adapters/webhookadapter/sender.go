@@ -0,0 +1,90 @@
+package webhookadapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	"pr-manager-service/internal/usecase"
+)
+
+const (
+	headerEvent     = "X-PRM-Event"
+	headerDelivery  = "X-PRM-Delivery"
+	headerSignature = "X-PRM-Signature-256"
+)
+
+type Sender struct {
+	client    *http.Client
+	userAgent string
+}
+
+var _ usecase.WebhookSenderInterface = (*Sender)(nil)
+
+func NewSender(timeout time.Duration, userAgent string) *Sender {
+	return &Sender{
+		client:    &http.Client{Timeout: timeout},
+		userAgent: userAgent,
+	}
+}
+
+type envelopeJSON struct {
+	EventId    int64           `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Send posts the event to the subscription url.
+// Body is signed with HMAC-SHA256 of the subscription secret.
+func (s *Sender) Send(ctx context.Context, d domain.WebhookDelivery) error {
+	body, err := json.Marshal(envelopeJSON{
+		EventId:    d.EventId,
+		EventType:  d.EventType,
+		OccurredAt: d.OccurredAt.UTC(),
+		Data:       json.RawMessage(d.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Subscription.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set(headerEvent, d.EventType)
+	req.Header.Set(headerDelivery, strconv.FormatInt(d.Id, 10))
+	req.Header.Set(headerSignature, Sign(d.Subscription.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for the body: "sha256=<hex>"
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -3,75 +3,296 @@ package app
 import (
 	"context"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"pr-manager-service/config"
 
+	chatadapter "pr-manager-service/internal/adapters/chatadapter"
+	emailadapter "pr-manager-service/internal/adapters/emailadapter"
+	grpcadapter "pr-manager-service/internal/adapters/grpcadapter"
 	httpadapter "pr-manager-service/internal/adapters/httpadapter"
 	metricsadapter "pr-manager-service/internal/adapters/metricsadapter"
-	repo "pr-manager-service/internal/repository"
+	tracingadapter "pr-manager-service/internal/adapters/tracingadapter"
+	webhookadapter "pr-manager-service/internal/adapters/webhookadapter"
 	uc "pr-manager-service/internal/usecase"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
 	"github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/metrics"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
 func Run(ctx context.Context, cfg *config.Config) error {
 	// logger
-	l := kitlogger.NewLogger(
-		cfg.Log.Level,
-		map[string]any{
-			"service": cfg.App.Name,
-			"version": cfg.App.Version,
-		},
-	)
+	l := newLogger(cfg)
 
 	l.Info("start configuration", nil)
 
 	// metrics
-	metrics.InitMetrics()
+	metrics.InitMetrics(
+		metrics.WithDurationBuckets(cfg.Metrics.DurationBuckets),
+		metrics.WithSizeBuckets(cfg.Metrics.SizeBuckets),
+	)
+
+	// tracing
+	shutdownTracing, err := tracingadapter.Setup(ctx, tracingadapter.Config{
+		Exporter:       cfg.Tracing.Exporter,
+		OTLPEndpoint:   cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure:   cfg.Tracing.OTLPInsecure,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+		ServiceName:    cfg.App.Name,
+		ServiceVersion: cfg.App.Version,
+	})
+	if err != nil {
+		l.Error("unable to set up tracing", map[string]any{
+			"tracing.exporter": cfg.Tracing.Exporter,
+			"error":            err.Error(),
+		})
+		return err
+	}
+	defer func() {
+		// flush spans still in the batch
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			l.Error("unable to flush spans", map[string]any{
+				"error": err.Error(),
+			})
+		}
+	}()
 
 	// business metrics adapter
 	businessMetrics := metricsadapter.NewMetrics(cfg.App.Name)
 
-	// postgresql
-	sslMode := "require"
-	if !cfg.PostgreSQL.SslEnabled {
-		sslMode = "disable"
+	// storage
+	store, err := newStorage(ctx, cfg, l)
+	if err != nil {
+		l.Error("unable to create storage", map[string]any{
+			"db.driver": cfg.Storage.Driver,
+			"error":     err.Error(),
+		})
+		return err
+	}
+	defer store.close()
+	if store.pgPool != nil {
+		metricsadapter.RegisterPoolCollector(cfg.App.Name, store.pgPool)
 	}
-	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.PostgreSQL.User, cfg.PostgreSQL.Password, cfg.PostgreSQL.Host,
-		cfg.PostgreSQL.Port, cfg.PostgreSQL.Name, sslMode)
-	pool, err := pgxpool.New(ctx, dbUrl)
+
+	// chat notifications
+	notifier := chatadapter.NewNotifier(chatadapter.NotifierConfig{
+		SlackWebhookUrl:      cfg.Chat.SlackWebhookUrl,
+		MattermostWebhookUrl: cfg.Chat.MattermostWebhookUrl,
+		Username:             cfg.Chat.Username,
+		Timeout:              cfg.Chat.Timeout,
+		QueueSize:            cfg.Chat.QueueSize,
+		RatePerSecond:        cfg.Chat.RatePerSecond,
+	}, l)
+
+	// email digest
+	mailer := emailadapter.NewSMTPMailer(emailadapter.SMTPConfig{
+		Host:      cfg.Email.SMTPHost,
+		Port:      cfg.Email.SMTPPort,
+		Username:  cfg.Email.SMTPUsername,
+		Password:  cfg.Email.SMTPPassword,
+		From:      cfg.Email.From,
+		StartTLS:  cfg.Email.SMTPStartTLS,
+		Timeout:   cfg.Email.SMTPTimeout,
+		PublicUrl: cfg.Email.PublicUrl,
+	})
+
+	// rate limits
+	rateLimits, rateLimited, err := routerRateLimits(cfg.RateLimit)
 	if err != nil {
-		l.Error("unable to create connection pool", map[string]any{
+		l.Error("invalid rate limits", map[string]any{
 			"error": err.Error(),
 		})
 		return err
 	}
-	defer pool.Close()
-
-	// repositories
-	teamRepo := repo.NewTeamRepository(pool)
-	userRepo := repo.NewUserRepository(pool)
-	prRepo := repo.NewPullRequestRepository(pool)
 
 	// usecase
-	usecase := uc.NewService(teamRepo, userRepo, prRepo, l, businessMetrics)
+	serviceOpts := store.serviceOptions(notifier, mailer, cfg.Idempotency.TTL, cfg.Idempotency.Lease, cfg.Workload.MergedWindow)
+	if rateLimited {
+		serviceOpts = append(serviceOpts, uc.WithRateLimits(store.rateLimits(cfg.RateLimit.Backend)))
+	}
+	usecase := uc.NewService(store.teams, store.users, store.prs, l, businessMetrics, serviceOpts...)
+
+	// background workers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workersWg sync.WaitGroup
+
+	if store.listen != nil {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			store.listen(workersCtx)
+		}()
+	}
+
+	if store.webhooks != nil {
+		webhookWorker := uc.NewWebhookWorker(
+			store.webhooks,
+			webhookadapter.NewSender(cfg.Webhooks.Timeout, cfg.App.Name+"/"+cfg.App.Version),
+			l,
+			uc.WebhookWorkerConfig{
+				PollInterval: cfg.Webhooks.PollInterval,
+				BatchSize:    cfg.Webhooks.BatchSize,
+				MaxAttempts:  cfg.Webhooks.MaxAttempts,
+				BackoffBase:  cfg.Webhooks.BackoffBase,
+				BackoffMax:   cfg.Webhooks.BackoffMax,
+				Lease:        2 * cfg.Webhooks.Timeout,
+
+				OutboxRetention: cfg.Webhooks.OutboxRetention,
+			},
+		)
+		if cfg.Webhooks.WorkerEnabled {
+			workersWg.Add(1)
+			go func() {
+				defer workersWg.Done()
+				l.Info("start webhook worker", map[string]any{
+					"poll_interval": cfg.Webhooks.PollInterval.String(),
+				})
+				webhookWorker.Run(workersCtx)
+			}()
+		}
+
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			runEvery(workersCtx, cfg.Webhooks.OutboxCleanupInterval, func(ctx context.Context) {
+				_, _ = webhookWorker.DeleteProcessedEvents(ctx)
+				// without a worker nothing fans the events out
+				if !cfg.Webhooks.WorkerEnabled {
+					_, _ = webhookWorker.DeleteUnprocessedEvents(ctx)
+				}
+			})
+		}()
+	}
+
+	workersWg.Add(1)
+	go func() {
+		defer workersWg.Done()
+		notifier.Run(workersCtx)
+	}()
+
+	if store.idempotency != nil {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			runEvery(workersCtx, cfg.Idempotency.CleanupInterval, func(ctx context.Context) {
+				_, _ = usecase.DeleteExpiredIdempotencyKeys(ctx)
+			})
+		}()
+	}
+
+	if rateLimited {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			l.Info("start rate limit bucket cleanup", map[string]any{
+				"rate_limit.backend": cfg.RateLimit.Backend,
+			})
+			runEvery(workersCtx, cfg.RateLimit.CleanupInterval, func(ctx context.Context) {
+				_, _ = usecase.DeleteExpiredRateLimitBuckets(ctx)
+			})
+		}()
+	}
+
+	if store.workload != nil {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			refresh := func(ctx context.Context) {
+				_ = usecase.RefreshWorkloadMetrics(ctx)
+			}
+			// gauges are filled right away, not after the first interval
+			refresh(workersCtx)
+			runEvery(workersCtx, cfg.Workload.RefreshInterval, refresh)
+		}()
+	}
+
+	if cfg.Chat.DigestEnabled {
+		digestAt, err := parseTimeOfDay(cfg.Chat.DigestTime)
+		if err != nil {
+			l.Error("invalid chat digest time", map[string]any{
+				"error": err.Error(),
+			})
+			return err
+		}
+
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			l.Info("start chat digest scheduler", map[string]any{
+				"digest_time": cfg.Chat.DigestTime,
+			})
+			runDaily(workersCtx, digestAt, func(ctx context.Context) {
+				_, _ = usecase.SendChatDigest(ctx)
+			})
+		}()
+	}
+
+	if cfg.Email.DigestEnabled {
+		digestAt, err := parseTimeOfDay(cfg.Email.DigestTime)
+		if err != nil {
+			l.Error("invalid email digest time", map[string]any{
+				"error": err.Error(),
+			})
+			return err
+		}
+
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			l.Info("start email digest scheduler", map[string]any{
+				"digest_time": cfg.Email.DigestTime,
+			})
+			runDaily(workersCtx, digestAt, func(ctx context.Context) {
+				_, _ = usecase.SendEmailDigest(ctx)
+			})
+		}()
+	}
 
 	// http
-	httpMux := httpadapter.NewRouter(usecase, cfg.App.Name, cfg.App.Version)
+	readiness := httpadapter.NewReadiness(cfg.HTTP.ReadinessTimeout, store.checks...)
+	routerOpts := []httpadapter.RouterOption{
+		httpadapter.WithIntegrationSecrets(cfg.Integrations.GitHubWebhookSecret, cfg.Integrations.GitLabWebhookToken),
+		httpadapter.WithStreamsContext(workersCtx),
+		httpadapter.WithReadiness(readiness),
+		httpadapter.WithMaxBodyBytes(cfg.HTTP.MaxBodyBytes),
+	}
+	if rateLimited {
+		routerOpts = append(routerOpts, httpadapter.WithRateLimits(rateLimits))
+	}
+	if cfg.HTTP.OpenAPISpecPath != "" {
+		validator, err := httpadapter.LoadOpenAPIValidator(cfg.HTTP.OpenAPISpecPath)
+		if err != nil {
+			l.Error("failed to load openapi spec", map[string]any{
+				"error": err.Error(),
+				"path":  cfg.HTTP.OpenAPISpecPath,
+			})
+			return err
+		}
+		routerOpts = append(routerOpts, httpadapter.WithOpenAPI(validator))
+		l.Info("validate requests against openapi spec", map[string]any{
+			"path": cfg.HTTP.OpenAPISpecPath,
+		})
+	}
+	httpMux := httpadapter.NewRouter(usecase, cfg.App.Name, cfg.App.Version, routerOpts...)
 	httpMux.Handle("/metrics", promhttp.Handler())
 
-	handlerWithMetrics := metrics.HTTPMiddleware(cfg.App.Name, httpMux)
+	handlerWithRecovery := httpadapter.Chain(httpMux, httpadapter.Recover(l))
+	handlerWithMetrics := metrics.HTTPMiddleware(cfg.App.Name, handlerWithRecovery,
+		metrics.WithRoute(metrics.ServeMuxRoute(httpMux)),
+	)
+	handlerWithLogging := kitlogger.RequestIDMiddleware(httpadapter.UserContext(handlerWithMetrics))
+	handlerWithTracing := httpadapter.TraceHandler(httpMux, handlerWithLogging)
 
 	httpAddr := ":" + cfg.HTTP.Port
-	httpServer := httpadapter.NewServer(httpAddr, handlerWithMetrics)
+	httpServer := httpadapter.NewServer(httpAddr, handlerWithTracing)
 
 	httpErrCh := make(chan error, 1)
 	go func() {
@@ -83,25 +304,61 @@ func Run(ctx context.Context, cfg *config.Config) error {
 		}
 	}()
 
+	// grpc
+	var grpcServer *grpc.Server
+	grpcErrCh := make(chan error, 1)
+	if cfg.GRPC.Enabled {
+		grpcAddr := ":" + cfg.GRPC.Port
+		grpcListener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			l.Error("unable to listen grpc address", map[string]any{
+				"grpc.addr": grpcAddr,
+				"error":     err.Error(),
+			})
+			return err
+		}
+
+		grpcServer = grpcadapter.NewServer(usecase, cfg.App.Name, cfg.App.Version,
+			grpcadapter.WithStreamsContext(workersCtx),
+		)
+
+		go func() {
+			l.Info("start grpc server", map[string]any{
+				"grpc.addr": grpcAddr,
+			})
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				grpcErrCh <- err
+			}
+		}()
+	}
+
 	l.Info("pr-manager-service service started", map[string]any{
-		"http.port": cfg.HTTP.Port,
-		"log.level": cfg.Log.Level,
-		"db.name":   cfg.PostgreSQL.Name,
-		"db.host":   cfg.PostgreSQL.Host,
-		"db.port":   cfg.PostgreSQL.Port,
+		"http.port":        cfg.HTTP.Port,
+		"log.level":        cfg.Log.Level,
+		"db.driver":        cfg.Storage.Driver,
+		"db.name":          cfg.PostgreSQL.Name,
+		"db.host":          cfg.PostgreSQL.Host,
+		"db.port":          cfg.PostgreSQL.Port,
+		"tracing.exporter": cfg.Tracing.Exporter,
 	})
 
 	// gracefull shutdown
 	select {
 	case <-ctx.Done():
-		l.Info("starting graceful shutdown", nil)
+		// fail readiness first, so load balancers drain the instance
+		readiness.SetShuttingDown()
+		l.Info("starting graceful shutdown", map[string]any{
+			"drain_delay": cfg.HTTP.ShutdownDrainDelay.String(),
+		})
+		time.Sleep(cfg.HTTP.ShutdownDrainDelay)
+
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 		done := make(chan struct{})
 
 		go func() {
 			var wg sync.WaitGroup
-			wg.Add(1)
+			wg.Add(3)
 
 			// http server
 			go func() {
@@ -113,6 +370,21 @@ func Run(ctx context.Context, cfg *config.Config) error {
 				}
 			}()
 
+			// grpc server
+			go func() {
+				defer wg.Done()
+				if grpcServer != nil {
+					grpcServer.GracefulStop()
+				}
+			}()
+
+			// background workers
+			go func() {
+				defer wg.Done()
+				stopWorkers()
+				workersWg.Wait()
+			}()
+
 			wg.Wait()
 			close(done)
 		}()
@@ -128,6 +400,9 @@ func Run(ctx context.Context, cfg *config.Config) error {
 					"error": err.Error(),
 				})
 			}
+			if grpcServer != nil {
+				grpcServer.Stop()
+			}
 			err := errors.New("graceful shutdown timeout")
 			l.Error("graceful shutdown error", map[string]any{
 				"error": err.Error(),
@@ -139,5 +414,10 @@ func Run(ctx context.Context, cfg *config.Config) error {
 			"error": err.Error(),
 		})
 		return err
+	case err := <-grpcErrCh:
+		l.Error("grpc server error", map[string]any{
+			"error": err.Error(),
+		})
+		return err
 	}
 }
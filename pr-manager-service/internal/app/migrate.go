@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"pr-manager-service/config"
+	"pr-manager-service/migrations"
+
+	repo "pr-manager-service/internal/repository"
+	"pr-manager-service/internal/repository/sqlite"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
+)
+
+// MigrateUp applies pending migrations of the configured DB_DRIVER
+func MigrateUp(ctx context.Context, cfg *config.Config) error {
+	l := newLogger(cfg)
+
+	switch cfg.Storage.Driver {
+	case config.DriverSQLite:
+		// the database is migrated when opened
+		db, err := sqlite.Open(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return err
+		}
+		l.Info("database migrated", map[string]any{
+			"db.driver": cfg.Storage.Driver,
+		})
+		return db.Close()
+	case config.DriverMemory:
+		l.Info("nothing to migrate", map[string]any{
+			"db.driver": cfg.Storage.Driver,
+		})
+		return nil
+	default:
+		pool, err := newPostgresPool(ctx, cfg.PostgreSQL)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+
+		return prepareSchema(ctx, pool, true, l)
+	}
+}
+
+// MigrateDown rolls back the given number of Postgres migrations
+func MigrateDown(ctx context.Context, cfg *config.Config, steps int) error {
+	if cfg.Storage.Driver != config.DriverPostgres {
+		return fmt.Errorf("rolling back migrations is not supported for DB_DRIVER=%s", cfg.Storage.Driver)
+	}
+	l := newLogger(cfg)
+
+	pool, err := newPostgresPool(ctx, cfg.PostgreSQL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := repo.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	rolledBack, err := migrator.Down(ctx, steps)
+	if err != nil {
+		return err
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.Info("migrations rolled back", map[string]any{
+		"rolled_back":    rolledBack,
+		"schema_version": version,
+	})
+	return nil
+}
+
+// prepareSchema applies pending migrations when autoMigrate is set and
+// refuses a schema migrated past the migrations embedded into the binary
+func prepareSchema(ctx context.Context, pool *pgxpool.Pool, autoMigrate bool, l uc.LoggerInterface) error {
+	migrator, err := repo.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	if autoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			l.Info("migrations applied", map[string]any{
+				"applied": applied,
+			})
+		}
+	}
+
+	version, err := migrator.CheckVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version < migrator.Latest() {
+		l.Warn("database schema is behind the service", map[string]any{
+			"schema_version": version,
+			"latest_version": migrator.Latest(),
+		})
+	}
+	return nil
+}
+
+func newLogger(cfg *config.Config) *kitlogger.Logger {
+	return kitlogger.NewLogger(
+		cfg.Log.Level,
+		map[string]any{
+			"service": cfg.App.Name,
+			"version": cfg.App.Version,
+		},
+	)
+}
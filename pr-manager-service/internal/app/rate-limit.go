@@ -0,0 +1,39 @@
+package app
+
+import (
+	"pr-manager-service/config"
+
+	httpadapter "pr-manager-service/internal/adapters/httpadapter"
+	uc "pr-manager-service/internal/usecase"
+)
+
+// Converts RATE_LIMIT_DEFAULT and RATE_LIMIT_ROUTES to the limits of the router,
+// false if no route is limited
+func routerRateLimits(cfg config.RateLimit) (httpadapter.RateLimits, bool, error) {
+	limits := httpadapter.RateLimits{Routes: make(map[string]uc.RateLimitDTO)}
+
+	defaultRate, err := cfg.DefaultRate()
+	if err != nil {
+		return limits, false, err
+	}
+	if defaultRate != nil {
+		limits.Default = rateLimitDTO(*defaultRate)
+	}
+
+	routeRates, err := cfg.RouteRates()
+	if err != nil {
+		return limits, false, err
+	}
+	for route, rate := range routeRates {
+		limits.Routes[route] = rateLimitDTO(rate)
+	}
+
+	return limits, defaultRate != nil || len(routeRates) > 0, nil
+}
+
+func rateLimitDTO(rate config.Rate) uc.RateLimitDTO {
+	return uc.RateLimitDTO{
+		Burst:  rate.Requests,
+		Period: rate.Period,
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pr-manager-service/migrations"
+
+	httpadapter "pr-manager-service/internal/adapters/httpadapter"
+	repo "pr-manager-service/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresChecks ping the pool and require the schema at the newest
+// embedded migration
+func postgresChecks(pool *pgxpool.Pool) ([]httpadapter.ReadinessCheck, error) {
+	migrator, err := repo.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	return []httpadapter.ReadinessCheck{
+		{Name: "postgres", Check: pool.Ping},
+		{Name: "migrations", Check: func(ctx context.Context) error {
+			version, err := migrator.CheckVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if version != migrator.Latest() {
+				return fmt.Errorf("schema is at version %d, the service needs %d", version, migrator.Latest())
+			}
+			return nil
+		}},
+	}, nil
+}
+
+// SQLite migrations are applied by sqlite.Open, only the file is checked
+func sqliteChecks(db *sql.DB) []httpadapter.ReadinessCheck {
+	return []httpadapter.ReadinessCheck{
+		{Name: "sqlite", Check: db.PingContext},
+	}
+}
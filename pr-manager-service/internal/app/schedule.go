@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Parses a UTC time of day in HH:MM format
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Returns the first moment after now at the given UTC time of day
+func nextDailyRun(now time.Time, at time.Duration) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(at)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Calls job once a day at the given UTC time of day until ctx is cancelled
+func runDaily(ctx context.Context, at time.Duration, job func(ctx context.Context)) {
+	for {
+		timer := time.NewTimer(time.Until(nextDailyRun(time.Now(), at)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			job(ctx)
+		}
+	}
+}
+
+// Calls job every interval until ctx is cancelled
+func runEvery(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
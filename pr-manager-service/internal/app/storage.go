@@ -0,0 +1,242 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pr-manager-service/config"
+
+	eventbroker "pr-manager-service/internal/adapters/eventbroker"
+	httpadapter "pr-manager-service/internal/adapters/httpadapter"
+	repo "pr-manager-service/internal/repository"
+	"pr-manager-service/internal/repository/inmemory"
+	"pr-manager-service/internal/repository/sqlite"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// storage holds the repositories of the configured DB_DRIVER.
+// Repositories of features the driver does not support are nil.
+type storage struct {
+	teams uc.TeamRepositoryInterface
+	users uc.UserRepositoryInterface
+	prs   uc.PullRequestRepositoryInterface
+
+	webhooks   uc.WebhookRepositoryInterface
+	identities uc.IdentityRepositoryInterface
+	verdicts   uc.ReviewVerdictRepositoryInterface
+	chats      uc.ChatHandleRepositoryInterface
+	emails     uc.EmailSubscriptionRepositoryInterface
+	rosters    uc.RosterRepositoryInterface
+	snapshots  uc.SnapshotRepositoryInterface
+
+	idempotency uc.IdempotencyRepositoryInterface
+	workload    uc.WorkloadRepositoryInterface
+	// rate limit buckets shared between replicas, postgres only
+	sharedRateLimits uc.RateLimitRepositoryInterface
+
+	events uc.EventBrokerInterface
+	// listen receives events of other replicas until ctx is done, nil if not needed
+	listen func(ctx context.Context)
+
+	// dependencies of /readyz
+	checks []httpadapter.ReadinessCheck
+	// pool of the postgres driver, its stats are exported as metrics
+	pgPool *pgxpool.Pool
+
+	close func()
+}
+
+func newStorage(ctx context.Context, cfg *config.Config, l uc.LoggerInterface) (*storage, error) {
+	switch cfg.Storage.Driver {
+	case config.DriverSQLite:
+		return newSQLiteStorage(ctx, cfg.SQLite)
+	case config.DriverMemory:
+		return newMemoryStorage(), nil
+	default:
+		return newPostgresStorage(ctx, cfg.PostgreSQL, l)
+	}
+}
+
+func newPostgresPool(ctx context.Context, cfg config.PostgreSQL) (*pgxpool.Pool, error) {
+	sslMode := "require"
+	if !cfg.SslEnabled {
+		sslMode = "disable"
+	}
+	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, sslMode)
+	poolCfg, err := pgxpool.ParseConfig(dbUrl)
+	if err != nil {
+		return nil, err
+	}
+	// every query gets a span when tracing is enabled
+	poolCfg.ConnConfig.Tracer = repo.NewQueryTracer()
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	poolCfg.MinConns = cfg.MinConns
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	if cfg.ConnectTimeout > 0 {
+		poolCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	}
+	poolCfg.ConnConfig.DefaultQueryExecMode = queryExecModes[cfg.QueryExecMode]
+	if cfg.StatementCacheCapacity > 0 {
+		poolCfg.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+		poolCfg.ConnConfig.DescriptionCacheCapacity = cfg.StatementCacheCapacity
+	}
+	return pgxpool.NewWithConfig(ctx, poolCfg)
+}
+
+var queryExecModes = map[string]pgx.QueryExecMode{
+	config.QueryExecModeCacheStatement: pgx.QueryExecModeCacheStatement,
+	config.QueryExecModeCacheDescribe:  pgx.QueryExecModeCacheDescribe,
+	config.QueryExecModeDescribeExec:   pgx.QueryExecModeDescribeExec,
+	config.QueryExecModeExec:           pgx.QueryExecModeExec,
+	config.QueryExecModeSimpleProtocol: pgx.QueryExecModeSimpleProtocol,
+}
+
+func newPostgresStorage(ctx context.Context, cfg config.PostgreSQL, l uc.LoggerInterface) (*storage, error) {
+	pool, err := newPostgresPool(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = prepareSchema(ctx, pool, cfg.AutoMigrate, l); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	checks, err := postgresChecks(pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	// live events shared between replicas
+	broker := eventbroker.NewPostgresBroker(pool, l)
+
+	// per-call timeout and retries of transient errors
+	opts := []repo.Option{
+		repo.WithQueryTimeout(cfg.QueryTimeout),
+		repo.WithRetry(cfg.RetryMaxAttempts, cfg.RetryBackoff),
+	}
+
+	return &storage{
+		teams:       repo.NewTeamRepository(pool, opts...),
+		users:       repo.NewUserRepository(pool, opts...),
+		prs:         repo.NewPullRequestRepository(pool, opts...),
+		webhooks:    repo.NewWebhookRepository(pool, opts...),
+		identities:  repo.NewIdentityRepository(pool, opts...),
+		verdicts:    repo.NewReviewVerdictRepository(pool, opts...),
+		chats:       repo.NewChatHandleRepository(pool, opts...),
+		emails:      repo.NewEmailSubscriptionRepository(pool, opts...),
+		rosters:     repo.NewRosterRepository(pool),
+		snapshots:   repo.NewSnapshotRepository(pool),
+		idempotency: repo.NewIdempotencyRepository(pool, opts...),
+		workload:    repo.NewWorkloadRepository(pool, opts...),
+		events:      broker,
+		listen:      broker.Listen,
+		checks:      checks,
+		pgPool:      pool,
+		close:       pool.Close,
+
+		sharedRateLimits: repo.NewRateLimitRepository(pool, opts...),
+	}, nil
+}
+
+// Single binary mode: teams, users, pull requests, roster import, idempotency keys
+// and workload, events are delivered within this process only.
+// Webhooks, identities, review verdicts, chat handles, email subscriptions and snapshots
+// are not stored, the usecase answers ErrNotConfigured for them.
+func newSQLiteStorage(ctx context.Context, cfg config.SQLite) (*storage, error) {
+	db, err := sqlite.Open(ctx, cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{
+		teams:       sqlite.NewTeamRepository(db),
+		users:       sqlite.NewUserRepository(db),
+		prs:         sqlite.NewPullRequestRepository(db),
+		rosters:     sqlite.NewRosterRepository(db),
+		idempotency: sqlite.NewIdempotencyRepository(db),
+		workload:    sqlite.NewWorkloadRepository(db),
+		events:      eventbroker.NewLocalBroker(),
+		checks:      sqliteChecks(db),
+		close: func() {
+			_ = db.Close()
+		},
+	}, nil
+}
+
+// Demo mode: teams, users, pull requests, idempotency keys and workload, lost on restart.
+// Like sqlite it has no webhooks, identities, review verdicts, chat handles, email
+// subscriptions and snapshots, and no roster import either.
+func newMemoryStorage() *storage {
+	store := inmemory.NewStore()
+
+	return &storage{
+		teams:       inmemory.NewTeamRepository(store),
+		users:       inmemory.NewUserRepository(store),
+		prs:         inmemory.NewPullRequestRepository(store),
+		idempotency: inmemory.NewIdempotencyRepository(store),
+		workload:    inmemory.NewWorkloadRepository(store),
+		events:      eventbroker.NewLocalBroker(),
+		close:       func() {},
+	}
+}
+
+// rateLimits returns the repository of the RATE_LIMIT_BACKEND buckets,
+// the memory backend keeps them per replica whatever the driver is
+func (s *storage) rateLimits(backend string) uc.RateLimitRepositoryInterface {
+	if backend == config.RateLimitBackendPostgres && s.sharedRateLimits != nil {
+		return s.sharedRateLimits
+	}
+	return inmemory.NewRateLimitRepository(inmemory.NewStore())
+}
+
+// serviceOptions enables the usecase features the storage supports
+func (s *storage) serviceOptions(notifier uc.NotifierInterface, mailer uc.MailerInterface, idempotencyTTL, idempotencyLease, mergedWindow time.Duration) []uc.ServiceOption {
+	opts := []uc.ServiceOption{uc.WithEventBroker(s.events)}
+
+	if s.webhooks != nil {
+		opts = append(opts, uc.WithWebhooks(s.webhooks))
+	}
+	if s.identities != nil {
+		opts = append(opts, uc.WithIdentities(s.identities))
+	}
+	if s.verdicts != nil {
+		opts = append(opts, uc.WithReviewVerdicts(s.verdicts))
+	}
+	if s.chats != nil {
+		opts = append(opts, uc.WithNotifier(notifier, s.chats))
+	}
+	if s.emails != nil {
+		opts = append(opts, uc.WithEmailDigest(s.emails, mailer))
+	}
+	if s.rosters != nil {
+		opts = append(opts, uc.WithRoster(s.rosters))
+	}
+	if s.snapshots != nil {
+		opts = append(opts, uc.WithSnapshots(s.snapshots))
+	}
+	if s.idempotency != nil {
+		opts = append(opts, uc.WithIdempotency(s.idempotency, idempotencyTTL, idempotencyLease))
+	}
+	if s.workload != nil {
+		opts = append(opts, uc.WithWorkload(s.workload, mergedWindow))
+	}
+	return opts
+}
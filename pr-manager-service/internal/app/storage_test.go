@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	kitlogger "github.com/nikitadev-work/avito-test-task-internship-autumn-2025/common/kit/logger"
+
+	"pr-manager-service/config"
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type noopMetrics struct{}
+
+func (noopMetrics) IncTeamCreated()                               {}
+func (noopMetrics) IncUserActivated()                             {}
+func (noopMetrics) IncUserDeactivated()                           {}
+func (noopMetrics) IncPullRequestCreated()                        {}
+func (noopMetrics) IncPullRequestMerged()                         {}
+func (noopMetrics) IncPullRequestReassigned()                     {}
+func (noopMetrics) IncBusinessOperation(operation, result string) {}
+func (noopMetrics) SetWorkload(w *domain.Workload)                {}
+
+// Features a driver does not store must answer ErrNotConfigured, not panic
+func TestStorage_UnsupportedFeatures(t *testing.T) {
+	ctx := context.Background()
+	l := kitlogger.NewLogger("error", nil)
+
+	sqliteStore, err := newSQLiteStorage(ctx, config.SQLite{Path: filepath.Join(t.TempDir(), "prm.db")})
+	if err != nil {
+		t.Fatalf("open sqlite storage: %v", err)
+	}
+	defer sqliteStore.close()
+
+	drivers := []struct {
+		name  string
+		store *storage
+		// roster import and export are stored by sqlite only
+		rosters bool
+	}{
+		{name: config.DriverSQLite, store: sqliteStore, rosters: true},
+		{name: config.DriverMemory, store: newMemoryStorage()},
+	}
+
+	for _, d := range drivers {
+		t.Run(d.name, func(t *testing.T) {
+			s := d.store
+			svc := uc.NewService(s.teams, s.users, s.prs, l, noopMetrics{}, s.serviceOptions(nil, nil, 0, 0, 0)...)
+
+			calls := map[string]func() error{
+				"CreateWebhookSubscription": func() error {
+					_, err := svc.CreateWebhookSubscription(ctx, uc.CreateWebhookSubscriptionInput{
+						Url:        "https://hooks.example.com/prm",
+						Secret:     "s3cret",
+						EventTypes: []string{domain.EventPullRequestMerged},
+					})
+					return err
+				},
+				"ListWebhookSubscriptions": func() error {
+					_, err := svc.ListWebhookSubscriptions(ctx)
+					return err
+				},
+				"DeleteWebhookSubscription": func() error {
+					return svc.DeleteWebhookSubscription(ctx, uc.DeleteWebhookSubscriptionInput{Id: 1})
+				},
+				"SetIdentity": func() error {
+					_, err := svc.SetIdentity(ctx, uc.SetIdentityInput{Provider: domain.ProviderGitHub, Login: "alice", UserId: "u1"})
+					return err
+				},
+				"HandleProviderPullRequestEvent": func() error {
+					_, err := svc.HandleProviderPullRequestEvent(ctx, uc.ProviderPullRequestEventInput{
+						Provider:        domain.ProviderGitHub,
+						Action:          uc.ProviderActionOpened,
+						PullRequestId:   "github:acme/payments#1",
+						PullRequestName: "Add search",
+						AuthorLogin:     "alice",
+					})
+					return err
+				},
+				"HandleProviderPullRequestEvent reviewed": func() error {
+					_, err := svc.HandleProviderPullRequestEvent(ctx, uc.ProviderPullRequestEventInput{
+						Provider:      domain.ProviderGitHub,
+						Action:        uc.ProviderActionReviewed,
+						PullRequestId: "github:acme/payments#1",
+						ReviewerLogin: "bob",
+					})
+					return err
+				},
+				"SetChatHandle": func() error {
+					_, err := svc.SetChatHandle(ctx, uc.SetChatHandleInput{UserId: "u1", Provider: domain.ChatProviderSlack, Handle: "alice"})
+					return err
+				},
+				"SendChatDigest": func() error {
+					_, err := svc.SendChatDigest(ctx)
+					return err
+				},
+				"SetEmailSubscription": func() error {
+					_, err := svc.SetEmailSubscription(ctx, uc.SetEmailSubscriptionInput{UserId: "u1", Email: "alice@example.com"})
+					return err
+				},
+				"SendEmailDigest": func() error {
+					_, err := svc.SendEmailDigest(ctx)
+					return err
+				},
+				"ExportSnapshot": func() error {
+					return svc.ExportSnapshot(ctx, nil)
+				},
+				"RestoreSnapshot": func() error {
+					_, err := svc.RestoreSnapshot(ctx, uc.RestoreSnapshotInput{Snapshot: &domain.Snapshot{
+						Header: domain.SnapshotHeader{FormatVersion: domain.SnapshotFormatVersion},
+					}})
+					return err
+				},
+			}
+			if !d.rosters {
+				calls["ImportRoster"] = func() error {
+					_, err := svc.ImportRoster(ctx, uc.ImportRosterInput{})
+					return err
+				}
+				calls["ExportRoster"] = func() error {
+					_, err := svc.ExportRoster(ctx)
+					return err
+				}
+			}
+
+			for name, call := range calls {
+				if err := call(); !errors.Is(err, uc.ErrNotConfigured) {
+					t.Errorf("%s: expected ErrNotConfigured, got %v", name, err)
+				}
+			}
+		})
+	}
+}
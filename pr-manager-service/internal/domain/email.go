@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// EmailSubscription holds a user's email address and digest preference
+type EmailSubscription struct {
+	UserId           string
+	Email            string
+	DigestEnabled    bool
+	UnsubscribeToken string
+}
+
+// EmailDigest lists a user's open review assignments
+type EmailDigest struct {
+	Recipient    EmailSubscription
+	PullRequests []PullRequest
+	GeneratedAt  time.Time
+}
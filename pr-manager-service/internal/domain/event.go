@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Event types of the pull request lifecycle
+const (
+	EventReviewerAssigned  = "reviewer.assigned"
+	EventReviewerReplaced  = "reviewer.replaced"
+	EventPullRequestMerged = "pull_request.merged"
+)
+
+// EventTypes lists all known event types
+var EventTypes = []string{
+	EventReviewerAssigned,
+	EventReviewerReplaced,
+	EventPullRequestMerged,
+}
+
+// Event represents a domain event about a pull request
+type Event struct {
+	EventType       string
+	PullRequestId   string
+	PullRequestName string
+	AuthorId        string
+	ReviewerId      string
+	OldReviewerId   string
+	Reviewers       []string
+	OccurredAt      time.Time
+}
+
+func IsKnownEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
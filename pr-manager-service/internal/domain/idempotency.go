@@ -0,0 +1,16 @@
+package domain
+
+// IdempotencyRecord is a mutating request saved by its Idempotency-Key,
+// the response is nil while the first request is still running
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Response    *StoredResponse
+}
+
+// StoredResponse is replayed to repeated requests with the same key
+type StoredResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
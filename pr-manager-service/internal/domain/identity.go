@@ -0,0 +1,18 @@
+package domain
+
+// Git providers which can drive the service through webhooks
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Identity maps a git provider login to a service user
+type Identity struct {
+	Provider string
+	Login    string
+	UserId   string
+}
+
+func IsKnownProvider(provider string) bool {
+	return provider == ProviderGitHub || provider == ProviderGitLab
+}
@@ -0,0 +1,31 @@
+package domain
+
+// Chat providers for direct notifications
+const (
+	ChatProviderSlack      = "slack"
+	ChatProviderMattermost = "mattermost"
+)
+
+// Kinds of chat notifications
+const (
+	NotificationReviewerAssigned = "reviewer_assigned"
+	NotificationReviewDigest     = "review_digest"
+)
+
+// ChatHandle maps a service user to a chat account
+type ChatHandle struct {
+	UserId   string
+	Provider string
+	Handle   string
+}
+
+// Notification is a direct message to a single user
+type Notification struct {
+	Kind         string
+	Recipient    ChatHandle
+	PullRequests []PullRequest
+}
+
+func IsKnownChatProvider(provider string) bool {
+	return provider == ChatProviderSlack || provider == ChatProviderMattermost
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // PullRequest represents a domain pull request entity
 type PullRequest struct {
 	PullRequestId     string
@@ -7,4 +9,7 @@ type PullRequest struct {
 	AuthorId          string
 	StatusId          int
 	AssignedReviewers []string
+	CreatedAt         time.Time
+	// Version starts at 1 and is bumped on every change of the pull request
+	Version int64
 }
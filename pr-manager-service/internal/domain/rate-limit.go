@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit is a token bucket of Burst tokens refilled evenly over Period:
+// Burst requests are allowed at once and Burst per Period after that
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// TokenBucket is the state of one rate limited key
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// RateLimitDecision is the outcome of taking a token
+type RateLimitDecision struct {
+	Allowed bool
+	// whole tokens left after the request
+	Remaining int
+	// time until a token is available, zero if the request is allowed
+	RetryAfter time.Duration
+}
+
+// NewTokenBucket returns a full bucket, the state of a key not seen before
+func NewTokenBucket(limit RateLimit, now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(limit.Burst), UpdatedAt: now}
+}
+
+// TakeToken refills the bucket up to now and takes a token if there is one.
+// A denied request takes nothing, so retrying after RetryAfter succeeds.
+func (b TokenBucket) TakeToken(limit RateLimit, now time.Time) (TokenBucket, RateLimitDecision) {
+	perSecond := float64(limit.Burst) / limit.Period.Seconds()
+
+	// clocks of replicas may go back a little
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed.Seconds()*perSecond)
+		b.UpdatedAt = now
+	}
+
+	if b.Tokens < 1 {
+		wait := time.Duration((1 - b.Tokens) / perSecond * float64(time.Second))
+		return b, RateLimitDecision{RetryAfter: wait}
+	}
+
+	b.Tokens--
+	return b, RateLimitDecision{Allowed: true, Remaining: int(b.Tokens)}
+}
+
+// FullAt is the time the bucket refills completely, from then on it is
+// the same as a missing one and can be deleted
+func (b TokenBucket) FullAt(limit RateLimit) time.Time {
+	missing := float64(limit.Burst) - b.Tokens
+	if missing <= 0 {
+		return b.UpdatedAt
+	}
+	return b.UpdatedAt.Add(time.Duration(missing / float64(limit.Burst) * float64(limit.Period)))
+}
@@ -0,0 +1,7 @@
+package domain
+
+// TeamRoster is a team with all of its members, the unit of bulk import and export
+type TeamRoster struct {
+	TeamName string
+	Members  []User
+}
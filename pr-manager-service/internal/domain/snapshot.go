@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// SnapshotFormatVersion is bumped on incompatible changes of the snapshot layout
+const SnapshotFormatVersion = 2
+
+// MinSnapshotSchemaVersion is the oldest migration version whose snapshots can be
+// restored: all tables of a snapshot exist since the initial schema
+const MinSnapshotSchemaVersion = 1
+
+// SnapshotHeader describes where a snapshot comes from
+type SnapshotHeader struct {
+	FormatVersion int
+	SchemaVersion int64
+	CreatedAt     time.Time
+}
+
+// Membership links a user to a team
+type Membership struct {
+	TeamName string
+	UserId   string
+}
+
+// SnapshotPullRequest is a pull request with all stored columns
+type SnapshotPullRequest struct {
+	PullRequestId     string
+	PullRequestName   string
+	AuthorId          string
+	StatusId          int
+	NeedMoreReviewers bool
+	CreatedAt         time.Time
+	MergedAt          *time.Time
+	Version           int64
+}
+
+// ReviewerAssignment is a reviewer in one of the two slots of a pull request
+type ReviewerAssignment struct {
+	PullRequestId string
+	UserId        string
+	Slot          int
+	CreatedAt     time.Time
+	// FirstVerdictAt is nil until the reviewer approves or requests changes
+	FirstVerdictAt *time.Time
+}
+
+// Snapshot is the full state of teams, users and pull requests
+type Snapshot struct {
+	Header       SnapshotHeader
+	Teams        []Team
+	Users        []User
+	Memberships  []Membership
+	PullRequests []SnapshotPullRequest
+	Assignments  []ReviewerAssignment
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// WebhookSubscription represents an outgoing webhook subscription
+type WebhookSubscription struct {
+	Id         int64
+	Url        string
+	Secret     string
+	EventTypes []string
+	IsActive   bool
+}
+
+// WebhookDelivery represents a pending delivery of an outbox event to a subscription
+type WebhookDelivery struct {
+	Id           int64
+	Attempts     int
+	Subscription WebhookSubscription
+	EventId      int64
+	EventType    string
+	Payload      []byte
+	OccurredAt   time.Time
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Workload is a snapshot of the review load, read periodically for the
+// workload gauges
+type Workload struct {
+	// open pull requests per reviewer, active users without reviews are zero
+	OpenAssignmentsByUser map[string]int
+	// open review assignments of the members of each team
+	OpenAssignmentsByTeam map[string]int
+	// open pull requests with fewer than two reviewers
+	UnfilledPullRequests int
+	// time since creation of every open pull request
+	OpenPullRequestAges []time.Duration
+	// time from creation to merge of pull requests merged within the window
+	MergeDurations []time.Duration
+	// time from assignment to the first approval or change request of
+	// reviewers who gave it within the window
+	FirstVerdictDurations []time.Duration
+}
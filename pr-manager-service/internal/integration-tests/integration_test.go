@@ -1,13 +1,13 @@
 package integration
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"testing"
 	"time"
+
+	"pr-manager-service/client"
 )
 
 // PRM_BASE_URL - basic URL
@@ -18,203 +18,87 @@ func baseURL() string {
 	return "http://localhost:8080"
 }
 
-func newAdminRequest(t *testing.T, method, path string, body any) *http.Request {
-	t.Helper()
-
-	var buf bytes.Buffer
-	if body != nil {
-		if err := json.NewEncoder(&buf).Encode(body); err != nil {
-			t.Fatalf("failed to encode body: %v", err)
-		}
-	}
-
-	req, err := http.NewRequest(method, baseURL()+path, &buf)
-	if err != nil {
-		t.Fatalf("failed to create request: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer admin:integration-admin")
-
-	return req
-}
-
-type getTeamResponse struct {
-	Team struct {
-		TeamName string `json:"team_name"`
-		Members  []struct {
-			UserID   string `json:"user_id"`
-			UserName string `json:"username"`
-			IsActive bool   `json:"is_active"`
-		} `json:"members"`
-	} `json:"team"`
+func newAdminClient() *client.Client {
+	return client.New(baseURL(), client.WithAuth(client.AdminToken("integration-admin")))
 }
 
-type getUserReviewsResponse struct {
-	UserID       string `json:"user_id"`
-	PullRequests []struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		Status          string `json:"status"`
-	} `json:"pull_requests"`
+func newTestContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
 }
 
 // Create team and read it after creation
 func TestCreateTeamAndGetTeam_Integration(t *testing.T) {
-	client := &http.Client{Timeout: 5 * time.Second}
+	ctx := newTestContext(t)
+	admin := newAdminClient()
 
 	teamName := fmt.Sprintf("integration-team-%d", time.Now().UnixNano())
 
 	// 1) POST /team/add
-	createBody := map[string]any{
-		"team_name": teamName,
-		"members": []map[string]any{
-			{
-				"user_id":   "u_int_1",
-				"username":  "Integration User 1",
-				"is_active": true,
-			},
-			{
-				"user_id":   "u_int_2",
-				"username":  "Integration User 2",
-				"is_active": true,
-			},
+	_, err := admin.CreateTeam(ctx, client.Team{
+		TeamName: teamName,
+		Members: []client.TeamMember{
+			{UserId: "u_int_1", Username: "Integration User 1", IsActive: true},
+			{UserId: "u_int_2", Username: "Integration User 2", IsActive: true},
 		},
-	}
-
-	req := newAdminRequest(t, http.MethodPost, "/team/add", createBody)
-	resp, err := client.Do(req)
+	})
 	if err != nil {
-		t.Fatalf("failed to call /team/add: %v", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			t.Logf("failed to close resp body: %v", err)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusCreated {
-		t.Fatalf("expected status 201, got %d", resp.StatusCode)
+		t.Fatalf("failed to create team: %v", err)
 	}
 
 	// 2) GET /team/get?team_name=...
-	getReq := newAdminRequest(t, http.MethodGet, "/team/get?team_name="+teamName, nil)
-	resp2, err := client.Do(getReq)
+	team, err := admin.GetTeam(ctx, teamName)
 	if err != nil {
-		t.Fatalf("failed to call /team/get: %v", err)
-	}
-	defer func() {
-		if err := resp2.Body.Close(); err != nil {
-			t.Logf("failed to close resp body: %v", err)
-		}
-	}()
-
-	if resp2.StatusCode != http.StatusOK {
-		t.Fatalf("expected status 200, got %d", resp2.StatusCode)
+		t.Fatalf("failed to get team: %v", err)
 	}
 
-	var teamResp getTeamResponse
-	if err := json.NewDecoder(resp2.Body).Decode(&teamResp); err != nil {
-		t.Fatalf("failed to decode /team/get response: %v", err)
+	if team.TeamName != teamName {
+		t.Fatalf("expected team_name %q, got %q", teamName, team.TeamName)
 	}
-
-	if teamResp.Team.TeamName != teamName {
-		t.Fatalf("expected team_name %q, got %q", teamName, teamResp.Team.TeamName)
-	}
-	if len(teamResp.Team.Members) != 2 {
-		t.Fatalf("expected 2 members, got %d", len(teamResp.Team.Members))
+	if len(team.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(team.Members))
 	}
-
 }
 
 // Check creation of PR and getting this PR
 func TestCreatePullRequestAndGetUserReviews_Integration(t *testing.T) {
-	client := &http.Client{Timeout: 5 * time.Second}
+	ctx := newTestContext(t)
+	admin := newAdminClient()
 
 	teamName := fmt.Sprintf("integration-team-pr-%d", time.Now().UnixNano())
 
-	createTeamBody := map[string]any{
-		"team_name": teamName,
-		"members": []map[string]any{
-			{
-				"user_id":   "u_pr_author",
-				"username":  "Author",
-				"is_active": true,
-			},
-			{
-				"user_id":   "u_pr_reviewer1",
-				"username":  "Reviewer1",
-				"is_active": true,
-			},
-			{
-				"user_id":   "u_pr_reviewer2",
-				"username":  "Reviewer2",
-				"is_active": true,
-			},
+	_, err := admin.CreateTeam(ctx, client.Team{
+		TeamName: teamName,
+		Members: []client.TeamMember{
+			{UserId: "u_pr_author", Username: "Author", IsActive: true},
+			{UserId: "u_pr_reviewer1", Username: "Reviewer1", IsActive: true},
+			{UserId: "u_pr_reviewer2", Username: "Reviewer2", IsActive: true},
 		},
-	}
-
-	reqTeam := newAdminRequest(t, http.MethodPost, "/team/add", createTeamBody)
-	respTeam, err := client.Do(reqTeam)
+	})
 	if err != nil {
-		t.Fatalf("failed to call /team/add: %v", err)
-	}
-	if err := respTeam.Body.Close(); err != nil {
-		t.Logf("failed to close respTeam body: %v", err)
-	}
-	if respTeam.StatusCode != http.StatusCreated {
-		t.Fatalf("expected status 201 from /team/add, got %d", respTeam.StatusCode)
+		t.Fatalf("failed to create team: %v", err)
 	}
 
 	prID := fmt.Sprintf("pr-int-%d", time.Now().UnixNano())
-	createPRBody := map[string]any{
-		"pull_request_id":   prID,
-		"pull_request_name": "Integration PR",
-		"author_id":         "u_pr_author",
-	}
-
-	reqPR := newAdminRequest(t, http.MethodPost, "/pullRequest/create", createPRBody)
-	respPR, err := client.Do(reqPR)
+	_, err = admin.CreatePullRequest(ctx, client.CreatePullRequestRequest{
+		PullRequestId:   prID,
+		PullRequestName: "Integration PR",
+		AuthorId:        "u_pr_author",
+	})
 	if err != nil {
-		t.Fatalf("failed to call /pullRequest/create: %v", err)
-	}
-	defer func() {
-		if err := respPR.Body.Close(); err != nil {
-			t.Logf("failed to close resp body: %v", err)
-		}
-	}()
-
-	if respPR.StatusCode != http.StatusCreated {
-		t.Fatalf("expected status 201 from /pullRequest/create, got %d", respPR.StatusCode)
+		t.Fatalf("failed to create pull request: %v", err)
 	}
 
 	// Check if the endpoint works correctly
-	getReq, err := http.NewRequest(http.MethodGet, baseURL()+"/users/getReview?user_id=u_pr_reviewer1", nil)
-	if err != nil {
-		t.Fatalf("failed to create request for /users/getReview: %v", err)
-	}
-	getReq.Header.Set("Authorization", "Bearer user:u_pr_reviewer1")
-
-	respGet, err := client.Do(getReq)
+	reviewer := client.New(baseURL(), client.WithAuth(client.UserToken("u_pr_reviewer1")))
+	reviews, err := reviewer.GetUserReviews(ctx, "u_pr_reviewer1")
 	if err != nil {
-		t.Fatalf("failed to call /users/getReview: %v", err)
-	}
-	defer func() {
-		if err := respGet.Body.Close(); err != nil {
-			t.Logf("failed to close resp body: %v", err)
-		}
-	}()
-
-	if respGet.StatusCode != http.StatusOK {
-		t.Fatalf("expected status 200 from /users/getReview, got %d", respGet.StatusCode)
-	}
-
-	var reviews getUserReviewsResponse
-	if err := json.NewDecoder(respGet.Body).Decode(&reviews); err != nil {
-		t.Fatalf("failed to decode /users/getReview response: %v", err)
+		t.Fatalf("failed to get user reviews: %v", err)
 	}
 
-	if reviews.UserID != "u_pr_reviewer1" {
-		t.Fatalf("expected user_id u_pr_reviewer1, got %s", reviews.UserID)
+	if reviews.UserId != "u_pr_reviewer1" {
+		t.Fatalf("expected user_id u_pr_reviewer1, got %s", reviews.UserId)
 	}
 }
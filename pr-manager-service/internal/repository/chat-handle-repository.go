@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ChatHandleRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.ChatHandleRepositoryInterface = (*ChatHandleRepository)(nil)
+
+func NewChatHandleRepository(pool *pgxpool.Pool, opts ...Option) *ChatHandleRepository {
+	return &ChatHandleRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+func (r *ChatHandleRepository) SetChatHandle(ctx context.Context, handle domain.ChatHandle) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.setChatHandle(ctx, handle)
+	})
+}
+
+func (r *ChatHandleRepository) setChatHandle(ctx context.Context, handle domain.ChatHandle) error {
+	upsertSQL := `
+		INSERT INTO user_chat_handles (user_id, provider, handle)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			provider   = EXCLUDED.provider,
+			handle     = EXCLUDED.handle,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.pool.Exec(ctx, upsertSQL, handle.UserId, handle.Provider, handle.Handle)
+	return err
+}
+
+func (r *ChatHandleRepository) GetChatHandle(ctx context.Context, userId string) (*domain.ChatHandle, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.ChatHandle, error) {
+		return r.getChatHandle(ctx, userId)
+	})
+}
+
+func (r *ChatHandleRepository) getChatHandle(ctx context.Context, userId string) (*domain.ChatHandle, error) {
+	getSQL := `
+		SELECT user_id, provider, handle
+		FROM user_chat_handles
+		WHERE user_id = $1
+	`
+	var h domain.ChatHandle
+	err := r.pool.QueryRow(ctx, getSQL, userId).Scan(&h.UserId, &h.Provider, &h.Handle)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Returns handles of active users only
+func (r *ChatHandleRepository) ListChatHandles(ctx context.Context) ([]domain.ChatHandle, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.ChatHandle, error) {
+		return r.listChatHandles(ctx)
+	})
+}
+
+func (r *ChatHandleRepository) listChatHandles(ctx context.Context) ([]domain.ChatHandle, error) {
+	querySQL := `
+		SELECT h.user_id, h.provider, h.handle
+		FROM user_chat_handles h
+		JOIN users u ON u.user_id = h.user_id
+		WHERE u.is_active = true
+		ORDER BY h.user_id
+	`
+	rows, err := r.pool.Query(ctx, querySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.ChatHandle
+	for rows.Next() {
+		var h domain.ChatHandle
+		err = rows.Scan(&h.UserId, &h.Provider, &h.Handle)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/repository/repotest"
+	"pr-manager-service/migrations"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PRM_TEST_DATABASE_DSN points the contract suite at a Postgres database.
+// Every scenario gets its own schema with all migrations applied, the schema
+// is dropped afterwards, so existing tables are left untouched.
+const testDSNEnv = "PRM_TEST_DATABASE_DSN"
+
+var schemaSeq atomic.Int64
+
+func testDSN(t *testing.T) string {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+	return dsn
+}
+
+func TestPostgresRepositories_Contract(t *testing.T) {
+	dsn := testDSN(t)
+
+	repotest.RunContract(t, func(t *testing.T) repotest.Repositories {
+		pool := newSchemaPool(t, dsn)
+		migrator, err := NewMigrator(pool, migrations.FS)
+		if err != nil {
+			t.Fatalf("load migrations: %v", err)
+		}
+		if _, err = migrator.Up(context.Background()); err != nil {
+			t.Fatalf("apply migrations: %v", err)
+		}
+
+		return repotest.Repositories{
+			Teams:        NewTeamRepository(pool),
+			Users:        NewUserRepository(pool),
+			PullRequests: NewPullRequestRepository(pool),
+			Idempotency:  NewIdempotencyRepository(pool),
+			Workload:     NewWorkloadRepository(pool),
+			RateLimits:   NewRateLimitRepository(pool),
+			Webhooks:     NewWebhookRepository(pool),
+			Verdicts:     NewReviewVerdictRepository(pool),
+		}
+	})
+}
+
+// Creates an empty schema and returns a pool whose connections use it as search_path
+func newSchemaPool(t *testing.T, dsn string) *pgxpool.Pool {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	schema := fmt.Sprintf("prm_contract_%d_%d", os.Getpid(), schemaSeq.Add(1))
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err = admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close()
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	// registered after the schema cleanup, so it runs first
+	t.Cleanup(pool.Close)
+
+	return pool
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EmailSubscriptionRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.EmailSubscriptionRepositoryInterface = (*EmailSubscriptionRepository)(nil)
+
+func NewEmailSubscriptionRepository(pool *pgxpool.Pool, opts ...Option) *EmailSubscriptionRepository {
+	return &EmailSubscriptionRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+// Creates or updates the subscription, an existing unsubscribe token is kept
+func (r *EmailSubscriptionRepository) SetEmailSubscription(ctx context.Context, sub domain.EmailSubscription) (*domain.EmailSubscription, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.EmailSubscription, error) {
+		return r.setEmailSubscription(ctx, sub)
+	})
+}
+
+func (r *EmailSubscriptionRepository) setEmailSubscription(ctx context.Context, sub domain.EmailSubscription) (*domain.EmailSubscription, error) {
+	upsertSQL := `
+		INSERT INTO user_email_subscriptions (user_id, email, digest_enabled, unsubscribe_token)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			email          = EXCLUDED.email,
+			digest_enabled = EXCLUDED.digest_enabled,
+			updated_at     = CURRENT_TIMESTAMP
+		RETURNING user_id, email, digest_enabled, unsubscribe_token
+	`
+	var result domain.EmailSubscription
+	err := r.pool.QueryRow(ctx, upsertSQL, sub.UserId, sub.Email, sub.DigestEnabled, sub.UnsubscribeToken).
+		Scan(&result.UserId, &result.Email, &result.DigestEnabled, &result.UnsubscribeToken)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Returns subscriptions of active users with the digest enabled
+func (r *EmailSubscriptionRepository) ListDigestSubscriptions(ctx context.Context) ([]domain.EmailSubscription, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.EmailSubscription, error) {
+		return r.listDigestSubscriptions(ctx)
+	})
+}
+
+func (r *EmailSubscriptionRepository) listDigestSubscriptions(ctx context.Context) ([]domain.EmailSubscription, error) {
+	querySQL := `
+		SELECT s.user_id, s.email, s.digest_enabled, s.unsubscribe_token
+		FROM user_email_subscriptions s
+		JOIN users u ON u.user_id = s.user_id
+		WHERE u.is_active = true AND s.digest_enabled = true
+		ORDER BY s.user_id
+	`
+	rows, err := r.pool.Query(ctx, querySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.EmailSubscription
+	for rows.Next() {
+		var s domain.EmailSubscription
+		err = rows.Scan(&s.UserId, &s.Email, &s.DigestEnabled, &s.UnsubscribeToken)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Disables the digest for the subscription with the token and returns its user id
+func (r *EmailSubscriptionRepository) Unsubscribe(ctx context.Context, token string) (string, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (string, error) {
+		return r.unsubscribe(ctx, token)
+	})
+}
+
+func (r *EmailSubscriptionRepository) unsubscribe(ctx context.Context, token string) (string, error) {
+	updateSQL := `
+		UPDATE user_email_subscriptions
+		SET digest_enabled = false,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE unsubscribe_token = $1
+		RETURNING user_id
+	`
+	var userId string
+	err := r.pool.QueryRow(ctx, updateSQL, token).Scan(&userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", sql.ErrNoRows
+		}
+		return "", err
+	}
+	return userId, nil
+}
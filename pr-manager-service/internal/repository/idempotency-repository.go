@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.IdempotencyRepositoryInterface = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository(pool *pgxpool.Pool, opts ...Option) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+// Inserts the key or takes over an expired one, a pending key expires when
+// its lease runs out. When neither is possible the existing record is
+// returned, unless it expires in between.
+func (r *IdempotencyRepository) ReserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.IdempotencyRecord, error) {
+		return r.reserveKey(ctx, key, fingerprint, lease)
+	})
+}
+
+func (r *IdempotencyRepository) reserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	reserveSQL := `
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3))
+		ON CONFLICT (key)
+		DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			headers     = NULL,
+			body        = NULL,
+			created_at  = CURRENT_TIMESTAMP,
+			expires_at  = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+		RETURNING key
+	`
+	getSQL := `
+		SELECT fingerprint, status_code, headers, body
+		FROM idempotency_keys
+		WHERE key = $1 AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	for {
+		var reserved string
+		err := r.pool.QueryRow(ctx, reserveSQL, key, fingerprint, lease.Seconds()).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		rec := domain.IdempotencyRecord{Key: key}
+		var (
+			statusCode *int
+			headers    map[string]string
+			body       []byte
+		)
+		err = r.pool.QueryRow(ctx, getSQL, key).Scan(&rec.Fingerprint, &statusCode, &headers, &body)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// expired after the insert, try to take it over
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if statusCode != nil {
+			rec.Response = &domain.StoredResponse{
+				StatusCode: *statusCode,
+				Headers:    headers,
+				Body:       body,
+			}
+		}
+		return &rec, nil
+	}
+}
+
+// Saves the response and extends the lease of the key to the ttl
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.saveResponse(ctx, key, resp, ttl)
+	})
+}
+
+func (r *IdempotencyRepository) saveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error {
+	updateSQL := `
+		UPDATE idempotency_keys
+		SET status_code = $2, headers = $3, body = $4,
+		    expires_at = CURRENT_TIMESTAMP + make_interval(secs => $5)
+		WHERE key = $1
+	`
+	ct, err := r.pool.Exec(ctx, updateSQL, key, resp.StatusCode, resp.Headers, resp.Body, ttl.Seconds())
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) ReleaseKey(ctx context.Context, key string) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.releaseKey(ctx, key)
+	})
+}
+
+func (r *IdempotencyRepository) releaseKey(ctx context.Context, key string) error {
+	deleteSQL := `
+		DELETE FROM idempotency_keys
+		WHERE key = $1 AND status_code IS NULL
+	`
+	_, err := r.pool.Exec(ctx, deleteSQL, key)
+	return err
+}
+
+func (r *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (int64, error) {
+		return r.deleteExpiredKeys(ctx)
+	})
+}
+
+func (r *IdempotencyRepository) deleteExpiredKeys(ctx context.Context) (int64, error) {
+	deleteSQL := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= CURRENT_TIMESTAMP
+	`
+	ct, err := r.pool.Exec(ctx, deleteSQL)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
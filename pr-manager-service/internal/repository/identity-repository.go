@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdentityRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.IdentityRepositoryInterface = (*IdentityRepository)(nil)
+
+func NewIdentityRepository(pool *pgxpool.Pool, opts ...Option) *IdentityRepository {
+	return &IdentityRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+func (r *IdentityRepository) SetIdentity(ctx context.Context, identity domain.Identity) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.setIdentity(ctx, identity)
+	})
+}
+
+func (r *IdentityRepository) setIdentity(ctx context.Context, identity domain.Identity) error {
+	upsertSQL := `
+		INSERT INTO user_identities (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login)
+		DO UPDATE SET
+			user_id    = EXCLUDED.user_id,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.pool.Exec(ctx, upsertSQL, identity.Provider, identity.Login, identity.UserId)
+	return err
+}
+
+func (r *IdentityRepository) GetUserIdByLogin(ctx context.Context, provider, login string) (string, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (string, error) {
+		return r.getUserIdByLogin(ctx, provider, login)
+	})
+}
+
+func (r *IdentityRepository) getUserIdByLogin(ctx context.Context, provider, login string) (string, error) {
+	getSQL := `
+		SELECT user_id
+		FROM user_identities
+		WHERE provider = $1 AND login = $2
+	`
+	var userId string
+	err := r.pool.QueryRow(ctx, getSQL, provider, login).Scan(&userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", sql.ErrNoRows
+		}
+		return "", err
+	}
+	return userId, nil
+}
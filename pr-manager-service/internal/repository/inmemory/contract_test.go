@@ -0,0 +1,21 @@
+package inmemory
+
+import (
+	"testing"
+
+	"pr-manager-service/internal/repository/repotest"
+)
+
+func TestRepositories_Contract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) repotest.Repositories {
+		store := NewStore()
+		return repotest.Repositories{
+			Teams:        NewTeamRepository(store),
+			Users:        NewUserRepository(store),
+			PullRequests: NewPullRequestRepository(store),
+			Idempotency:  NewIdempotencyRepository(store),
+			Workload:     NewWorkloadRepository(store),
+			RateLimits:   NewRateLimitRepository(store),
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package inmemory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type IdempotencyRepository struct {
+	store *Store
+}
+
+var _ uc.IdempotencyRepositoryInterface = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository(store *Store) *IdempotencyRepository {
+	return &IdempotencyRepository{store: store}
+}
+
+func (r *IdempotencyRepository) ReserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if rec, ok := s.idempotency[key]; ok && rec.expiresAt.After(now) {
+		existing := &domain.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: rec.fingerprint,
+		}
+		if rec.response != nil {
+			resp := *rec.response
+			existing.Response = &resp
+		}
+		return existing, nil
+	}
+
+	s.idempotency[key] = &idempotencyRecord{
+		fingerprint: fingerprint,
+		expiresAt:   now.Add(lease),
+	}
+	return nil, nil
+}
+
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.idempotency[key]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	rec.response = &resp
+	rec.expiresAt = s.now().Add(ttl)
+	return nil
+}
+
+func (r *IdempotencyRepository) ReleaseKey(ctx context.Context, key string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.idempotency[key]; ok && rec.response == nil {
+		delete(s.idempotency, key)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var deleted int64
+	for key, rec := range s.idempotency {
+		if !rec.expiresAt.After(now) {
+			delete(s.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,185 @@
+package inmemory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type PullRequestRepository struct {
+	store *Store
+}
+
+var _ uc.PullRequestRepositoryInterface = (*PullRequestRepository)(nil)
+
+func NewPullRequestRepository(store *Store) *PullRequestRepository {
+	return &PullRequestRepository{store: store}
+}
+
+func (r *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.prs[pr.PullRequestId]; ok {
+		return uc.ErrPullRequestAlreadyExists
+	}
+	if _, ok := s.users[pr.AuthorId]; !ok {
+		return ErrUnknownUser
+	}
+	if len(pr.AssignedReviewers) > 2 {
+		return ErrTooManyReviewers
+	}
+	seen := make(map[string]struct{}, len(pr.AssignedReviewers))
+	for _, reviewerId := range pr.AssignedReviewers {
+		if _, ok := s.users[reviewerId]; !ok {
+			return ErrUnknownUser
+		}
+		if _, ok := seen[reviewerId]; ok {
+			return ErrReviewerDuplicate
+		}
+		seen[reviewerId] = struct{}{}
+	}
+
+	// new pull requests are always OPEN, whatever the status in pr
+	s.nextSeq++
+	s.prs[pr.PullRequestId] = &pullRequest{
+		id:        pr.PullRequestId,
+		name:      pr.PullRequestName,
+		authorId:  pr.AuthorId,
+		statusId:  statusOpen,
+		createdAt: s.now(),
+		reviewers: append([]string(nil), pr.AssignedReviewers...),
+		seq:       s.nextSeq,
+		version:   1,
+	}
+	pr.Version = 1
+
+	return nil
+}
+
+func (r *PullRequestRepository) GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pr, ok := s.prs[prId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return pr.toDomain(), nil
+}
+
+// MergePullRequest is idempotent, the merge time and version of the first call are kept
+func (r *PullRequestRepository) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (*domain.PullRequest, bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.prs[prId]
+	if !ok {
+		return nil, false, sql.ErrNoRows
+	}
+	if expectedVersion != 0 && pr.version != expectedVersion {
+		return nil, false, uc.ErrStaleVersion
+	}
+
+	merged := pr.statusId != statusMerged
+	if merged {
+		pr.statusId = statusMerged
+		pr.version++
+	}
+	if pr.mergedAt == nil {
+		now := s.now()
+		pr.mergedAt = &now
+	}
+
+	return pr.toDomain(), merged, nil
+}
+
+func (r *PullRequestRepository) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var assigned []*pullRequest
+	for _, pr := range s.prs {
+		for _, reviewerId := range pr.reviewers {
+			if reviewerId == userId {
+				assigned = append(assigned, pr)
+				break
+			}
+		}
+	}
+	sort.Slice(assigned, func(i, j int) bool {
+		if !assigned[i].createdAt.Equal(assigned[j].createdAt) {
+			return assigned[i].createdAt.Before(assigned[j].createdAt)
+		}
+		return assigned[i].seq < assigned[j].seq
+	})
+
+	var result []domain.PullRequest
+	for _, pr := range assigned {
+		// reviewers are not selected by the Postgres query either
+		result = append(result, domain.PullRequest{
+			PullRequestId:   pr.id,
+			PullRequestName: pr.name,
+			AuthorId:        pr.authorId,
+			StatusId:        pr.statusId,
+			CreatedAt:       pr.createdAt,
+		})
+	}
+	return result, nil
+}
+
+// ReplaceReviewer puts newUserId into the slot of oldUserId
+func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.prs[prId]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if expectedVersion != 0 && pr.version != expectedVersion {
+		return uc.ErrStaleVersion
+	}
+
+	slot := -1
+	for i, reviewerId := range pr.reviewers {
+		if reviewerId == oldUserId {
+			slot = i
+		}
+		if reviewerId == newUserId && newUserId != oldUserId {
+			return ErrReviewerDuplicate
+		}
+	}
+	if slot < 0 {
+		return sql.ErrNoRows
+	}
+	if _, ok := s.users[newUserId]; !ok {
+		return ErrUnknownUser
+	}
+
+	pr.reviewers[slot] = newUserId
+	pr.version++
+	return nil
+}
+
+func (r *PullRequestRepository) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var active []domain.User
+	for _, u := range s.membersOf(teamName) {
+		if u.IsActive {
+			active = append(active, u)
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,53 @@
+package inmemory
+
+import (
+	"context"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type RateLimitRepository struct {
+	store *Store
+}
+
+var _ uc.RateLimitRepositoryInterface = (*RateLimitRepository)(nil)
+
+func NewRateLimitRepository(store *Store) *RateLimitRepository {
+	return &RateLimitRepository{store: store}
+}
+
+func (r *RateLimitRepository) TakeToken(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	rec, ok := s.rateLimits[key]
+	if !ok {
+		rec.bucket = domain.NewTokenBucket(limit, now)
+	}
+
+	bucket, decision := rec.bucket.TakeToken(limit, now)
+	s.rateLimits[key] = rateLimitBucket{
+		bucket:    bucket,
+		expiresAt: bucket.FullAt(limit),
+	}
+	return decision, nil
+}
+
+func (r *RateLimitRepository) DeleteExpiredBuckets(ctx context.Context) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var deleted int64
+	for key, rec := range s.rateLimits {
+		if !rec.expiresAt.After(now) {
+			delete(s.rateLimits, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
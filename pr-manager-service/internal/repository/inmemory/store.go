@@ -0,0 +1,126 @@
+// Package inmemory implements the team, user and pull request repositories on
+// top of process memory. It follows the semantics of the Postgres repositories
+// and is meant for tests and the zero-dependency demo mode (DB_DRIVER=memory).
+// The rate limit buckets are also kept here with RATE_LIMIT_BACKEND=memory.
+package inmemory
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Errors of the constraints Postgres enforces with foreign keys and checks
+var (
+	ErrUnknownUser       = errors.New("user does not exist")
+	ErrTooManyReviewers  = errors.New("pull request can have at most two reviewers")
+	ErrReviewerDuplicate = errors.New("user is already a reviewer of this pull request")
+)
+
+// Status ids of pull requests, 1 - OPEN, 2 - MERGED
+const (
+	statusOpen   = 1
+	statusMerged = 2
+)
+
+type pullRequest struct {
+	id        string
+	name      string
+	authorId  string
+	statusId  int
+	createdAt time.Time
+	mergedAt  *time.Time
+	// reviewer per slot, index 0 is slot 1
+	reviewers []string
+	// insertion order, breaks ties of equal createdAt
+	seq int
+	// bumped on every change
+	version int64
+}
+
+type idempotencyRecord struct {
+	fingerprint string
+	// nil while the request is in progress
+	response  *domain.StoredResponse
+	expiresAt time.Time
+}
+
+type rateLimitBucket struct {
+	bucket domain.TokenBucket
+	// the bucket is full again from then on
+	expiresAt time.Time
+}
+
+// Store is the shared state of the repositories. It is safe for concurrent use,
+// every repository call is atomic.
+type Store struct {
+	mu sync.RWMutex
+
+	teams map[string]struct{}
+	users map[string]domain.User
+	// user id -> team names
+	memberships map[string]map[string]struct{}
+	prs         map[string]*pullRequest
+	nextSeq     int
+	idempotency map[string]*idempotencyRecord
+	rateLimits  map[string]rateLimitBucket
+
+	now func() time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		teams:       make(map[string]struct{}),
+		users:       make(map[string]domain.User),
+		memberships: make(map[string]map[string]struct{}),
+		prs:         make(map[string]*pullRequest),
+		idempotency: make(map[string]*idempotencyRecord),
+		rateLimits:  make(map[string]rateLimitBucket),
+		now:         time.Now,
+	}
+}
+
+// Team names of the user in alphabetical order, must be called with mu held
+func (s *Store) teamNamesOf(userId string) []string {
+	names := make([]string, 0, len(s.memberships[userId]))
+	for name := range s.memberships[userId] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Members of the team ordered by username, must be called with mu held
+func (s *Store) membersOf(teamName string) []domain.User {
+	var members []domain.User
+	for userId, teams := range s.memberships {
+		if _, ok := teams[teamName]; ok {
+			members = append(members, s.users[userId])
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].UserName != members[j].UserName {
+			return members[i].UserName < members[j].UserName
+		}
+		return members[i].UserId < members[j].UserId
+	})
+	return members
+}
+
+func (pr *pullRequest) toDomain() *domain.PullRequest {
+	var reviewers []string
+	if len(pr.reviewers) > 0 {
+		reviewers = append([]string(nil), pr.reviewers...)
+	}
+	return &domain.PullRequest{
+		PullRequestId:     pr.id,
+		PullRequestName:   pr.name,
+		AuthorId:          pr.authorId,
+		StatusId:          pr.statusId,
+		AssignedReviewers: reviewers,
+		Version:           pr.version,
+	}
+}
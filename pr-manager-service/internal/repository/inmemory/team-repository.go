@@ -0,0 +1,54 @@
+package inmemory
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type TeamRepository struct {
+	store *Store
+}
+
+var _ uc.TeamRepositoryInterface = (*TeamRepository)(nil)
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{store: store}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[teamName]; ok {
+		return uc.ErrTeamAlreadyExists
+	}
+	s.teams[teamName] = struct{}{}
+
+	// Create users and memberships for them
+	for _, u := range members {
+		s.users[u.UserId] = u
+
+		if s.memberships[u.UserId] == nil {
+			s.memberships[u.UserId] = make(map[string]struct{})
+		}
+		s.memberships[u.UserId][teamName] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.teams[teamName]; !ok {
+		return nil, nil, sql.ErrNoRows
+	}
+
+	return &domain.Team{TeamName: teamName}, s.membersOf(teamName), nil
+}
@@ -0,0 +1,64 @@
+package inmemory
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+var _ uc.UserRepositoryInterface = (*UserRepository)(nil)
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, userId string) (*domain.User, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[userId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userId]
+	if !ok {
+		return nil, "", sql.ErrNoRows
+	}
+	u.IsActive = isActive
+	s.users[userId] = u
+
+	// the first team by name, as in the Postgres repository
+	teamName := ""
+	if names := s.teamNamesOf(userId); len(names) > 0 {
+		teamName = names[0]
+	}
+
+	return &u, teamName, nil
+}
+
+func (r *UserRepository) GetTeamName(ctx context.Context, userId string) (string, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := s.teamNamesOf(userId)
+	if len(names) == 0 {
+		return "", sql.ErrNoRows
+	}
+	return names[0], nil
+}
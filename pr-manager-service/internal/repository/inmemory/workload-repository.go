@@ -0,0 +1,63 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type WorkloadRepository struct {
+	store *Store
+}
+
+var _ uc.WorkloadRepositoryInterface = (*WorkloadRepository)(nil)
+
+func NewWorkloadRepository(store *Store) *WorkloadRepository {
+	return &WorkloadRepository{store: store}
+}
+
+func (r *WorkloadRepository) GetWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	w := &domain.Workload{
+		OpenAssignmentsByUser: make(map[string]int),
+		OpenAssignmentsByTeam: make(map[string]int),
+	}
+
+	for userId, u := range s.users {
+		if u.IsActive {
+			w.OpenAssignmentsByUser[userId] = 0
+		}
+	}
+	for teamName := range s.teams {
+		w.OpenAssignmentsByTeam[teamName] = 0
+	}
+
+	mergedSince := now.Add(-mergedWindow)
+	for _, pr := range s.prs {
+		if pr.statusId == statusMerged {
+			if pr.mergedAt != nil && !pr.mergedAt.Before(mergedSince) {
+				w.MergeDurations = append(w.MergeDurations, pr.mergedAt.Sub(pr.createdAt))
+			}
+			continue
+		}
+
+		w.OpenPullRequestAges = append(w.OpenPullRequestAges, now.Sub(pr.createdAt))
+		if len(pr.reviewers) < 2 {
+			w.UnfilledPullRequests++
+		}
+		for _, reviewerId := range pr.reviewers {
+			// inactive users are kept while they still have open reviews
+			w.OpenAssignmentsByUser[reviewerId]++
+			for teamName := range s.memberships[reviewerId] {
+				w.OpenAssignmentsByTeam[teamName]++
+			}
+		}
+	}
+	return w, nil
+}
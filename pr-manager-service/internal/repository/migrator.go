@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrSchemaAhead is returned when the database has migrations this binary
+// doesn't know about, i.e. it was migrated by a newer version of the service
+var ErrSchemaAhead = errors.New("database schema is newer than the service")
+
+// migrationLockKey is the pg_advisory_lock key held while migrating,
+// so replicas starting together apply migrations one at a time
+const migrationLockKey int64 = 0x70726d6d6967 // "prmmig"
+
+// Migration is a pair of up and down scripts with the same version
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies embedded migrations. The version is kept in
+// schema_migrations in the golang-migrate format, so databases migrated
+// by the migrate tool are picked up as they are.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// LoadMigrations reads NNN_name.up.sql and NNN_name.down.sql files
+// from the root of fsys ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, name := range names {
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql", name)
+		}
+
+		prefix, rest, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: no version prefix", name)
+		}
+
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: strings.TrimSuffix(rest, "."+direction+".sql")}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(script)
+		} else {
+			m.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the applied version, 0 for an empty database
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return migratedVersion(ctx, m.pool)
+}
+
+// CheckVersion fails with ErrSchemaAhead when the database is migrated
+// past the newest embedded migration
+func (m *Migrator) CheckVersion(ctx context.Context) (int64, error) {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version > m.Latest() {
+		return version, fmt.Errorf("%w: database is at version %d, the service knows up to %d",
+			ErrSchemaAhead, version, m.Latest())
+	}
+	return version, nil
+}
+
+// Up applies pending migrations and returns the versions it applied
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	var applied []int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := migratedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version > m.Latest() {
+			return fmt.Errorf("%w: database is at version %d, the service knows up to %d",
+				ErrSchemaAhead, version, m.Latest())
+		}
+
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+			err = runMigration(ctx, conn, mig.Up, mig.Version)
+			if err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig.Version)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back up to steps migrations, newest first, and returns
+// the versions it rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	var rolledBack []int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := migratedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			mig := m.migrations[i]
+			if mig.Version > version {
+				continue
+			}
+			if mig.Version != version {
+				return fmt.Errorf("database version %d has no embedded migration", version)
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			err = runMigration(ctx, conn, mig.Down, previous)
+			if err != nil {
+				return fmt.Errorf("roll back migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			rolledBack = append(rolledBack, mig.Version)
+			version = previous
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Runs fn on a connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) (err error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// a fresh context, so the lock is released even after cancellation
+		_, unlockErr := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+		if unlockErr != nil && err == nil {
+			err = fmt.Errorf("release migration lock: %w", unlockErr)
+		}
+	}()
+
+	createSQL := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)
+	`
+	if _, err = conn.Exec(ctx, createSQL); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// Runs the script and records the new version in one transaction,
+// so a failed migration leaves neither changes nor a dirty version
+func runMigration(ctx context.Context, conn *pgxpool.Conn, script string, newVersion int64) (err error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if newVersion > 0 {
+		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, newVersion)
+	}
+	return err
+}
+
+// Returns the version in schema_migrations, 0 if the table is missing or empty
+func migratedVersion(ctx context.Context, q queryRower) (int64, error) {
+	var exists bool
+	existsSQL := `SELECT to_regclass('schema_migrations') IS NOT NULL`
+	if err := q.QueryRow(ctx, existsSQL).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	version, err := schemaVersion(ctx, q)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"pr-manager-service/migrations"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatalf("no migrations embedded")
+	}
+	for i, m := range loaded {
+		if i > 0 && m.Version <= loaded[i-1].Version {
+			t.Fatalf("expected ascending versions, got %d after %d", m.Version, loaded[i-1].Version)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Fatalf("migration %d_%s must have up and down scripts", m.Version, m.Name)
+		}
+	}
+	if loaded[0].Name != "init_schema" {
+		t.Fatalf("expected init_schema first, got %q", loaded[0].Name)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing up script",
+			fsys: fstest.MapFS{"001_init.down.sql": {Data: []byte("DROP TABLE t;")}},
+		},
+		{
+			name: "no version prefix",
+			fsys: fstest.MapFS{"init.up.sql": {Data: []byte("CREATE TABLE t ();")}},
+		},
+		{
+			name: "unknown direction",
+			fsys: fstest.MapFS{"001_init.sql": {Data: []byte("CREATE TABLE t ();")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadMigrations(tt.fsys); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestMigrator_Postgres(t *testing.T) {
+	dsn := testDSN(t)
+	ctx := context.Background()
+
+	pool := newSchemaPool(t, dsn)
+	migrator, err := NewMigrator(pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	latest := migrator.Latest()
+
+	applied, err := migrator.Up(ctx)
+	if err != nil || len(applied) != int(latest) {
+		t.Fatalf("expected %d applied migrations, got %v, %v", latest, applied, err)
+	}
+	applied, err = migrator.Up(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("expected nothing to apply, got %v, %v", applied, err)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 1)
+	if err != nil || len(rolledBack) != 1 || rolledBack[0] != latest {
+		t.Fatalf("expected to roll back %d, got %v, %v", latest, rolledBack, err)
+	}
+	if version, _ := migrator.Version(ctx); version != latest-1 {
+		t.Fatalf("expected version %d, got %d", latest-1, version)
+	}
+
+	// rolling back everything must leave an empty schema
+	if _, err = migrator.Down(ctx, int(latest)); err != nil {
+		t.Fatalf("roll back all: %v", err)
+	}
+	if version, _ := migrator.Version(ctx); version != 0 {
+		t.Fatalf("expected version 0, got %d", version)
+	}
+	var tables int
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'
+	`).Scan(&tables)
+	if err != nil || tables != 0 {
+		t.Fatalf("expected no tables left, got %d, %v", tables, err)
+	}
+
+	if _, err = migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+
+	_, err = pool.Exec(ctx, `UPDATE schema_migrations SET version = $1`, latest+1)
+	if err != nil {
+		t.Fatalf("bump version: %v", err)
+	}
+	if _, err = migrator.CheckVersion(ctx); !errors.Is(err, ErrSchemaAhead) {
+		t.Fatalf("expected ErrSchemaAhead, got %v", err)
+	}
+	if _, err = migrator.Up(ctx); !errors.Is(err, ErrSchemaAhead) {
+		t.Fatalf("Up: expected ErrSchemaAhead, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pr-manager-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// outboxPayloadJSON is stored in outbox_events.payload and sent as webhook data
+type outboxPayloadJSON struct {
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorId        string    `json:"author_id"`
+	ReviewerId      string    `json:"reviewer_id,omitempty"`
+	OldReviewerId   string    `json:"old_reviewer_id,omitempty"`
+	Reviewers       []string  `json:"assigned_reviewers"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// Writes the event to the outbox inside the caller's transaction,
+// so it is committed or rolled back together with the change itself
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, ev domain.Event) error {
+	reviewers := ev.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	payload, err := json.Marshal(outboxPayloadJSON{
+		PullRequestId:   ev.PullRequestId,
+		PullRequestName: ev.PullRequestName,
+		AuthorId:        ev.AuthorId,
+		ReviewerId:      ev.ReviewerId,
+		OldReviewerId:   ev.OldReviewerId,
+		Reviewers:       reviewers,
+		OccurredAt:      ev.OccurredAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	insertSQL := `
+		INSERT INTO outbox_events (event_type, payload)
+		VALUES ($1, $2)
+	`
+	_, err = tx.Exec(ctx, insertSQL, ev.EventType, payload)
+	return err
+}
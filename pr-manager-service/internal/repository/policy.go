@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Option configures how the repositories call the database
+type Option func(*callPolicy)
+
+// WithQueryTimeout bounds every attempt of a repository call
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(p *callPolicy) {
+		p.queryTimeout = timeout
+	}
+}
+
+// WithRetry retries calls failed by a transient error up to maxAttempts
+// times in total, waiting about backoff, 2*backoff, ... between attempts
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(p *callPolicy) {
+		p.maxAttempts = maxAttempts
+		p.backoff = backoff
+	}
+}
+
+// callPolicy applies the timeout and retries to one repository call.
+// A call is a whole transaction, so a retried transaction starts over.
+type callPolicy struct {
+	queryTimeout time.Duration
+	maxAttempts  int
+	backoff      time.Duration
+}
+
+func newCallPolicy(opts []Option) callPolicy {
+	p := callPolicy{maxAttempts: 1}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+func (p callPolicy) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := p.attempt(ctx, fn)
+		if err == nil || attempt >= p.maxAttempts || !isTransient(err) {
+			return err
+		}
+
+		// jitter keeps retrying replicas apart
+		delay := p.backoff << (attempt - 1)
+		if delay > 0 {
+			delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p callPolicy) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.queryTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.queryTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// callValue is run for calls returning a value
+func callValue[T any](ctx context.Context, p callPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var res T
+	err := p.run(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = fn(ctx)
+		return err
+	})
+	return res, err
+}
+
+// SQLSTATE codes of failures the server rolled back and which succeed
+// when the transaction is run again
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// isTransient reports errors a retry may fix: serialization failures and
+// deadlocks, and connection errors raised before the query was sent,
+// so a retry can't apply a change twice
+func isTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+	}
+	return pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCallPolicyRetriesTransientErrors(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		wantAttempts int
+	}{
+		"serialization failure": {&pgconn.PgError{Code: serializationFailure}, 3},
+		"deadlock":              {&pgconn.PgError{Code: deadlockDetected}, 3},
+		"unique violation":      {&pgconn.PgError{Code: "23505"}, 1},
+		"not found":             {errors.New("not found"), 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newCallPolicy([]Option{WithRetry(3, time.Millisecond)})
+			attempts := 0
+			err := p.run(context.Background(), func(ctx context.Context) error {
+				attempts++
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Fatalf("expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestCallPolicyStopsAfterSuccess(t *testing.T) {
+	p := newCallPolicy([]Option{WithRetry(5, time.Millisecond)})
+	attempts := 0
+	got, err := callValue(context.Background(), p, func(ctx context.Context) (int, error) {
+		attempts++
+		if attempts < 2 {
+			return 0, &pgconn.PgError{Code: serializationFailure}
+		}
+		return 42, nil
+	})
+	if err != nil || got != 42 {
+		t.Fatalf("expected 42, got %d, %v", got, err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestCallPolicyAppliesTimeoutPerAttempt(t *testing.T) {
+	p := newCallPolicy([]Option{WithQueryTimeout(time.Second)})
+	err := p.run(context.Background(), func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Fatalf("expected a deadline within a second, got %v, %v", deadline, ok)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCallPolicyStopsWhenContextDone(t *testing.T) {
+	p := newCallPolicy([]Option{WithRetry(5, time.Hour)})
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := p.run(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return &pgconn.PgError{Code: serializationFailure}
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected one failed attempt, got %d, %v", attempts, err)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"pr-manager-service/internal/domain"
 	uc "pr-manager-service/internal/usecase"
@@ -12,16 +13,23 @@ import (
 )
 
 type PullRequestRepository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	policy callPolicy
 }
 
 var _ uc.PullRequestRepositoryInterface = (*PullRequestRepository)(nil)
 
-func NewPullRequestRepository(pool *pgxpool.Pool) *PullRequestRepository {
-	return &PullRequestRepository{pool: pool}
+func NewPullRequestRepository(pool *pgxpool.Pool, opts ...Option) *PullRequestRepository {
+	return &PullRequestRepository{pool: pool, policy: newCallPolicy(opts)}
 }
 
 func (r *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.createPullRequest(ctx, pr)
+	})
+}
+
+func (r *PullRequestRepository) createPullRequest(ctx context.Context, pr *domain.PullRequest) (err error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -30,16 +38,20 @@ func (r *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *domai
 		if err != nil {
 			_ = tx.Rollback(ctx)
 		} else {
-			_ = tx.Commit(ctx)
+			err = tx.Commit(ctx)
 		}
 	}()
 
 	insertPrSQL := `
 		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
 		VALUES ($1, $2, $3)
+		RETURNING version
 	`
-	_, err = tx.Exec(ctx, insertPrSQL, pr.PullRequestId, pr.PullRequestName, pr.AuthorId)
+	err = tx.QueryRow(ctx, insertPrSQL, pr.PullRequestId, pr.PullRequestName, pr.AuthorId).Scan(&pr.Version)
 	if err != nil {
+		if isUniqueViolation(err, "pull_requests_pkey") {
+			err = uc.ErrPullRequestAlreadyExists
+		}
 		return err
 	}
 
@@ -56,18 +68,40 @@ func (r *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *domai
 		}
 	}
 
+	now := time.Now()
+	for _, reviewerId := range pr.AssignedReviewers {
+		err = insertOutboxEvent(ctx, tx, domain.Event{
+			EventType:       domain.EventReviewerAssigned,
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			ReviewerId:      reviewerId,
+			Reviewers:       pr.AssignedReviewers,
+			OccurredAt:      now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (r *PullRequestRepository) GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.PullRequest, error) {
+		return r.getPullRequest(ctx, prId)
+	})
+}
+
+func (r *PullRequestRepository) getPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
 	getPrSQL := `
-		SELECT pull_request_id, pull_request_name, author_id, status_id
+		SELECT pull_request_id, pull_request_name, author_id, status_id, version
 		FROM pull_requests
 		WHERE pull_request_id = $1
 	`
 	var pr domain.PullRequest
 	err := r.pool.QueryRow(ctx, getPrSQL, prId).
-		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId)
+		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &pr.Version)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -75,88 +109,113 @@ func (r *PullRequestRepository) GetPullRequest(ctx context.Context, prId string)
 		return nil, err
 	}
 
-	getReviewersSQL := `
-		SELECT user_id
-		FROM reviewer_assignments
-		WHERE pull_request_id = $1
-		ORDER BY slot
-	`
-	rows, err := r.pool.Query(ctx, getReviewersSQL, prId)
+	reviewers, err := getReviewers(ctx, r.pool, prId)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var reviewers []string
-	for rows.Next() {
-		var userId string
-		err = rows.Scan(&userId)
+	pr.AssignedReviewers = reviewers
+	return &pr, nil
+}
+
+func (r *PullRequestRepository) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (pr *domain.PullRequest, merged bool, err error) {
+	err = r.policy.run(ctx, func(ctx context.Context) error {
+		var err error
+		pr, merged, err = r.mergePullRequest(ctx, prId, expectedVersion)
+		return err
+	})
+	return pr, merged, err
+}
+
+func (r *PullRequestRepository) mergePullRequest(ctx context.Context, prId string, expectedVersion int64) (_ *domain.PullRequest, _ bool, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, err
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
 		}
-		reviewers = append(reviewers, userId)
+	}()
+
+	// Lock the PR to find out whether this call actually merges it
+	lockSQL := `
+		SELECT status_id, version
+		FROM pull_requests
+		WHERE pull_request_id = $1
+		FOR UPDATE
+	`
+	var (
+		prevStatusId int
+		version      int64
+	)
+	err = tx.QueryRow(ctx, lockSQL, prId).Scan(&prevStatusId, &version)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, false, sql.ErrNoRows
+		}
+		return nil, false, err
 	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+	if expectedVersion != 0 && version != expectedVersion {
+		err = uc.ErrStaleVersion
+		return nil, false, err
 	}
 
-	pr.AssignedReviewers = reviewers
-	return &pr, nil
-}
-
-func (r *PullRequestRepository) MergePullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	// Merging a merged PR changes nothing and keeps the version
 	updateSQL := `
 		UPDATE pull_requests
 		SET status_id = 2,
 		    mergedAt  = COALESCE(mergedAt, CURRENT_TIMESTAMP),
+		    version   = version + CASE WHEN status_id = 2 THEN 0 ELSE 1 END,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE pull_request_id = $1
-		RETURNING pull_request_id, pull_request_name, author_id, status_id
+		RETURNING pull_request_id, pull_request_name, author_id, status_id, version
 	`
 
 	var pr domain.PullRequest
-	err := r.pool.QueryRow(ctx, updateSQL, prId).
-		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId)
+	err = tx.QueryRow(ctx, updateSQL, prId).
+		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &pr.Version)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, sql.ErrNoRows
-		}
-		return nil, err
+		return nil, false, err
 	}
 
 	// Get reviewers
-	getReviewersSQL := `
-		SELECT user_id
-		FROM reviewer_assignments
-		WHERE pull_request_id = $1
-		ORDER BY slot
-	`
-	rows, err := r.pool.Query(ctx, getReviewersSQL, prId)
+	reviewers, err := getReviewers(ctx, tx, prId)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	defer rows.Close()
+	pr.AssignedReviewers = reviewers
 
-	var reviewers []string
-	for rows.Next() {
-		var userId string
-		err = rows.Scan(&userId)
+	// Merge is idempotent, the event is written only on the first merge
+	merged := prevStatusId != 2
+	if merged {
+		err = insertOutboxEvent(ctx, tx, domain.Event{
+			EventType:       domain.EventPullRequestMerged,
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			Reviewers:       pr.AssignedReviewers,
+			OccurredAt:      time.Now(),
+		})
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		reviewers = append(reviewers, userId)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
 	}
 
-	pr.AssignedReviewers = reviewers
-	return &pr, nil
+	return &pr, merged, nil
 }
 
 func (r *PullRequestRepository) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.PullRequest, error) {
+		return r.getAllPrByUserId(ctx, userId)
+	})
+}
+
+func (r *PullRequestRepository) getAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
 	querySQL := `
-		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status_id
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status_id, p.created_at
 		FROM pull_requests p
 		JOIN reviewer_assignments r ON p.pull_request_id = r.pull_request_id
 		WHERE r.user_id = $1
@@ -172,7 +231,7 @@ func (r *PullRequestRepository) GetAllPrByUserId(ctx context.Context, userId str
 
 	for rows.Next() {
 		var pr domain.PullRequest
-		err = rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId)
+		err = rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &pr.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -185,23 +244,85 @@ func (r *PullRequestRepository) GetAllPrByUserId(ctx context.Context, userId str
 	return result, nil
 }
 
-func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prId string, oldUserId string, newUserId string) error {
+func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prId string, oldUserId string, newUserId string, expectedVersion int64) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.replaceReviewer(ctx, prId, oldUserId, newUserId, expectedVersion)
+	})
+}
+
+func (r *PullRequestRepository) replaceReviewer(ctx context.Context, prId string, oldUserId string, newUserId string, expectedVersion int64) (err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	// Bump the version first, the row lock serializes concurrent changes
+	bumpSQL := `
+		UPDATE pull_requests
+		SET version = version + 1,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE pull_request_id = $1
+		  AND ($2::bigint = 0 OR version = $2)
+		RETURNING pull_request_name, author_id
+	`
+	var prName, authorId string
+	err = tx.QueryRow(ctx, bumpSQL, prId, expectedVersion).Scan(&prName, &authorId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = missingOrStale(ctx, tx, prId)
+		}
+		return err
+	}
+
+	// The new reviewer starts the assignment over, without a verdict
 	updateSQL := `
 		UPDATE reviewer_assignments
-		SET user_id = $3
+		SET user_id = $3,
+		    created_at = CURRENT_TIMESTAMP,
+		    first_verdict_at = NULL
 		WHERE pull_request_id = $1 AND user_id = $2
 	`
-	ct, err := r.pool.Exec(ctx, updateSQL, prId, oldUserId, newUserId)
+	ct, err := tx.Exec(ctx, updateSQL, prId, oldUserId, newUserId)
 	if err != nil {
 		return err
 	}
 	if ct.RowsAffected() == 0 {
-		return sql.ErrNoRows
+		err = sql.ErrNoRows
+		return err
 	}
-	return nil
+
+	reviewers, err := getReviewers(ctx, tx, prId)
+	if err != nil {
+		return err
+	}
+
+	err = insertOutboxEvent(ctx, tx, domain.Event{
+		EventType:       domain.EventReviewerReplaced,
+		PullRequestId:   prId,
+		PullRequestName: prName,
+		AuthorId:        authorId,
+		ReviewerId:      newUserId,
+		OldReviewerId:   oldUserId,
+		Reviewers:       reviewers,
+		OccurredAt:      time.Now(),
+	})
+	return err
 }
 
 func (r *PullRequestRepository) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.User, error) {
+		return r.getActiveTeamMembers(ctx, teamName)
+	})
+}
+
+func (r *PullRequestRepository) getActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
 	querySQL := `
 		SELECT u.user_id, u.username, u.is_active
 		FROM memberships m
@@ -229,3 +350,53 @@ func (r *PullRequestRepository) GetActiveTeamMembers(ctx context.Context, teamNa
 	}
 	return users, nil
 }
+
+// Tells why a versioned update matched no rows: sql.ErrNoRows if the PR
+// doesn't exist, ErrStaleVersion if it has another version
+func missingOrStale(ctx context.Context, tx pgx.Tx, prId string) error {
+	existsSQL := `
+		SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $1)
+	`
+	var exists bool
+	if err := tx.QueryRow(ctx, existsSQL, prId).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return uc.ErrStaleVersion
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Returns reviewers of the PR ordered by slot
+func getReviewers(ctx context.Context, q querier, prId string) ([]string, error) {
+	getReviewersSQL := `
+		SELECT user_id
+		FROM reviewer_assignments
+		WHERE pull_request_id = $1
+		ORDER BY slot
+	`
+	rows, err := q.Query(ctx, getReviewersSQL, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviewers []string
+	for rows.Next() {
+		var userId string
+		err = rows.Scan(&userId)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, userId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RateLimitRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.RateLimitRepositoryInterface = (*RateLimitRepository)(nil)
+
+func NewRateLimitRepository(pool *pgxpool.Pool, opts ...Option) *RateLimitRepository {
+	return &RateLimitRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+// Locks the bucket of the key, inserting a full one if there is none, and
+// writes it back after taking a token. Time is read from the database, so
+// replicas share one clock.
+func (r *RateLimitRepository) TakeToken(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (domain.RateLimitDecision, error) {
+		return r.takeToken(ctx, key, limit)
+	})
+}
+
+func (r *RateLimitRepository) takeToken(ctx context.Context, key string, limit domain.RateLimit) (_ domain.RateLimitDecision, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return domain.RateLimitDecision{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	// the no-op update locks an existing row and returns it unchanged
+	lockSQL := `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (key)
+		DO UPDATE SET key = EXCLUDED.key
+		RETURNING tokens, updated_at, CURRENT_TIMESTAMP
+	`
+	var (
+		bucket domain.TokenBucket
+		now    time.Time
+	)
+	err = tx.QueryRow(ctx, lockSQL, key, float64(limit.Burst)).Scan(&bucket.Tokens, &bucket.UpdatedAt, &now)
+	if err != nil {
+		return domain.RateLimitDecision{}, err
+	}
+
+	bucket, decision := bucket.TakeToken(limit, now)
+
+	updateSQL := `
+		UPDATE rate_limit_buckets
+		SET tokens = $2, updated_at = $3, expires_at = $4
+		WHERE key = $1
+	`
+	_, err = tx.Exec(ctx, updateSQL, key, bucket.Tokens, bucket.UpdatedAt, bucket.FullAt(limit))
+	if err != nil {
+		return domain.RateLimitDecision{}, err
+	}
+	return decision, nil
+}
+
+func (r *RateLimitRepository) DeleteExpiredBuckets(ctx context.Context) (int64, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (int64, error) {
+		return r.deleteExpiredBuckets(ctx)
+	})
+}
+
+func (r *RateLimitRepository) deleteExpiredBuckets(ctx context.Context) (int64, error) {
+	deleteSQL := `
+		DELETE FROM rate_limit_buckets
+		WHERE expires_at <= CURRENT_TIMESTAMP
+	`
+	ct, err := r.pool.Exec(ctx, deleteSQL)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,857 @@
+// Package repotest holds the behavior every implementation of the usecase
+// repository interfaces must share. Backends run it from their own tests:
+//
+//	repotest.RunContract(t, func(t *testing.T) repotest.Repositories { ... })
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+const (
+	statusOpen   = 1
+	statusMerged = 2
+)
+
+// Repositories is one backend under test
+type Repositories struct {
+	Teams        uc.TeamRepositoryInterface
+	Users        uc.UserRepositoryInterface
+	PullRequests uc.PullRequestRepositoryInterface
+	Idempotency  uc.IdempotencyRepositoryInterface
+	Workload     uc.WorkloadRepositoryInterface
+	// nil if the backend keeps no rate limits, their scenario is skipped
+	RateLimits uc.RateLimitRepositoryInterface
+	// nil if the backend has no outbox, its scenario is skipped
+	Webhooks uc.WebhookRepositoryInterface
+	// nil if the backend keeps no review verdicts, their scenario is skipped
+	Verdicts uc.ReviewVerdictRepositoryInterface
+}
+
+// Factory returns repositories over empty storage, it is called once per scenario
+type Factory func(t *testing.T) Repositories
+
+// RunContract runs every scenario as a subtest against repositories from newRepos
+func RunContract(t *testing.T, newRepos Factory) {
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, r Repositories)
+	}{
+		{"TeamUniqueness", testTeamUniqueness},
+		{"TeamMembersOrderedByUsername", testTeamMembersOrdered},
+		{"CreateTeamUpsertsUsers", testCreateTeamUpsertsUsers},
+		{"UserNotFound", testUserNotFound},
+		{"SetIsActive", testSetIsActive},
+		{"TeamNameIsFirstAlphabetically", testTeamName},
+		{"ActiveMemberFiltering", testActiveMembers},
+		{"PullRequestUniqueness", testPullRequestUniqueness},
+		{"PullRequestNotFound", testPullRequestNotFound},
+		{"MergeIdempotency", testMergeIdempotency},
+		{"ReplaceReviewer", testReplaceReviewer},
+		{"PullRequestsByReviewerOrderedByCreation", testPullRequestsByReviewer},
+		{"OptimisticConcurrency", testOptimisticConcurrency},
+		{"IdempotencyKeys", testIdempotencyKeys},
+		{"ExpiredIdempotencyKeys", testExpiredIdempotencyKeys},
+		{"Workload", testWorkload},
+		{"RateLimits", testRateLimits},
+		{"ProcessedOutboxEvents", testProcessedOutboxEvents},
+		{"UnprocessedOutboxEvents", testUnprocessedOutboxEvents},
+		{"FirstVerdicts", testFirstVerdicts},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			s.run(t, newRepos(t))
+		})
+	}
+}
+
+func mustCreateTeam(t *testing.T, r Repositories, teamName string, members ...domain.User) {
+	t.Helper()
+	if err := r.Teams.CreateTeam(context.Background(), teamName, members); err != nil {
+		t.Fatalf("CreateTeam(%q): %v", teamName, err)
+	}
+}
+
+func mustCreatePullRequest(t *testing.T, r Repositories, prId, authorId string, reviewers ...string) {
+	t.Helper()
+	pr := &domain.PullRequest{
+		PullRequestId:     prId,
+		PullRequestName:   "PR " + prId,
+		AuthorId:          authorId,
+		AssignedReviewers: reviewers,
+	}
+	if err := r.PullRequests.CreatePullRequest(context.Background(), pr); err != nil {
+		t.Fatalf("CreatePullRequest(%q): %v", prId, err)
+	}
+}
+
+func user(id, name string, active bool) domain.User {
+	return domain.User{UserId: id, UserName: name, IsActive: active}
+}
+
+func userIds(users []domain.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserId)
+	}
+	return ids
+}
+
+func testTeamUniqueness(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true))
+
+	err := r.Teams.CreateTeam(context.Background(), "backend", []domain.User{user("u2", "Bob", true)})
+	if !errors.Is(err, uc.ErrTeamAlreadyExists) {
+		t.Fatalf("expected ErrTeamAlreadyExists, got %v", err)
+	}
+
+	// the failed call must not leave its members behind
+	_, members, err := r.Teams.GetTeam(context.Background(), "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if got := userIds(members); !reflect.DeepEqual(got, []string{"u1"}) {
+		t.Fatalf("expected members [u1], got %v", got)
+	}
+	if _, err := r.Users.GetUser(context.Background(), "u2"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected u2 to be rolled back, got %v", err)
+	}
+}
+
+func testTeamMembersOrdered(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "backend",
+		user("u3", "Carol", true),
+		user("u1", "Alice", false),
+		user("u2", "Bob", true),
+	)
+
+	team, members, err := r.Teams.GetTeam(context.Background(), "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if team.TeamName != "backend" {
+		t.Fatalf("expected team backend, got %q", team.TeamName)
+	}
+	want := []domain.User{user("u1", "Alice", false), user("u2", "Bob", true), user("u3", "Carol", true)}
+	if !reflect.DeepEqual(members, want) {
+		t.Fatalf("expected %+v, got %+v", want, members)
+	}
+
+	mustCreateTeam(t, r, "empty")
+	_, members, err = r.Teams.GetTeam(context.Background(), "empty")
+	if err != nil || len(members) != 0 {
+		t.Fatalf("expected an empty team, got %+v, %v", members, err)
+	}
+
+	if _, _, err := r.Teams.GetTeam(context.Background(), "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func testCreateTeamUpsertsUsers(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true))
+	mustCreateTeam(t, r, "frontend", user("u1", "Alice Smith", false))
+
+	got, err := r.Users.GetUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if want := user("u1", "Alice Smith", false); *got != want {
+		t.Fatalf("expected %+v, got %+v", want, *got)
+	}
+
+	// the user stays a member of both teams
+	for _, teamName := range []string{"backend", "frontend"} {
+		_, members, err := r.Teams.GetTeam(context.Background(), teamName)
+		if err != nil {
+			t.Fatalf("GetTeam(%q): %v", teamName, err)
+		}
+		if ids := userIds(members); !reflect.DeepEqual(ids, []string{"u1"}) {
+			t.Fatalf("expected %s members [u1], got %v", teamName, ids)
+		}
+	}
+}
+
+func testUserNotFound(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	if _, err := r.Users.GetUser(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetUser: expected sql.ErrNoRows, got %v", err)
+	}
+	if _, _, err := r.Users.SetIsActive(ctx, "missing", true); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SetIsActive: expected sql.ErrNoRows, got %v", err)
+	}
+	if _, err := r.Users.GetTeamName(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetTeamName: expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func testSetIsActive(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true))
+
+	got, teamName, err := r.Users.SetIsActive(context.Background(), "u1", false)
+	if err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	if want := user("u1", "Alice", false); *got != want || teamName != "backend" {
+		t.Fatalf("expected %+v in backend, got %+v in %q", want, *got, teamName)
+	}
+
+	stored, err := r.Users.GetUser(context.Background(), "u1")
+	if err != nil || stored.IsActive {
+		t.Fatalf("expected u1 to be stored inactive, got %+v, %v", stored, err)
+	}
+}
+
+func testTeamName(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "platform", user("u1", "Alice", true))
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true))
+
+	teamName, err := r.Users.GetTeamName(context.Background(), "u1")
+	if err != nil || teamName != "backend" {
+		t.Fatalf("expected backend, got %q, %v", teamName, err)
+	}
+
+	_, teamName, err = r.Users.SetIsActive(context.Background(), "u1", true)
+	if err != nil || teamName != "backend" {
+		t.Fatalf("SetIsActive: expected backend, got %q, %v", teamName, err)
+	}
+}
+
+func testActiveMembers(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend",
+		user("u1", "Alice", true),
+		user("u2", "Bob", false),
+		user("u3", "Carol", true),
+	)
+	mustCreateTeam(t, r, "frontend", user("u4", "Dave", true))
+
+	// order is unspecified, callers pick candidates at random
+	activeIds := func() []string {
+		t.Helper()
+		members, err := r.PullRequests.GetActiveTeamMembers(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetActiveTeamMembers: %v", err)
+		}
+		for _, m := range members {
+			if !m.IsActive {
+				t.Fatalf("inactive member returned: %+v", m)
+			}
+		}
+		ids := userIds(members)
+		sort.Strings(ids)
+		return ids
+	}
+
+	if got := activeIds(); !reflect.DeepEqual(got, []string{"u1", "u3"}) {
+		t.Fatalf("expected [u1 u3], got %v", got)
+	}
+
+	if _, _, err := r.Users.SetIsActive(ctx, "u2", true); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	if _, _, err := r.Users.SetIsActive(ctx, "u3", false); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	if got := activeIds(); !reflect.DeepEqual(got, []string{"u1", "u2"}) {
+		t.Fatalf("expected [u1 u2] after toggling, got %v", got)
+	}
+
+	members, err := r.PullRequests.GetActiveTeamMembers(ctx, "missing")
+	if err != nil || len(members) != 0 {
+		t.Fatalf("expected no members of a missing team, got %+v, %v", members, err)
+	}
+}
+
+func testPullRequestUniqueness(t *testing.T, r Repositories) {
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true), user("u2", "Bob", true), user("u3", "Carol", true))
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2")
+
+	err := r.PullRequests.CreatePullRequest(context.Background(), &domain.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Another",
+		AuthorId:          "u3",
+		AssignedReviewers: []string{"u1"},
+	})
+	if !errors.Is(err, uc.ErrPullRequestAlreadyExists) {
+		t.Fatalf("expected ErrPullRequestAlreadyExists, got %v", err)
+	}
+
+	pr, err := r.PullRequests.GetPullRequest(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if pr.PullRequestName != "PR pr-1" || pr.AuthorId != "u1" || !reflect.DeepEqual(pr.AssignedReviewers, []string{"u2"}) {
+		t.Fatalf("the original pull request must be kept, got %+v", pr)
+	}
+}
+
+func testPullRequestNotFound(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	if _, err := r.PullRequests.GetPullRequest(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetPullRequest: expected sql.ErrNoRows, got %v", err)
+	}
+	if _, _, err := r.PullRequests.MergePullRequest(ctx, "missing", 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("MergePullRequest: expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func testMergeIdempotency(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true), user("u2", "Bob", true), user("u3", "Carol", true))
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2", "u3")
+
+	created, err := r.PullRequests.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if created.StatusId != statusOpen {
+		t.Fatalf("expected a new pull request to be open, got status %d", created.StatusId)
+	}
+
+	for i := 1; i <= 2; i++ {
+		merged, changed, err := r.PullRequests.MergePullRequest(ctx, "pr-1", 0)
+		if err != nil {
+			t.Fatalf("merge #%d: %v", i, err)
+		}
+		// only the first call changes the status
+		if changed != (i == 1) {
+			t.Fatalf("merge #%d: expected merged %v, got %v", i, i == 1, changed)
+		}
+		if merged.PullRequestId != "pr-1" || merged.AuthorId != "u1" || merged.StatusId != statusMerged {
+			t.Fatalf("merge #%d: got %+v", i, merged)
+		}
+		if !reflect.DeepEqual(merged.AssignedReviewers, []string{"u2", "u3"}) {
+			t.Fatalf("merge #%d: expected reviewers [u2 u3], got %v", i, merged.AssignedReviewers)
+		}
+	}
+
+	stored, err := r.PullRequests.GetPullRequest(ctx, "pr-1")
+	if err != nil || stored.StatusId != statusMerged {
+		t.Fatalf("expected the stored pull request to be merged, got %+v, %v", stored, err)
+	}
+}
+
+func testReplaceReviewer(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend",
+		user("u1", "Alice", true),
+		user("u2", "Bob", true),
+		user("u3", "Carol", true),
+		user("u4", "Dave", true),
+	)
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2", "u3")
+
+	// the new reviewer takes the slot of the old one
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u2", "u4", 0); err != nil {
+		t.Fatalf("ReplaceReviewer: %v", err)
+	}
+	pr, err := r.PullRequests.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if !reflect.DeepEqual(pr.AssignedReviewers, []string{"u4", "u3"}) {
+		t.Fatalf("expected reviewers [u4 u3], got %v", pr.AssignedReviewers)
+	}
+
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u2", "u1", 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("replacing a non-reviewer: expected sql.ErrNoRows, got %v", err)
+	}
+	if err := r.PullRequests.ReplaceReviewer(ctx, "missing", "u2", "u1", 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("replacing on a missing pull request: expected sql.ErrNoRows, got %v", err)
+	}
+
+	// a reviewer can't hold both slots
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u4", "u3", 0); err == nil {
+		t.Fatalf("expected an error when the new reviewer is already assigned")
+	}
+	pr, err = r.PullRequests.GetPullRequest(ctx, "pr-1")
+	if err != nil || !reflect.DeepEqual(pr.AssignedReviewers, []string{"u4", "u3"}) {
+		t.Fatalf("a failed replacement must not change reviewers, got %+v, %v", pr, err)
+	}
+
+	reviews, err := r.PullRequests.GetAllPrByUserId(ctx, "u2")
+	if err != nil || len(reviews) != 0 {
+		t.Fatalf("expected no reviews for the replaced reviewer, got %+v, %v", reviews, err)
+	}
+}
+
+func testPullRequestsByReviewer(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true), user("u2", "Bob", true), user("u3", "Carol", true))
+	mustCreatePullRequest(t, r, "pr-b", "u1", "u2")
+	mustCreatePullRequest(t, r, "pr-c", "u3", "u2")
+	mustCreatePullRequest(t, r, "pr-a", "u1", "u2", "u3")
+	mustCreatePullRequest(t, r, "pr-d", "u1", "u3")
+
+	if _, _, err := r.PullRequests.MergePullRequest(ctx, "pr-c", 0); err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+
+	reviews, err := r.PullRequests.GetAllPrByUserId(ctx, "u2")
+	if err != nil {
+		t.Fatalf("GetAllPrByUserId: %v", err)
+	}
+
+	var ids []string
+	for _, pr := range reviews {
+		ids = append(ids, pr.PullRequestId)
+	}
+	if !reflect.DeepEqual(ids, []string{"pr-b", "pr-c", "pr-a"}) {
+		t.Fatalf("expected [pr-b pr-c pr-a] in creation order, got %v", ids)
+	}
+	if reviews[1].StatusId != statusMerged || reviews[0].StatusId != statusOpen {
+		t.Fatalf("unexpected statuses: %+v", reviews)
+	}
+	for _, pr := range reviews {
+		if pr.CreatedAt.IsZero() {
+			t.Fatalf("expected CreatedAt to be set, got %+v", pr)
+		}
+	}
+
+	reviews, err = r.PullRequests.GetAllPrByUserId(ctx, "u1")
+	if err != nil || len(reviews) != 0 {
+		t.Fatalf("authors aren't reviewers, got %+v, %v", reviews, err)
+	}
+}
+
+func testOptimisticConcurrency(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend",
+		user("u1", "Alice", true),
+		user("u2", "Bob", true),
+		user("u3", "Carol", true),
+		user("u4", "Dave", true),
+	)
+
+	pr := &domain.PullRequest{
+		PullRequestId:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorId:          "u1",
+		AssignedReviewers: []string{"u2"},
+	}
+	if err := r.PullRequests.CreatePullRequest(ctx, pr); err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if pr.Version != 1 {
+		t.Fatalf("expected a created pull request to get version 1, got %d", pr.Version)
+	}
+
+	version := func() int64 {
+		t.Helper()
+		stored, err := r.PullRequests.GetPullRequest(ctx, "pr-1")
+		if err != nil {
+			t.Fatalf("GetPullRequest: %v", err)
+		}
+		return stored.Version
+	}
+
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u2", "u3", 1); err != nil {
+		t.Fatalf("ReplaceReviewer with the current version: %v", err)
+	}
+	if got := version(); got != 2 {
+		t.Fatalf("expected version 2 after a replacement, got %d", got)
+	}
+
+	// a stale version changes nothing
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u3", "u4", 1); !errors.Is(err, uc.ErrStaleVersion) {
+		t.Fatalf("ReplaceReviewer: expected ErrStaleVersion, got %v", err)
+	}
+	if _, _, err := r.PullRequests.MergePullRequest(ctx, "pr-1", 1); !errors.Is(err, uc.ErrStaleVersion) {
+		t.Fatalf("MergePullRequest: expected ErrStaleVersion, got %v", err)
+	}
+	stored, err := r.PullRequests.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if stored.Version != 2 || stored.StatusId != statusOpen || !reflect.DeepEqual(stored.AssignedReviewers, []string{"u3"}) {
+		t.Fatalf("stale updates must not change the pull request, got %+v", stored)
+	}
+
+	// a failed replacement rolls the version back too
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u2", "u4", 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("replacing a non-reviewer: expected sql.ErrNoRows, got %v", err)
+	}
+	if got := version(); got != 2 {
+		t.Fatalf("expected version 2 after a failed replacement, got %d", got)
+	}
+
+	merged, _, err := r.PullRequests.MergePullRequest(ctx, "pr-1", 2)
+	if err != nil {
+		t.Fatalf("MergePullRequest with the current version: %v", err)
+	}
+	if merged.Version != 3 {
+		t.Fatalf("expected version 3 after merge, got %d", merged.Version)
+	}
+
+	// merging again changes nothing, so the version stays
+	for _, expected := range []int64{0, 3} {
+		merged, _, err = r.PullRequests.MergePullRequest(ctx, "pr-1", expected)
+		if err != nil || merged.Version != 3 {
+			t.Fatalf("repeated merge with version %d: got %+v, %v", expected, merged, err)
+		}
+	}
+
+	if _, _, err := r.PullRequests.MergePullRequest(ctx, "missing", 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("MergePullRequest on a missing pull request: expected sql.ErrNoRows, got %v", err)
+	}
+	if err := r.PullRequests.ReplaceReviewer(ctx, "missing", "u2", "u3", 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("ReplaceReviewer on a missing pull request: expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func testIdempotencyKeys(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	existing, err := r.Idempotency.ReserveKey(ctx, "k1", "fp-1", time.Hour)
+	if err != nil || existing != nil {
+		t.Fatalf("first ReserveKey: expected a reservation, got %+v, %v", existing, err)
+	}
+
+	existing, err = r.Idempotency.ReserveKey(ctx, "k1", "fp-2", time.Hour)
+	if err != nil {
+		t.Fatalf("ReserveKey in progress: %v", err)
+	}
+	if existing == nil || existing.Fingerprint != "fp-1" || existing.Response != nil {
+		t.Fatalf("expected the in-progress record of fp-1, got %+v", existing)
+	}
+
+	resp := domain.StoredResponse{
+		StatusCode: 201,
+		Headers:    map[string]string{"Content-Type": "application/json", "ETag": `"1"`},
+		Body:       []byte(`{"pr":{}}`),
+	}
+	if err = r.Idempotency.SaveResponse(ctx, "k1", resp, time.Hour); err != nil {
+		t.Fatalf("SaveResponse: %v", err)
+	}
+	// a saved response is not released
+	if err = r.Idempotency.ReleaseKey(ctx, "k1"); err != nil {
+		t.Fatalf("ReleaseKey: %v", err)
+	}
+
+	existing, err = r.Idempotency.ReserveKey(ctx, "k1", "fp-1", time.Hour)
+	if err != nil {
+		t.Fatalf("ReserveKey after completion: %v", err)
+	}
+	if existing == nil || existing.Response == nil || !reflect.DeepEqual(*existing.Response, resp) {
+		t.Fatalf("expected the saved response, got %+v", existing)
+	}
+
+	if err = r.Idempotency.SaveResponse(ctx, "missing", resp, time.Hour); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("SaveResponse of a missing key: expected sql.ErrNoRows, got %v", err)
+	}
+
+	// a released key can be reserved again
+	if _, err = r.Idempotency.ReserveKey(ctx, "k2", "fp-1", time.Hour); err != nil {
+		t.Fatalf("ReserveKey k2: %v", err)
+	}
+	if err = r.Idempotency.ReleaseKey(ctx, "k2"); err != nil {
+		t.Fatalf("ReleaseKey k2: %v", err)
+	}
+	existing, err = r.Idempotency.ReserveKey(ctx, "k2", "fp-2", time.Hour)
+	if err != nil || existing != nil {
+		t.Fatalf("ReserveKey after release: expected a reservation, got %+v, %v", existing, err)
+	}
+}
+
+func testExpiredIdempotencyKeys(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	// a negative lease reserves keys which are expired right away
+	for _, key := range []string{"expired-1", "expired-2"} {
+		if _, err := r.Idempotency.ReserveKey(ctx, key, "fp-1", -time.Minute); err != nil {
+			t.Fatalf("ReserveKey(%q): %v", key, err)
+		}
+	}
+	if _, err := r.Idempotency.ReserveKey(ctx, "live", "fp-1", time.Hour); err != nil {
+		t.Fatalf("ReserveKey(live): %v", err)
+	}
+
+	// an expired key is taken over by the next request
+	existing, err := r.Idempotency.ReserveKey(ctx, "expired-1", "fp-2", time.Hour)
+	if err != nil || existing != nil {
+		t.Fatalf("ReserveKey of an expired key: expected a reservation, got %+v, %v", existing, err)
+	}
+	existing, err = r.Idempotency.ReserveKey(ctx, "expired-1", "fp-3", time.Hour)
+	if err != nil || existing == nil || existing.Fingerprint != "fp-2" {
+		t.Fatalf("expected the record of the new request, got %+v, %v", existing, err)
+	}
+
+	deleted, err := r.Idempotency.DeleteExpiredKeys(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpiredKeys: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 expired key deleted, got %d", deleted)
+	}
+
+	existing, err = r.Idempotency.ReserveKey(ctx, "live", "fp-2", time.Hour)
+	if err != nil || existing == nil || existing.Fingerprint != "fp-1" {
+		t.Fatalf("live key must survive the cleanup, got %+v, %v", existing, err)
+	}
+
+	// the response outlives the lease it was reserved with
+	if _, err = r.Idempotency.ReserveKey(ctx, "saved", "fp-1", -time.Minute); err != nil {
+		t.Fatalf("ReserveKey(saved): %v", err)
+	}
+	resp := domain.StoredResponse{StatusCode: 200, Body: []byte(`{}`)}
+	if err = r.Idempotency.SaveResponse(ctx, "saved", resp, time.Hour); err != nil {
+		t.Fatalf("SaveResponse(saved): %v", err)
+	}
+	existing, err = r.Idempotency.ReserveKey(ctx, "saved", "fp-1", time.Hour)
+	if err != nil || existing == nil || existing.Response == nil || existing.Response.StatusCode != 200 {
+		t.Fatalf("expected the saved response after the lease, got %+v, %v", existing, err)
+	}
+}
+
+func testWorkload(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend",
+		user("u1", "Alice", true),
+		user("u2", "Bob", true),
+		user("u3", "Carol", true),
+		user("u5", "Eve", false),
+	)
+	mustCreateTeam(t, r, "frontend", user("u4", "Dave", true))
+
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2", "u3")
+	mustCreatePullRequest(t, r, "pr-2", "u1", "u2")
+	mustCreatePullRequest(t, r, "pr-3", "u2", "u3", "u4")
+	mustCreatePullRequest(t, r, "pr-4", "u4")
+	if _, _, err := r.PullRequests.MergePullRequest(ctx, "pr-3", 0); err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	// a deactivated reviewer keeps counting while the review is open
+	if _, _, err := r.Users.SetIsActive(ctx, "u3", false); err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+
+	w, err := r.Workload.GetWorkload(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("GetWorkload: %v", err)
+	}
+
+	wantByUser := map[string]int{"u1": 0, "u2": 2, "u3": 1, "u4": 0}
+	if !reflect.DeepEqual(w.OpenAssignmentsByUser, wantByUser) {
+		t.Fatalf("expected assignments by user %v, got %v", wantByUser, w.OpenAssignmentsByUser)
+	}
+	wantByTeam := map[string]int{"backend": 3, "frontend": 0}
+	if !reflect.DeepEqual(w.OpenAssignmentsByTeam, wantByTeam) {
+		t.Fatalf("expected assignments by team %v, got %v", wantByTeam, w.OpenAssignmentsByTeam)
+	}
+	if w.UnfilledPullRequests != 2 {
+		t.Fatalf("expected 2 pull requests with unfilled slots, got %d", w.UnfilledPullRequests)
+	}
+	if len(w.OpenPullRequestAges) != 3 {
+		t.Fatalf("expected ages of 3 open pull requests, got %v", w.OpenPullRequestAges)
+	}
+	for _, age := range w.OpenPullRequestAges {
+		if age < 0 || age > time.Hour {
+			t.Fatalf("expected ages of new pull requests, got %v", w.OpenPullRequestAges)
+		}
+	}
+	if len(w.MergeDurations) != 1 || w.MergeDurations[0] < 0 || w.MergeDurations[0] > time.Hour {
+		t.Fatalf("expected the merge duration of pr-3, got %v", w.MergeDurations)
+	}
+
+	// a negative window starts in the future and covers no merges
+	w, err = r.Workload.GetWorkload(ctx, -time.Hour)
+	if err != nil {
+		t.Fatalf("GetWorkload: %v", err)
+	}
+	if len(w.MergeDurations) != 0 {
+		t.Fatalf("expected no merges within a negative window, got %v", w.MergeDurations)
+	}
+}
+
+func testRateLimits(t *testing.T, r Repositories) {
+	if r.RateLimits == nil {
+		t.Skip("backend keeps no rate limits")
+	}
+	ctx := context.Background()
+	limit := domain.RateLimit{Burst: 2, Period: time.Hour}
+
+	for want := 1; want >= 0; want-- {
+		decision, err := r.RateLimits.TakeToken(ctx, "k1", limit)
+		if err != nil || !decision.Allowed || decision.Remaining != want {
+			t.Fatalf("TakeToken: expected allowed with %d left, got %+v, %v", want, decision, err)
+		}
+	}
+	decision, err := r.RateLimits.TakeToken(ctx, "k1", limit)
+	if err != nil || decision.Allowed {
+		t.Fatalf("TakeToken of an empty bucket: expected denied, got %+v, %v", decision, err)
+	}
+	// a token comes back every 30 minutes
+	if decision.RetryAfter <= 29*time.Minute || decision.RetryAfter > 30*time.Minute {
+		t.Fatalf("expected RetryAfter of about 30m, got %s", decision.RetryAfter)
+	}
+
+	decision, err = r.RateLimits.TakeToken(ctx, "k2", limit)
+	if err != nil || !decision.Allowed {
+		t.Fatalf("TakeToken of another key: expected allowed, got %+v, %v", decision, err)
+	}
+
+	// a bucket refilled completely is deleted, the others are kept
+	short := domain.RateLimit{Burst: 1, Period: time.Millisecond}
+	if _, err = r.RateLimits.TakeToken(ctx, "short", short); err != nil {
+		t.Fatalf("TakeToken(short): %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	deleted, err := r.RateLimits.DeleteExpiredBuckets(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpiredBuckets: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 expired bucket deleted, got %d", deleted)
+	}
+
+	decision, err = r.RateLimits.TakeToken(ctx, "k1", limit)
+	if err != nil || decision.Allowed {
+		t.Fatalf("empty bucket must survive the cleanup, got %+v, %v", decision, err)
+	}
+}
+
+func testProcessedOutboxEvents(t *testing.T, r Repositories) {
+	if r.Webhooks == nil {
+		t.Skip("backend has no outbox")
+	}
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true), user("u2", "Bob", true))
+	if err := r.Webhooks.CreateSubscription(ctx, &domain.WebhookSubscription{
+		Url:      "https://hooks.example.com/prm",
+		Secret:   "s3cret",
+		IsActive: true,
+	}); err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+
+	// every created pull request writes one reviewer.assigned event
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2")
+	mustCreatePullRequest(t, r, "pr-2", "u1", "u2")
+	if n, err := r.Webhooks.FanOutEvents(ctx, 10); err != nil || n != 2 {
+		t.Fatalf("FanOutEvents: expected 2 events, got %d, %v", n, err)
+	}
+
+	deleted, err := r.Webhooks.DeleteProcessedEvents(ctx, -time.Second)
+	if err != nil || deleted != 0 {
+		t.Fatalf("DeleteProcessedEvents with pending deliveries: expected 0, got %d, %v", deleted, err)
+	}
+
+	deliveries, err := r.Webhooks.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("ClaimDueDeliveries: expected 2 deliveries, got %d, %v", len(deliveries), err)
+	}
+	if err = r.Webhooks.CompleteDelivery(ctx, deliveries[0].Id); err != nil {
+		t.Fatalf("CompleteDelivery: %v", err)
+	}
+	if err = r.Webhooks.DeadLetterDelivery(ctx, deliveries[1].Id, 1, "status 500"); err != nil {
+		t.Fatalf("DeadLetterDelivery: %v", err)
+	}
+	// not fanned out yet
+	mustCreatePullRequest(t, r, "pr-3", "u1", "u2")
+
+	deleted, err = r.Webhooks.DeleteProcessedEvents(ctx, time.Hour)
+	if err != nil || deleted != 0 {
+		t.Fatalf("DeleteProcessedEvents within retention: expected 0, got %d, %v", deleted, err)
+	}
+
+	// a negative retention covers the event delivered just now,
+	// the dead lettered and the unprocessed events are kept
+	deleted, err = r.Webhooks.DeleteProcessedEvents(ctx, -time.Second)
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteProcessedEvents: expected 1, got %d, %v", deleted, err)
+	}
+	if n, err := r.Webhooks.FanOutEvents(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("FanOutEvents after cleanup: expected 1 event, got %d, %v", n, err)
+	}
+}
+
+func testUnprocessedOutboxEvents(t *testing.T, r Repositories) {
+	if r.Webhooks == nil {
+		t.Skip("backend has no outbox")
+	}
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend", user("u1", "Alice", true), user("u2", "Bob", true))
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2")
+
+	deleted, err := r.Webhooks.DeleteUnprocessedEvents(ctx, time.Hour)
+	if err != nil || deleted != 0 {
+		t.Fatalf("DeleteUnprocessedEvents within retention: expected 0, got %d, %v", deleted, err)
+	}
+	if n, err := r.Webhooks.FanOutEvents(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("FanOutEvents: expected 1 event, got %d, %v", n, err)
+	}
+
+	// the fanned out event is left to DeleteProcessedEvents
+	mustCreatePullRequest(t, r, "pr-2", "u1", "u2")
+	deleted, err = r.Webhooks.DeleteUnprocessedEvents(ctx, -time.Second)
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteUnprocessedEvents: expected 1, got %d, %v", deleted, err)
+	}
+	if n, err := r.Webhooks.FanOutEvents(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("FanOutEvents after cleanup: expected no events, got %d, %v", n, err)
+	}
+}
+
+func testFirstVerdicts(t *testing.T, r Repositories) {
+	if r.Verdicts == nil {
+		t.Skip("backend keeps no review verdicts")
+	}
+	ctx := context.Background()
+	mustCreateTeam(t, r, "backend",
+		user("u1", "Alice", true), user("u2", "Bob", true),
+		user("u3", "Charlie", true), user("u4", "Dave", true))
+	mustCreatePullRequest(t, r, "pr-1", "u1", "u2", "u3")
+
+	record := func(prId, userId string, want bool) {
+		t.Helper()
+		recorded, err := r.Verdicts.RecordFirstVerdict(ctx, prId, userId)
+		if err != nil || recorded != want {
+			t.Fatalf("RecordFirstVerdict(%q, %q): expected %v, got %v, %v", prId, userId, want, recorded, err)
+		}
+	}
+	verdicts := func(window time.Duration) []time.Duration {
+		t.Helper()
+		w, err := r.Workload.GetWorkload(ctx, window)
+		if err != nil {
+			t.Fatalf("GetWorkload: %v", err)
+		}
+		return w.FirstVerdictDurations
+	}
+
+	record("pr-1", "u2", true)
+	// only the first verdict counts, other users and pull requests are not assigned
+	record("pr-1", "u2", false)
+	record("pr-1", "u4", false)
+	record("pr-2", "u2", false)
+
+	got := verdicts(time.Hour)
+	if len(got) != 1 || got[0] < 0 || got[0] > time.Minute {
+		t.Fatalf("expected one verdict right after the assignment, got %v", got)
+	}
+
+	// the new reviewer starts without a verdict
+	if err := r.PullRequests.ReplaceReviewer(ctx, "pr-1", "u2", "u4", 0); err != nil {
+		t.Fatalf("ReplaceReviewer: %v", err)
+	}
+	if got = verdicts(time.Hour); len(got) != 0 {
+		t.Fatalf("expected no verdicts after the replacement, got %v", got)
+	}
+	record("pr-1", "u2", false)
+	record("pr-1", "u4", true)
+
+	if got = verdicts(-time.Second); len(got) != 0 {
+		t.Fatalf("expected no verdicts within a negative window, got %v", got)
+	}
+}
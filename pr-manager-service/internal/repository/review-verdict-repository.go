@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewVerdictRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.ReviewVerdictRepositoryInterface = (*ReviewVerdictRepository)(nil)
+
+func NewReviewVerdictRepository(pool *pgxpool.Pool, opts ...Option) *ReviewVerdictRepository {
+	return &ReviewVerdictRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+func (r *ReviewVerdictRepository) RecordFirstVerdict(ctx context.Context, prId, userId string) (bool, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (bool, error) {
+		return r.recordFirstVerdict(ctx, prId, userId)
+	})
+}
+
+func (r *ReviewVerdictRepository) recordFirstVerdict(ctx context.Context, prId, userId string) (bool, error) {
+	updateSQL := `
+		UPDATE reviewer_assignments
+		SET first_verdict_at = CURRENT_TIMESTAMP
+		WHERE pull_request_id = $1 AND user_id = $2
+		  AND first_verdict_at IS NULL
+	`
+	ct, err := r.pool.Exec(ctx, updateSQL, prId, userId)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RosterRepository struct {
+	pool *pgxpool.Pool
+}
+
+var _ uc.RosterRepositoryInterface = (*RosterRepository)(nil)
+
+// NewRosterRepository takes no call options: an import runs the whole roster
+// through one transaction, so it is bounded by the request context instead of
+// DB_QUERY_TIMEOUT, and it is not retried
+func NewRosterRepository(pool *pgxpool.Pool) *RosterRepository {
+	return &RosterRepository{pool: pool}
+}
+
+func (r *RosterRepository) ListTeamRosters(ctx context.Context) ([]domain.TeamRoster, error) {
+	listSQL := `
+		SELECT t.team_name, u.user_id, u.username, u.is_active
+		FROM teams t
+		LEFT JOIN memberships m ON m.team_name = t.team_name
+		LEFT JOIN users u ON u.user_id = m.user_id
+		ORDER BY t.team_name, u.username, u.user_id
+	`
+	rows, err := r.pool.Query(ctx, listSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rosters []domain.TeamRoster
+	for rows.Next() {
+		var (
+			teamName string
+			userId   *string
+			username *string
+			isActive *bool
+		)
+		err = rows.Scan(&teamName, &userId, &username, &isActive)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rosters) == 0 || rosters[len(rosters)-1].TeamName != teamName {
+			rosters = append(rosters, domain.TeamRoster{TeamName: teamName})
+		}
+		// a team without members has a single row of NULLs
+		if userId == nil {
+			continue
+		}
+		last := &rosters[len(rosters)-1]
+		last.Members = append(last.Members, domain.User{
+			UserId:   *userId,
+			UserName: *username,
+			IsActive: *isActive,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rosters, nil
+}
+
+func (r *RosterRepository) UpsertTeamRosters(ctx context.Context, rosters []domain.TeamRoster) (err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	insertTeamSQL := `
+		INSERT INTO teams (team_name)
+		VALUES ($1)
+		ON CONFLICT (team_name) DO NOTHING
+	`
+	upsertUserSQL := `
+		INSERT INTO users (user_id, username, is_active)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			username   = EXCLUDED.username,
+			is_active  = EXCLUDED.is_active,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE users.username <> EXCLUDED.username
+		   OR users.is_active <> EXCLUDED.is_active
+	`
+	insertMembershipSQL := `
+		INSERT INTO memberships (user_id, team_name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, team_name) DO NOTHING
+	`
+
+	for _, t := range rosters {
+		_, err = tx.Exec(ctx, insertTeamSQL, t.TeamName)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range t.Members {
+			_, err = tx.Exec(ctx, upsertUserSQL, u.UserId, u.UserName, u.IsActive)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, insertMembershipSQL, u.UserId, t.TeamName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var errDirtySchema = errors.New("schema_migrations is dirty, fix the failed migration first")
+
+type SnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+var _ uc.SnapshotRepositoryInterface = (*SnapshotRepository)(nil)
+
+// NewSnapshotRepository takes no call options: export and restore stream the
+// response or request body through one transaction, their duration grows with
+// the data and the client, and a consumed body cannot be retried. They are
+// bounded by the request context instead of DB_QUERY_TIMEOUT.
+func NewSnapshotRepository(pool *pgxpool.Pool) *SnapshotRepository {
+	return &SnapshotRepository{pool: pool}
+}
+
+// queryRower is implemented by both the pool and a transaction
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *SnapshotRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	return schemaVersion(ctx, r.pool)
+}
+
+// Reads the version golang-migrate keeps in schema_migrations
+func schemaVersion(ctx context.Context, q queryRower) (int64, error) {
+	versionSQL := `
+		SELECT version, dirty
+		FROM schema_migrations
+		LIMIT 1
+	`
+	var (
+		version int64
+		dirty   bool
+	)
+	err := q.QueryRow(ctx, versionSQL).Scan(&version, &dirty)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, errDirtySchema
+	}
+	return version, nil
+}
+
+func (r *SnapshotRepository) ExportSnapshot(ctx context.Context, w uc.SnapshotWriterInterface) error {
+	// all sections are read from the same point in time
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	err = w.WriteHeader(domain.SnapshotHeader{
+		FormatVersion: domain.SnapshotFormatVersion,
+		SchemaVersion: version,
+		CreatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	teamsSQL := `SELECT team_name FROM teams ORDER BY team_name`
+	err = exportRows(ctx, tx, teamsSQL, func(rows pgx.Rows) error {
+		var t domain.Team
+		if err := rows.Scan(&t.TeamName); err != nil {
+			return err
+		}
+		return w.WriteTeam(t)
+	})
+	if err != nil {
+		return err
+	}
+
+	usersSQL := `SELECT user_id, username, is_active FROM users ORDER BY user_id`
+	err = exportRows(ctx, tx, usersSQL, func(rows pgx.Rows) error {
+		var u domain.User
+		if err := rows.Scan(&u.UserId, &u.UserName, &u.IsActive); err != nil {
+			return err
+		}
+		return w.WriteUser(u)
+	})
+	if err != nil {
+		return err
+	}
+
+	membershipsSQL := `SELECT team_name, user_id FROM memberships ORDER BY team_name, user_id`
+	err = exportRows(ctx, tx, membershipsSQL, func(rows pgx.Rows) error {
+		var m domain.Membership
+		if err := rows.Scan(&m.TeamName, &m.UserId); err != nil {
+			return err
+		}
+		return w.WriteMembership(m)
+	})
+	if err != nil {
+		return err
+	}
+
+	prsSQL := `
+		SELECT pull_request_id, pull_request_name, author_id, status_id,
+		       need_more_reviewers, created_at, mergedAt, version
+		FROM pull_requests
+		ORDER BY pull_request_id
+	`
+	err = exportRows(ctx, tx, prsSQL, func(rows pgx.Rows) error {
+		var pr domain.SnapshotPullRequest
+		err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId,
+			&pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.Version)
+		if err != nil {
+			return err
+		}
+		return w.WritePullRequest(pr)
+	})
+	if err != nil {
+		return err
+	}
+
+	assignmentsSQL := `
+		SELECT pull_request_id, user_id, slot, created_at, first_verdict_at
+		FROM reviewer_assignments
+		ORDER BY pull_request_id, slot
+	`
+	err = exportRows(ctx, tx, assignmentsSQL, func(rows pgx.Rows) error {
+		var a domain.ReviewerAssignment
+		if err := rows.Scan(&a.PullRequestId, &a.UserId, &a.Slot, &a.CreatedAt, &a.FirstVerdictAt); err != nil {
+			return err
+		}
+		return w.WriteAssignment(a)
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func exportRows(ctx context.Context, tx pgx.Tx, query string, row func(rows pgx.Rows) error) error {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := row(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *SnapshotRepository) RestoreSnapshot(ctx context.Context, snap *domain.Snapshot) (err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	// nobody may write between the emptiness check and the load
+	lockSQL := `LOCK TABLE teams, users, memberships, pull_requests, reviewer_assignments IN EXCLUSIVE MODE`
+	_, err = tx.Exec(ctx, lockSQL)
+	if err != nil {
+		return err
+	}
+
+	emptySQL := `
+		SELECT NOT EXISTS (SELECT 1 FROM teams)
+		   AND NOT EXISTS (SELECT 1 FROM users)
+		   AND NOT EXISTS (SELECT 1 FROM pull_requests)
+	`
+	var empty bool
+	err = tx.QueryRow(ctx, emptySQL).Scan(&empty)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		err = uc.ErrDatabaseNotEmpty
+		return err
+	}
+
+	teams := make([][]any, 0, len(snap.Teams))
+	for _, t := range snap.Teams {
+		teams = append(teams, []any{t.TeamName})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"teams"}, []string{"team_name"}, pgx.CopyFromRows(teams))
+	if err != nil {
+		return err
+	}
+
+	users := make([][]any, 0, len(snap.Users))
+	for _, u := range snap.Users {
+		users = append(users, []any{u.UserId, u.UserName, u.IsActive})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"users"}, []string{"user_id", "username", "is_active"}, pgx.CopyFromRows(users))
+	if err != nil {
+		return err
+	}
+
+	memberships := make([][]any, 0, len(snap.Memberships))
+	for _, m := range snap.Memberships {
+		memberships = append(memberships, []any{m.UserId, m.TeamName})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"memberships"}, []string{"user_id", "team_name"}, pgx.CopyFromRows(memberships))
+	if err != nil {
+		return err
+	}
+
+	prs := make([][]any, 0, len(snap.PullRequests))
+	for _, pr := range snap.PullRequests {
+		prs = append(prs, []any{pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.StatusId,
+			pr.NeedMoreReviewers, pr.CreatedAt, pr.MergedAt, pr.Version})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"pull_requests"},
+		[]string{"pull_request_id", "pull_request_name", "author_id", "status_id", "need_more_reviewers", "created_at", "mergedat", "version"},
+		pgx.CopyFromRows(prs))
+	if err != nil {
+		return err
+	}
+
+	assignments := make([][]any, 0, len(snap.Assignments))
+	for _, a := range snap.Assignments {
+		assignments = append(assignments, []any{a.UserId, a.PullRequestId, a.Slot, a.CreatedAt, a.FirstVerdictAt})
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"reviewer_assignments"},
+		[]string{"user_id", "pull_request_id", "slot", "created_at", "first_verdict_at"},
+		pgx.CopyFromRows(assignments))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"pr-manager-service/internal/repository/repotest"
+)
+
+func TestRepositories_Contract(t *testing.T) {
+	repotest.RunContract(t, func(t *testing.T) repotest.Repositories {
+		db, err := Open(context.Background(), filepath.Join(t.TempDir(), "prm.db"))
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = db.Close()
+		})
+
+		return repotest.Repositories{
+			Teams:        NewTeamRepository(db),
+			Users:        NewUserRepository(db),
+			PullRequests: NewPullRequestRepository(db),
+			Idempotency:  NewIdempotencyRepository(db),
+			Workload:     NewWorkloadRepository(db),
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+)
+
+func TestOpen_KeepsDataAndMigratesOnce(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "prm.db")
+
+	db, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	err = NewTeamRepository(db).CreateTeam(ctx, "backend", []domain.User{{UserId: "u1", UserName: "Alice", IsActive: true}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	_ = db.Close()
+
+	db, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	migrations, err := listMigrations()
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	var applied int
+	if err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatalf("count migrations: %v", err)
+	}
+	if applied != len(migrations) {
+		t.Fatalf("expected %d applied migrations, got %d", len(migrations), applied)
+	}
+
+	rosters, err := NewRosterRepository(db).ListTeamRosters(ctx)
+	if err != nil {
+		t.Fatalf("list rosters: %v", err)
+	}
+	if len(rosters) != 1 || rosters[0].TeamName != "backend" || len(rosters[0].Members) != 1 {
+		t.Fatalf("expected the team to survive a reopen, got %+v", rosters)
+	}
+}
+
+func TestOpen_RefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "prm.db")
+
+	db, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (999)`)
+	if err != nil {
+		t.Fatalf("bump version: %v", err)
+	}
+	_ = db.Close()
+
+	_, err = Open(ctx, path)
+	if err == nil || !strings.Contains(err.Error(), "version 999") {
+		t.Fatalf("expected the newer schema to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+var _ uc.IdempotencyRepositoryInterface = (*IdempotencyRepository)(nil)
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Inserts the key or takes over an expired one, a pending key expires when
+// its lease runs out. Otherwise returns the existing record. Both statements run on the single connection,
+// so the record can't expire in between.
+func (r *IdempotencyRepository) ReserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	reserveSQL := `
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+		VALUES (?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now', ?))
+		ON CONFLICT (key)
+		DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			status_code = NULL,
+			headers     = NULL,
+			body        = NULL,
+			created_at  = strftime('%Y-%m-%dT%H:%M:%fZ', 'now'),
+			expires_at  = excluded.expires_at
+		WHERE idempotency_keys.expires_at <= strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		RETURNING key
+	`
+	modifier := fmt.Sprintf("%+.3f seconds", lease.Seconds())
+
+	var reserved string
+	err := r.db.QueryRowContext(ctx, reserveSQL, key, fingerprint, modifier).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	getSQL := `
+		SELECT fingerprint, status_code, headers, body
+		FROM idempotency_keys
+		WHERE key = ?
+	`
+	rec := domain.IdempotencyRecord{Key: key}
+	var (
+		statusCode sql.NullInt64
+		headers    sql.NullString
+		body       []byte
+	)
+	err = r.db.QueryRowContext(ctx, getSQL, key).Scan(&rec.Fingerprint, &statusCode, &headers, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode.Valid {
+		rec.Response = &domain.StoredResponse{
+			StatusCode: int(statusCode.Int64),
+			Body:       body,
+		}
+		if headers.Valid {
+			if err = json.Unmarshal([]byte(headers.String), &rec.Response.Headers); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &rec, nil
+}
+
+// Saves the response and extends the lease of the key to the ttl
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error {
+	headers, err := json.Marshal(resp.Headers)
+	if err != nil {
+		return err
+	}
+
+	updateSQL := `
+		UPDATE idempotency_keys
+		SET status_code = ?, headers = ?, body = ?,
+		    expires_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now', ?)
+		WHERE key = ?
+	`
+	modifier := fmt.Sprintf("%+.3f seconds", ttl.Seconds())
+	res, err := r.db.ExecContext(ctx, updateSQL, resp.StatusCode, string(headers), resp.Body, modifier, key)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) ReleaseKey(ctx context.Context, key string) error {
+	deleteSQL := `
+		DELETE FROM idempotency_keys
+		WHERE key = ? AND status_code IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, deleteSQL, key)
+	return err
+}
+
+func (r *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	deleteSQL := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+	`
+	res, err := r.db.ExecContext(ctx, deleteSQL)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,55 @@
+-- pull-request-manager-service, SQLite flavour of the Postgres schema
+-- timestamps are UTC text in RFC 3339 with milliseconds
+
+CREATE TABLE teams (
+    team_name TEXT PRIMARY KEY NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE users (
+    user_id TEXT PRIMARY KEY NOT NULL,
+    username TEXT NOT NULL,
+    is_active INTEGER NOT NULL CHECK (is_active IN (0, 1)),
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE statuses (
+    id INTEGER PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE memberships (
+    user_id TEXT NOT NULL REFERENCES users(user_id),
+    team_name TEXT NOT NULL REFERENCES teams(team_name),
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+    PRIMARY KEY (user_id, team_name)
+);
+
+CREATE TABLE pull_requests (
+    pull_request_id TEXT PRIMARY KEY NOT NULL,
+    pull_request_name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(user_id),
+    status_id INTEGER NOT NULL DEFAULT 1 REFERENCES statuses(id),
+    need_more_reviewers INTEGER NOT NULL DEFAULT 1,
+    merged_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE reviewer_assignments (
+    user_id TEXT NOT NULL REFERENCES users(user_id),
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id),
+    slot INTEGER NOT NULL CHECK (slot IN (1, 2)),
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+
+    PRIMARY KEY (pull_request_id, slot),
+    UNIQUE (pull_request_id, user_id)
+);
+
+INSERT INTO statuses (id, name) VALUES (1, 'OPEN'), (2, 'MERGED');
+CREATE INDEX team_name_mem_idx ON memberships (team_name);
+CREATE INDEX user_id_mem_idx ON memberships (user_id);
+CREATE INDEX usr_id_idx ON reviewer_assignments (user_id);
@@ -0,0 +1,14 @@
+-- responses of requests sent with an Idempotency-Key, kept until expires_at
+
+CREATE TABLE idempotency_keys (
+    key TEXT PRIMARY KEY NOT NULL,
+    fingerprint TEXT NOT NULL,
+    -- NULL while the first request is in progress
+    status_code INTEGER,
+    headers TEXT,
+    body BLOB,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    expires_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,283 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type PullRequestRepository struct {
+	db *sql.DB
+}
+
+var _ uc.PullRequestRepositoryInterface = (*PullRequestRepository)(nil)
+
+func NewPullRequestRepository(db *sql.DB) *PullRequestRepository {
+	return &PullRequestRepository{db: db}
+}
+
+func (r *PullRequestRepository) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	insertPrSQL := `
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
+		VALUES (?, ?, ?)
+		RETURNING version
+	`
+	err = tx.QueryRowContext(ctx, insertPrSQL, pr.PullRequestId, pr.PullRequestName, pr.AuthorId).Scan(&pr.Version)
+	if err != nil {
+		if isPrimaryKeyViolation(err) {
+			err = uc.ErrPullRequestAlreadyExists
+		}
+		return err
+	}
+
+	insertReviewerSQL := `
+		INSERT INTO reviewer_assignments (user_id, pull_request_id, slot)
+		VALUES (?, ?, ?)
+	`
+	for i, reviewerId := range pr.AssignedReviewers {
+		slot := i + 1
+		_, err = tx.ExecContext(ctx, insertReviewerSQL, reviewerId, pr.PullRequestId, slot)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepository) GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+	getPrSQL := `
+		SELECT pull_request_id, pull_request_name, author_id, status_id, version
+		FROM pull_requests
+		WHERE pull_request_id = ?
+	`
+	var pr domain.PullRequest
+	err := r.db.QueryRowContext(ctx, getPrSQL, prId).
+		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &pr.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := getReviewers(ctx, r.db, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.AssignedReviewers = reviewers
+	return &pr, nil
+}
+
+func (r *PullRequestRepository) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (_ *domain.PullRequest, merged bool, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// The status before the update tells whether this call merges the PR
+	var prevStatusId int
+	err = tx.QueryRowContext(ctx, `SELECT status_id FROM pull_requests WHERE pull_request_id = ?`, prId).
+		Scan(&prevStatusId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Merge is idempotent, the first merge time and version are kept
+	updateSQL := `
+		UPDATE pull_requests
+		SET status_id  = 2,
+		    merged_at  = COALESCE(merged_at, strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+		    version    = version + CASE WHEN status_id = 2 THEN 0 ELSE 1 END,
+		    updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		WHERE pull_request_id = ?
+		  AND (? = 0 OR version = ?)
+		RETURNING pull_request_id, pull_request_name, author_id, status_id, version
+	`
+	var pr domain.PullRequest
+	err = tx.QueryRowContext(ctx, updateSQL, prId, expectedVersion, expectedVersion).
+		Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &pr.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = missingOrStale(ctx, tx, prId)
+		}
+		return nil, false, err
+	}
+
+	reviewers, err := getReviewers(ctx, tx, prId)
+	if err != nil {
+		return nil, false, err
+	}
+	pr.AssignedReviewers = reviewers
+
+	return &pr, prevStatusId != 2, nil
+}
+
+func (r *PullRequestRepository) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	// rowid breaks ties between pull requests created within a millisecond
+	querySQL := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status_id, p.created_at
+		FROM pull_requests p
+		JOIN reviewer_assignments r ON p.pull_request_id = r.pull_request_id
+		WHERE r.user_id = ?
+		ORDER BY p.created_at, p.rowid
+	`
+	rows, err := r.db.QueryContext(ctx, querySQL, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []domain.PullRequest
+	for rows.Next() {
+		var (
+			pr        domain.PullRequest
+			createdAt string
+		)
+		err = rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId, &pr.StatusId, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		pr.CreatedAt, err = parseTime(createdAt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pr)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prId string, oldUserId string, newUserId string, expectedVersion int64) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	bumpSQL := `
+		UPDATE pull_requests
+		SET version    = version + 1,
+		    updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		WHERE pull_request_id = ?
+		  AND (? = 0 OR version = ?)
+	`
+	res, err := tx.ExecContext(ctx, bumpSQL, prId, expectedVersion, expectedVersion)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return missingOrStale(ctx, tx, prId)
+	}
+
+	updateSQL := `
+		UPDATE reviewer_assignments
+		SET user_id = ?
+		WHERE pull_request_id = ? AND user_id = ?
+	`
+	res, err = tx.ExecContext(ctx, updateSQL, newUserId, prId, oldUserId)
+	if err != nil {
+		return err
+	}
+	affected, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Tells why a versioned update matched no rows: sql.ErrNoRows if the PR
+// doesn't exist, ErrStaleVersion if it has another version
+func missingOrStale(ctx context.Context, tx *sql.Tx, prId string) error {
+	existsSQL := `
+		SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = ?)
+	`
+	var exists bool
+	if err := tx.QueryRowContext(ctx, existsSQL, prId).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return uc.ErrStaleVersion
+}
+
+func (r *PullRequestRepository) GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	querySQL := `
+		SELECT u.user_id, u.username, u.is_active
+		FROM memberships m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = ?
+		  AND u.is_active = 1
+	`
+	return queryUsers(ctx, r.db, querySQL, teamName)
+}
+
+// Returns reviewers of the PR ordered by slot
+func getReviewers(ctx context.Context, q queryer, prId string) ([]string, error) {
+	getReviewersSQL := `
+		SELECT user_id
+		FROM reviewer_assignments
+		WHERE pull_request_id = ?
+		ORDER BY slot
+	`
+	rows, err := q.QueryContext(ctx, getReviewersSQL, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var reviewers []string
+	for rows.Next() {
+		var userId string
+		err = rows.Scan(&userId)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, userId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type RosterRepository struct {
+	db *sql.DB
+}
+
+var _ uc.RosterRepositoryInterface = (*RosterRepository)(nil)
+
+func NewRosterRepository(db *sql.DB) *RosterRepository {
+	return &RosterRepository{db: db}
+}
+
+func (r *RosterRepository) ListTeamRosters(ctx context.Context) ([]domain.TeamRoster, error) {
+	listSQL := `
+		SELECT t.team_name, u.user_id, u.username, u.is_active
+		FROM teams t
+		LEFT JOIN memberships m ON m.team_name = t.team_name
+		LEFT JOIN users u ON u.user_id = m.user_id
+		ORDER BY t.team_name, u.username, u.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, listSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var rosters []domain.TeamRoster
+	for rows.Next() {
+		var (
+			teamName string
+			userId   sql.NullString
+			username sql.NullString
+			isActive sql.NullBool
+		)
+		err = rows.Scan(&teamName, &userId, &username, &isActive)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rosters) == 0 || rosters[len(rosters)-1].TeamName != teamName {
+			rosters = append(rosters, domain.TeamRoster{TeamName: teamName})
+		}
+		// a team without members has a single row of NULLs
+		if !userId.Valid {
+			continue
+		}
+		last := &rosters[len(rosters)-1]
+		last.Members = append(last.Members, domain.User{
+			UserId:   userId.String,
+			UserName: username.String,
+			IsActive: isActive.Bool,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rosters, nil
+}
+
+func (r *RosterRepository) UpsertTeamRosters(ctx context.Context, rosters []domain.TeamRoster) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	insertTeamSQL := `
+		INSERT INTO teams (team_name)
+		VALUES (?)
+		ON CONFLICT (team_name) DO NOTHING
+	`
+	for _, t := range rosters {
+		_, err = tx.ExecContext(ctx, insertTeamSQL, t.TeamName)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range t.Members {
+			err = upsertUser(ctx, tx, u)
+			if err != nil {
+				return err
+			}
+
+			err = insertMembership(ctx, tx, u.UserId, t.TeamName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
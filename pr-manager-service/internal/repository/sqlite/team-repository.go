@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+var _ uc.TeamRepositoryInterface = (*TeamRepository)(nil)
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, members []domain.User) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	createTeamSQL := `
+		INSERT INTO teams (team_name)
+		VALUES (?)
+	`
+	_, err = tx.ExecContext(ctx, createTeamSQL, teamName)
+	if err != nil {
+		if isPrimaryKeyViolation(err) {
+			err = uc.ErrTeamAlreadyExists
+		}
+		return err
+	}
+
+	for _, u := range members {
+		err = upsertUser(ctx, tx, u)
+		if err != nil {
+			return err
+		}
+
+		err = insertMembership(ctx, tx, u.UserId, teamName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
+	getTeamSQL := `
+		SELECT team_name
+		FROM teams
+		WHERE team_name = ?
+	`
+	var t domain.Team
+	err := r.db.QueryRowContext(ctx, getTeamSQL, teamName).Scan(&t.TeamName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	getMembersSQL := `
+		SELECT u.user_id, u.username, u.is_active
+		FROM memberships m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = ?
+		ORDER BY u.username
+	`
+	members, err := queryUsers(ctx, r.db, getMembersSQL, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &t, members, nil
+}
+
+// Creates the user or updates the username and active flag of an existing one
+func upsertUser(ctx context.Context, tx *sql.Tx, u domain.User) error {
+	upsertUserSQL := `
+		INSERT INTO users (user_id, username, is_active)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			username   = excluded.username,
+			is_active  = excluded.is_active,
+			updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		WHERE users.username <> excluded.username
+		   OR users.is_active <> excluded.is_active
+	`
+	_, err := tx.ExecContext(ctx, upsertUserSQL, u.UserId, u.UserName, u.IsActive)
+	return err
+}
+
+func insertMembership(ctx context.Context, tx *sql.Tx, userId, teamName string) error {
+	insertMembershipSQL := `
+		INSERT INTO memberships (user_id, team_name)
+		VALUES (?, ?)
+		ON CONFLICT (user_id, team_name) DO NOTHING
+	`
+	_, err := tx.ExecContext(ctx, insertMembershipSQL, userId, teamName)
+	return err
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Runs a query selecting user_id, username and is_active
+func queryUsers(ctx context.Context, q queryer, query string, args ...any) ([]domain.User, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		err = rows.Scan(&u.UserId, &u.UserName, &u.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
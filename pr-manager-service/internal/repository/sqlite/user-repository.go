@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+var _ uc.UserRepositoryInterface = (*UserRepository)(nil)
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, userId string) (*domain.User, error) {
+	getUserSQL := `
+		SELECT user_id, username, is_active
+		FROM users
+		WHERE user_id = ?
+	`
+	var u domain.User
+	err := r.db.QueryRowContext(ctx, getUserSQL, userId).Scan(&u.UserId, &u.UserName, &u.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
+	updateSQL := `
+		UPDATE users
+		SET is_active = ?,
+		    updated_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		WHERE user_id = ?
+		RETURNING user_id, username, is_active
+	`
+	var u domain.User
+	err := r.db.QueryRowContext(ctx, updateSQL, isActive, userId).
+		Scan(&u.UserId, &u.UserName, &u.IsActive)
+	if err != nil {
+		return nil, "", err
+	}
+
+	teamName, err := r.GetTeamName(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &u, "", nil
+		}
+		return nil, "", err
+	}
+
+	return &u, teamName, nil
+}
+
+func (r *UserRepository) GetTeamName(ctx context.Context, userId string) (string, error) {
+	getTeamSQL := `
+		SELECT team_name
+		FROM memberships
+		WHERE user_id = ?
+		ORDER BY team_name
+		LIMIT 1
+	`
+	var teamName string
+	err := r.db.QueryRowContext(ctx, getTeamSQL, userId).Scan(&teamName)
+	if err != nil {
+		return "", err
+	}
+	return teamName, nil
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+)
+
+type WorkloadRepository struct {
+	db *sql.DB
+}
+
+var _ uc.WorkloadRepositoryInterface = (*WorkloadRepository)(nil)
+
+func NewWorkloadRepository(db *sql.DB) *WorkloadRepository {
+	return &WorkloadRepository{db: db}
+}
+
+// Reads all parts of the workload in one transaction, so they agree
+func (r *WorkloadRepository) GetWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Inactive users are kept while they still have open reviews
+	byUserSQL := `
+		SELECT u.user_id, COUNT(p.pull_request_id)
+		FROM users u
+		LEFT JOIN reviewer_assignments r ON r.user_id = u.user_id
+		LEFT JOIN pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status_id = 1
+		WHERE u.is_active = 1 OR p.pull_request_id IS NOT NULL
+		GROUP BY u.user_id
+	`
+	byTeamSQL := `
+		SELECT t.team_name, COUNT(p.pull_request_id)
+		FROM teams t
+		LEFT JOIN memberships m ON m.team_name = t.team_name
+		LEFT JOIN reviewer_assignments r ON r.user_id = m.user_id
+		LEFT JOIN pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status_id = 1
+		GROUP BY t.team_name
+	`
+	unfilledSQL := `
+		SELECT COUNT(*)
+		FROM pull_requests p
+		WHERE p.status_id = 1
+		  AND (SELECT COUNT(*) FROM reviewer_assignments r WHERE r.pull_request_id = p.pull_request_id) < 2
+	`
+	agesSQL := `
+		SELECT (julianday('now') - julianday(created_at)) * 86400.0
+		FROM pull_requests
+		WHERE status_id = 1
+	`
+	mergeSQL := `
+		SELECT (julianday(merged_at) - julianday(created_at)) * 86400.0
+		FROM pull_requests
+		WHERE status_id = 2
+		  AND merged_at >= strftime('%Y-%m-%dT%H:%M:%fZ', 'now', ?)
+	`
+
+	w := &domain.Workload{}
+	if w.OpenAssignmentsByUser, err = queryCounts(ctx, tx, byUserSQL); err != nil {
+		return nil, err
+	}
+	if w.OpenAssignmentsByTeam, err = queryCounts(ctx, tx, byTeamSQL); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRowContext(ctx, unfilledSQL).Scan(&w.UnfilledPullRequests); err != nil {
+		return nil, err
+	}
+	if w.OpenPullRequestAges, err = queryDurations(ctx, tx, agesSQL); err != nil {
+		return nil, err
+	}
+	modifier := fmt.Sprintf("%+.3f seconds", -mergedWindow.Seconds())
+	if w.MergeDurations, err = queryDurations(ctx, tx, mergeSQL, modifier); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reads (name, count) rows into a map
+func queryCounts(ctx context.Context, tx *sql.Tx, query string, args ...any) (map[string]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			name  string
+			count int
+		)
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+// Reads rows of seconds into durations
+func queryDurations(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]time.Duration, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []time.Duration
+	for rows.Next() {
+		var seconds float64
+		if err := rows.Scan(&seconds); err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Duration(seconds*float64(time.Second)))
+	}
+	return durations, rows.Err()
+}
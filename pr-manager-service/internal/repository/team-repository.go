@@ -12,16 +12,23 @@ import (
 )
 
 type TeamRepository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	policy callPolicy
 }
 
 var _ uc.TeamRepositoryInterface = (*TeamRepository)(nil)
 
-func NewTeamRepository(pool *pgxpool.Pool) *TeamRepository {
-	return &TeamRepository{pool: pool}
+func NewTeamRepository(pool *pgxpool.Pool, opts ...Option) *TeamRepository {
+	return &TeamRepository{pool: pool, policy: newCallPolicy(opts)}
 }
 
 func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, members []domain.User) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.createTeam(ctx, teamName, members)
+	})
+}
+
+func (r *TeamRepository) createTeam(ctx context.Context, teamName string, members []domain.User) (err error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -30,7 +37,7 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, member
 		if err != nil {
 			_ = tx.Rollback(ctx)
 		} else {
-			_ = tx.Commit(ctx)
+			err = tx.Commit(ctx)
 		}
 	}()
 
@@ -41,6 +48,9 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, member
 	`
 	_, err = tx.Exec(ctx, createTeamSQL, teamName)
 	if err != nil {
+		if isUniqueViolation(err, "teams_pkey") {
+			err = uc.ErrTeamAlreadyExists
+		}
 		return err
 	}
 
@@ -74,7 +84,15 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string, member
 	return nil
 }
 
-func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
+func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (team *domain.Team, members []domain.User, err error) {
+	err = r.policy.run(ctx, func(ctx context.Context) error {
+		team, members, err = r.getTeam(ctx, teamName)
+		return err
+	})
+	return team, members, err
+}
+
+func (r *TeamRepository) getTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error) {
 	getTeamSQL := `
 		SELECT team_name
 		FROM teams
@@ -0,0 +1,17 @@
+package repository
+
+import "testing"
+
+func TestQueryOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":                              "SELECT",
+		"\n\t\tinsert INTO teams (team_name)\n": "INSERT",
+		"-- teams\n\nCREATE TABLE teams ();":    "CREATE",
+		"":                                      "QUERY",
+	}
+	for sql, want := range tests {
+		if got := queryOperation(sql); got != want {
+			t.Errorf("queryOperation(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
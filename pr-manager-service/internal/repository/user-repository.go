@@ -12,16 +12,23 @@ import (
 )
 
 type UserRepository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	policy callPolicy
 }
 
 var _ uc.UserRepositoryInterface = (*UserRepository)(nil)
 
-func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
-	return &UserRepository{pool: pool}
+func NewUserRepository(pool *pgxpool.Pool, opts ...Option) *UserRepository {
+	return &UserRepository{pool: pool, policy: newCallPolicy(opts)}
 }
 
 func (r *UserRepository) GetUser(ctx context.Context, userId string) (*domain.User, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.User, error) {
+		return r.getUser(ctx, userId)
+	})
+}
+
+func (r *UserRepository) getUser(ctx context.Context, userId string) (*domain.User, error) {
 	getUserSQL := `
 		SELECT user_id, username, is_active
 		FROM users
@@ -38,7 +45,15 @@ func (r *UserRepository) GetUser(ctx context.Context, userId string) (*domain.Us
 	return &u, nil
 }
 
-func (r *UserRepository) SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
+func (r *UserRepository) SetIsActive(ctx context.Context, userId string, isActive bool) (user *domain.User, teamName string, err error) {
+	err = r.policy.run(ctx, func(ctx context.Context) error {
+		user, teamName, err = r.setIsActive(ctx, userId, isActive)
+		return err
+	})
+	return user, teamName, err
+}
+
+func (r *UserRepository) setIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error) {
 	updateSQL := `
 		UPDATE users
 		SET is_active = $2,
@@ -77,6 +92,12 @@ func (r *UserRepository) SetIsActive(ctx context.Context, userId string, isActiv
 }
 
 func (r *UserRepository) GetTeamName(ctx context.Context, userId string) (string, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (string, error) {
+		return r.getTeamName(ctx, userId)
+	})
+}
+
+func (r *UserRepository) getTeamName(ctx context.Context, userId string) (string, error) {
 	getTeamSQL := `
 		SELECT team_name
 		FROM memberships
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.WebhookRepositoryInterface = (*WebhookRepository)(nil)
+
+func NewWebhookRepository(pool *pgxpool.Pool, opts ...Option) *WebhookRepository {
+	return &WebhookRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.createSubscription(ctx, sub)
+	})
+}
+
+func (r *WebhookRepository) createSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	insertSQL := `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, is_active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, insertSQL, sub.Url, sub.Secret, sub.EventTypes, sub.IsActive).
+		Scan(&sub.Id)
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.WebhookSubscription, error) {
+		return r.listSubscriptions(ctx)
+	})
+}
+
+func (r *WebhookRepository) listSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	querySQL := `
+		SELECT id, url, secret, event_types, is_active
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+	rows, err := r.pool.Query(ctx, querySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.WebhookSubscription
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		err = rows.Scan(&sub.Id, &sub.Url, &sub.Secret, &sub.EventTypes, &sub.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sub)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.deleteSubscription(ctx, id)
+	})
+}
+
+func (r *WebhookRepository) deleteSubscription(ctx context.Context, id int64) error {
+	deleteSQL := `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	ct, err := r.pool.Exec(ctx, deleteSQL, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Turns unprocessed outbox events into deliveries for every matching subscription
+func (r *WebhookRepository) FanOutEvents(ctx context.Context, limit int) (int, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (int, error) {
+		return r.fanOutEvents(ctx, limit)
+	})
+}
+
+func (r *WebhookRepository) fanOutEvents(ctx context.Context, limit int) (_ int, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	selectSQL := `
+		SELECT id, event_type
+		FROM outbox_events
+		WHERE processed_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.Query(ctx, selectSQL, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	type outboxEvent struct {
+		id        int64
+		eventType string
+	}
+	var events []outboxEvent
+	for rows.Next() {
+		var ev outboxEvent
+		err = rows.Scan(&ev.id, &ev.eventType)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, ev)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Empty event_types means the subscription receives every event
+	insertDeliveriesSQL := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id)
+		SELECT id, $1
+		FROM webhook_subscriptions
+		WHERE is_active = true
+		  AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+	markProcessedSQL := `
+		UPDATE outbox_events
+		SET processed_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	for _, ev := range events {
+		_, err = tx.Exec(ctx, insertDeliveriesSQL, ev.id, ev.eventType)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(ctx, markProcessedSQL, ev.id)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// Claims due deliveries by pushing their next attempt forward by the lease,
+// so concurrent workers and replicas do not send the same delivery twice
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) ([]domain.WebhookDelivery, error) {
+		return r.claimDueDeliveries(ctx, limit, lease)
+	})
+}
+
+func (r *WebhookRepository) claimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	claimSQL := `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			FROM due
+			WHERE d.id = due.id
+			RETURNING d.id, d.attempts, d.subscription_id, d.event_id
+		)
+		SELECT c.id, c.attempts, s.id, s.url, s.secret, s.event_types, s.is_active,
+		       e.id, e.event_type, e.payload, e.created_at
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		JOIN outbox_events e ON e.id = c.event_id
+		ORDER BY c.id
+	`
+	rows, err := r.pool.Query(ctx, claimSQL, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		err = rows.Scan(
+			&d.Id, &d.Attempts,
+			&d.Subscription.Id, &d.Subscription.Url, &d.Subscription.Secret,
+			&d.Subscription.EventTypes, &d.Subscription.IsActive,
+			&d.EventId, &d.EventType, &d.Payload, &d.OccurredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepository) CompleteDelivery(ctx context.Context, deliveryId int64) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.completeDelivery(ctx, deliveryId)
+	})
+}
+
+func (r *WebhookRepository) completeDelivery(ctx context.Context, deliveryId int64) error {
+	deleteSQL := `
+		DELETE FROM webhook_deliveries
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, deleteSQL, deliveryId)
+	return err
+}
+
+func (r *WebhookRepository) RetryDelivery(ctx context.Context, deliveryId int64, attempts int, delay time.Duration, lastErr string) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.retryDelivery(ctx, deliveryId, attempts, delay, lastErr)
+	})
+}
+
+func (r *WebhookRepository) retryDelivery(ctx context.Context, deliveryId int64, attempts int, delay time.Duration, lastErr string) error {
+	updateSQL := `
+		UPDATE webhook_deliveries
+		SET attempts = $2,
+		    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3),
+		    last_error = $4
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, updateSQL, deliveryId, attempts, delay.Seconds(), lastErr)
+	return err
+}
+
+func (r *WebhookRepository) DeleteProcessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (int64, error) {
+		return r.deleteProcessedEvents(ctx, olderThan)
+	})
+}
+
+func (r *WebhookRepository) deleteProcessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	// Pending deliveries and dead letters keep their event for sending and inspection
+	deleteSQL := `
+		DELETE FROM outbox_events e
+		WHERE e.processed_at <= CURRENT_TIMESTAMP - make_interval(secs => $1)
+		  AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.event_id = e.id)
+		  AND NOT EXISTS (SELECT 1 FROM webhook_dead_letters l WHERE l.event_id = e.id)
+	`
+	ct, err := r.pool.Exec(ctx, deleteSQL, olderThan.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) DeleteUnprocessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (int64, error) {
+		return r.deleteUnprocessedEvents(ctx, olderThan)
+	})
+}
+
+func (r *WebhookRepository) deleteUnprocessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	// Events are referenced by deliveries only after the fan-out marks them processed
+	deleteSQL := `
+		DELETE FROM outbox_events
+		WHERE processed_at IS NULL
+		  AND created_at <= CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+	ct, err := r.pool.Exec(ctx, deleteSQL, olderThan.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) DeadLetterDelivery(ctx context.Context, deliveryId int64, attempts int, lastErr string) error {
+	return r.policy.run(ctx, func(ctx context.Context) error {
+		return r.deadLetterDelivery(ctx, deliveryId, attempts, lastErr)
+	})
+}
+
+func (r *WebhookRepository) deadLetterDelivery(ctx context.Context, deliveryId int64, attempts int, lastErr string) (err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	moveSQL := `
+		INSERT INTO webhook_dead_letters (subscription_id, event_id, attempts, last_error)
+		SELECT subscription_id, event_id, $2, $3
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+	_, err = tx.Exec(ctx, moveSQL, deliveryId, attempts, lastErr)
+	if err != nil {
+		return err
+	}
+
+	deleteSQL := `
+		DELETE FROM webhook_deliveries
+		WHERE id = $1
+	`
+	_, err = tx.Exec(ctx, deleteSQL, deliveryId)
+	return err
+}
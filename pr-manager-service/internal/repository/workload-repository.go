@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pr-manager-service/internal/domain"
+	uc "pr-manager-service/internal/usecase"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WorkloadRepository struct {
+	pool   *pgxpool.Pool
+	policy callPolicy
+}
+
+var _ uc.WorkloadRepositoryInterface = (*WorkloadRepository)(nil)
+
+func NewWorkloadRepository(pool *pgxpool.Pool, opts ...Option) *WorkloadRepository {
+	return &WorkloadRepository{pool: pool, policy: newCallPolicy(opts)}
+}
+
+// Reads all parts of the workload in one read-only snapshot
+func (r *WorkloadRepository) GetWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error) {
+	return callValue(ctx, r.policy, func(ctx context.Context) (*domain.Workload, error) {
+		return r.getWorkload(ctx, mergedWindow)
+	})
+}
+
+func (r *WorkloadRepository) getWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Inactive users are kept while they still have open reviews
+	byUserSQL := `
+		SELECT u.user_id, COUNT(p.pull_request_id)
+		FROM users u
+		LEFT JOIN reviewer_assignments r ON r.user_id = u.user_id
+		LEFT JOIN pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status_id = 1
+		WHERE u.is_active OR p.pull_request_id IS NOT NULL
+		GROUP BY u.user_id
+	`
+	byTeamSQL := `
+		SELECT t.team_name, COUNT(p.pull_request_id)
+		FROM teams t
+		LEFT JOIN memberships m ON m.team_name = t.team_name
+		LEFT JOIN reviewer_assignments r ON r.user_id = m.user_id
+		LEFT JOIN pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status_id = 1
+		GROUP BY t.team_name
+	`
+	unfilledSQL := `
+		SELECT COUNT(*)
+		FROM pull_requests p
+		WHERE p.status_id = 1
+		  AND (SELECT COUNT(*) FROM reviewer_assignments r WHERE r.pull_request_id = p.pull_request_id) < 2
+	`
+	agesSQL := `
+		SELECT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - created_at)::float8
+		FROM pull_requests
+		WHERE status_id = 1
+	`
+	mergeSQL := `
+		SELECT EXTRACT(EPOCH FROM mergedAt - created_at)::float8
+		FROM pull_requests
+		WHERE status_id = 2
+		  AND mergedAt >= CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+	verdictSQL := `
+		SELECT EXTRACT(EPOCH FROM first_verdict_at - created_at)::float8
+		FROM reviewer_assignments
+		WHERE first_verdict_at >= CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	w := &domain.Workload{}
+	if w.OpenAssignmentsByUser, err = queryCounts(ctx, tx, byUserSQL); err != nil {
+		return nil, err
+	}
+	if w.OpenAssignmentsByTeam, err = queryCounts(ctx, tx, byTeamSQL); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRow(ctx, unfilledSQL).Scan(&w.UnfilledPullRequests); err != nil {
+		return nil, err
+	}
+	if w.OpenPullRequestAges, err = queryDurations(ctx, tx, agesSQL); err != nil {
+		return nil, err
+	}
+	if w.MergeDurations, err = queryDurations(ctx, tx, mergeSQL, mergedWindow.Seconds()); err != nil {
+		return nil, err
+	}
+	if w.FirstVerdictDurations, err = queryDurations(ctx, tx, verdictSQL, mergedWindow.Seconds()); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reads (name, count) rows into a map
+func queryCounts(ctx context.Context, tx pgx.Tx, query string, args ...any) (map[string]int, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			name  string
+			count int
+		)
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+// Reads rows of seconds into durations
+func queryDurations(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]time.Duration, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []time.Duration
+	for rows.Next() {
+		var seconds float64
+		if err := rows.Scan(&seconds); err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Duration(seconds*float64(time.Second)))
+	}
+	return durations, rows.Err()
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Email digest
+
+func (s *Service) SetEmailSubscription(ctx context.Context, in SetEmailSubscriptionInput) (_ *SetEmailSubscriptionOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SetEmailSubscription")
+	defer func() { op.end(err) }()
+
+	if err := validateSetEmailSubscriptionInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "set email subscription validation failed", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.emails == nil {
+		return nil, ErrNotConfigured
+	}
+
+	// Check if the user exists
+	_, err = s.users.GetUser(ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "set email subscription: user not found", map[string]any{
+				"user_id": in.UserId,
+				"error":   err.Error(),
+			})
+			return nil, err
+		}
+
+		s.log().ErrorCtx(ctx, "set email subscription: get user repository error", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	token, err := newUnsubscribeToken()
+	if err != nil {
+		s.log().ErrorCtx(ctx, "set email subscription: generate token error", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	sub, err := s.emails.SetEmailSubscription(ctx, domain.EmailSubscription{
+		UserId:           in.UserId,
+		Email:            in.Email,
+		DigestEnabled:    in.DigestEnabled,
+		UnsubscribeToken: token,
+	})
+	if err != nil {
+		s.log().ErrorCtx(ctx, "set email subscription repository error", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "set email subscription completed", map[string]any{
+		"user_id":        sub.UserId,
+		"digest_enabled": sub.DigestEnabled,
+	})
+
+	return &SetEmailSubscriptionOutput{
+		UserId:        sub.UserId,
+		Email:         sub.Email,
+		DigestEnabled: sub.DigestEnabled,
+	}, nil
+}
+
+func (s *Service) UnsubscribeEmail(ctx context.Context, in UnsubscribeEmailInput) (_ *UnsubscribeEmailOutput, err error) {
+	ctx, op := s.startOperation(ctx, "UnsubscribeEmail")
+	defer func() { op.end(err) }()
+
+	if err := validateUnsubscribeEmailInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "unsubscribe email validation failed", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.emails == nil {
+		return nil, ErrNotConfigured
+	}
+
+	userId, err := s.emails.Unsubscribe(ctx, in.Token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "unsubscribe email: token not found", nil)
+			return nil, err
+		}
+
+		s.log().ErrorCtx(ctx, "unsubscribe email repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "unsubscribe email completed", map[string]any{
+		"user_id": userId,
+	})
+
+	return &UnsubscribeEmailOutput{UserId: userId}, nil
+}
+
+// SendEmailDigest emails every subscribed active user the list of their
+// open review assignments. Users with an empty queue are skipped, a failed
+// email does not stop the rest of the digest.
+func (s *Service) SendEmailDigest(ctx context.Context) (_ *SendEmailDigestOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SendEmailDigest")
+	defer func() { op.end(err) }()
+
+	if s.emails == nil || s.mailer == nil {
+		return nil, ErrNotConfigured
+	}
+
+	s.log().InfoCtx(ctx, "send email digest started", nil)
+
+	subs, err := s.emails.ListDigestSubscriptions(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "send email digest: list subscriptions repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	out := &SendEmailDigestOutput{}
+	for _, sub := range subs {
+		prs, err := s.prs.GetAllPrByUserId(ctx, sub.UserId)
+		if err != nil {
+			s.log().ErrorCtx(ctx, "send email digest: get reviews repository error", map[string]any{
+				"user_id": sub.UserId,
+				"error":   err.Error(),
+			})
+			return nil, err
+		}
+
+		open := openPullRequests(prs)
+		if len(open) == 0 {
+			continue
+		}
+
+		err = s.mailer.SendDigest(ctx, domain.EmailDigest{
+			Recipient:    sub,
+			PullRequests: open,
+			GeneratedAt:  now,
+		})
+		if err != nil {
+			s.log().ErrorCtx(ctx, "send email digest: mailer error", map[string]any{
+				"user_id": sub.UserId,
+				"error":   err.Error(),
+			})
+			out.Failed++
+			continue
+		}
+		out.Sent++
+	}
+
+	s.log().InfoCtx(ctx, "send email digest completed", map[string]any{
+		"sent":   out.Sent,
+		"failed": out.Failed,
+	})
+
+	return out, nil
+}
+
+func newUnsubscribeToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
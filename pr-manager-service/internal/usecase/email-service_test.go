@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockEmailRepo struct {
+	subs []domain.EmailSubscription
+}
+
+func (m *mockEmailRepo) SetEmailSubscription(ctx context.Context, sub domain.EmailSubscription) (*domain.EmailSubscription, error) {
+	return &sub, nil
+}
+
+func (m *mockEmailRepo) ListDigestSubscriptions(ctx context.Context) ([]domain.EmailSubscription, error) {
+	return m.subs, nil
+}
+
+func (m *mockEmailRepo) Unsubscribe(ctx context.Context, token string) (string, error) {
+	panic("not used in this test")
+}
+
+type mockMailer struct {
+	failFor map[string]bool
+	sent    []domain.EmailDigest
+}
+
+func (m *mockMailer) SendDigest(ctx context.Context, digest domain.EmailDigest) error {
+	if m.failFor[digest.Recipient.UserId] {
+		return errors.New("smtp unavailable")
+	}
+	m.sent = append(m.sent, digest)
+	return nil
+}
+
+func TestSendEmailDigest_SkipsEmptyAndContinuesOnFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mailer := &mockMailer{failFor: map[string]bool{"u2": true}}
+	svc := &Service{
+		prs: &mockReviewsPRRepo{reviews: map[string][]domain.PullRequest{
+			"u2": {{PullRequestId: "pr-1", StatusId: 1}},
+			"u3": {{PullRequestId: "pr-2", StatusId: 1}, {PullRequestId: "pr-3", StatusId: 2}},
+			"u4": {{PullRequestId: "pr-4", StatusId: 2}},
+		}},
+		emails: &mockEmailRepo{subs: []domain.EmailSubscription{
+			{UserId: "u2", Email: "bob@example.com", DigestEnabled: true},
+			{UserId: "u3", Email: "charlie@example.com", DigestEnabled: true},
+			{UserId: "u4", Email: "dave@example.com", DigestEnabled: true},
+		}},
+		mailer:  mailer,
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	out, err := svc.SendEmailDigest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Sent != 1 || out.Failed != 1 {
+		t.Fatalf("expected 1 sent and 1 failed, got %+v", out)
+	}
+	digest := mailer.sent[0]
+	if digest.Recipient.UserId != "u3" || len(digest.PullRequests) != 1 || digest.PullRequests[0].PullRequestId != "pr-2" {
+		t.Fatalf("unexpected digest: %+v", digest)
+	}
+	if digest.GeneratedAt.IsZero() {
+		t.Fatalf("expected digest generation time to be set")
+	}
+}
+
+func TestSetEmailSubscription_Validation(t *testing.T) {
+	svc := &Service{
+		emails:  &mockEmailRepo{},
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	tests := []struct {
+		name string
+		in   SetEmailSubscriptionInput
+		want error
+	}{
+		{"no user", SetEmailSubscriptionInput{Email: "bob@example.com"}, ErrUserIdRequired},
+		{"no email", SetEmailSubscriptionInput{UserId: "u2"}, ErrEmailRequired},
+		{"invalid email", SetEmailSubscriptionInput{UserId: "u2", Email: "bob"}, ErrEmailInvalid},
+		{"display name", SetEmailSubscriptionInput{UserId: "u2", Email: "Bob <bob@example.com>"}, ErrEmailInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetEmailSubscription(context.Background(), tt.in); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSetEmailSubscription_GeneratesToken(t *testing.T) {
+	emails := &tokenCapturingEmailRepo{}
+	svc := &Service{
+		users:   &mockUserRepo{getUserResp: &domain.User{UserId: "u2"}},
+		emails:  emails,
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	_, err := svc.SetEmailSubscription(context.Background(), SetEmailSubscriptionInput{
+		UserId:        "u2",
+		Email:         "bob@example.com",
+		DigestEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(emails.token) != 32 {
+		t.Fatalf("expected 32 hex chars token, got %q", emails.token)
+	}
+}
+
+type tokenCapturingEmailRepo struct {
+	mockEmailRepo
+	token string
+}
+
+func (m *tokenCapturingEmailRepo) SetEmailSubscription(ctx context.Context, sub domain.EmailSubscription) (*domain.EmailSubscription, error) {
+	m.token = sub.UnsubscribeToken
+	return &sub, nil
+}
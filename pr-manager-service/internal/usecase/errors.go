@@ -14,4 +14,70 @@ var (
 	ErrReviewerNotAssigned      = errors.New("reviewer not assigned for this pr")
 	ErrNoCandidateInTeam        = errors.New("no review candidates in this team")
 	ErrNotFound                 = errors.New("resource not found")
+	ErrWebhookUrlRequired       = errors.New("url is required")
+	ErrWebhookUrlInvalid        = errors.New("url must be an absolute http or https url")
+	ErrWebhookSecretRequired    = errors.New("secret is required")
+	ErrWebhookIdRequired        = errors.New("id is required")
+	ErrUnknownEventType         = errors.New("unknown event type")
+	ErrUnknownProvider          = errors.New("unknown provider")
+	ErrLoginRequired            = errors.New("login is required")
+	ErrIdentityNotMapped        = errors.New("provider login is not mapped to a user")
+	ErrNotConfigured            = errors.New("feature is not configured")
+	ErrUnknownChatProvider      = errors.New("unknown chat provider")
+	ErrChatHandleRequired       = errors.New("handle is required")
+	ErrEmailRequired            = errors.New("email is required")
+	ErrEmailInvalid             = errors.New("email is invalid")
+	ErrUnsubscribeTokenRequired = errors.New("token is required")
+	ErrUserNameRequired         = errors.New("username is required")
+	ErrRosterDuplicateTeam      = errors.New("team is listed more than once")
+	ErrRosterUserConflict       = errors.New("user is listed with different username or is_active")
+	ErrSnapshotInvalid          = errors.New("snapshot is invalid")
+	ErrSnapshotFormatVersion    = errors.New("unsupported snapshot format version")
+	ErrSnapshotSchemaVersion    = errors.New("snapshot schema version is not compatible")
+	ErrDatabaseNotEmpty         = errors.New("database is not empty")
+	ErrStaleVersion             = errors.New("pull request was changed since the expected version")
+	ErrIdempotencyKeyInvalid    = errors.New("idempotency key must be 1 to 255 printable ascii characters")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyInProgress    = errors.New("request with this idempotency key is still in progress")
+	ErrRateLimitInvalid         = errors.New("rate limit must allow at least one request per positive period")
 )
+
+// Errors caused by invalid input
+var validationErrors = []error{
+	ErrTeamNameRequired,
+	ErrUserIdRequired,
+	ErrPullRequestIdRequired,
+	ErrPullRequestNameRequired,
+	ErrAuthorIdRequired,
+	ErrOldUserIdRequired,
+	ErrWebhookUrlRequired,
+	ErrWebhookUrlInvalid,
+	ErrWebhookSecretRequired,
+	ErrWebhookIdRequired,
+	ErrUnknownEventType,
+	ErrUnknownProvider,
+	ErrLoginRequired,
+	ErrUnknownChatProvider,
+	ErrChatHandleRequired,
+	ErrEmailRequired,
+	ErrEmailInvalid,
+	ErrUnsubscribeTokenRequired,
+	ErrUserNameRequired,
+	ErrRosterDuplicateTeam,
+	ErrRosterUserConflict,
+	ErrSnapshotInvalid,
+	ErrSnapshotFormatVersion,
+	ErrSnapshotSchemaVersion,
+	ErrIdempotencyKeyInvalid,
+	ErrRateLimitInvalid,
+}
+
+// IsValidationError reports whether err is caused by invalid input
+func IsValidationError(err error) bool {
+	for _, target := range validationErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"pr-manager-service/internal/domain"
 )
@@ -11,21 +12,150 @@ type TeamRepositoryInterface interface {
 	GetTeam(ctx context.Context, teamName string) (*domain.Team, []domain.User, error)
 }
 
+// RosterRepositoryInterface reads and bulk upserts all teams with their members
+type RosterRepositoryInterface interface {
+	ListTeamRosters(ctx context.Context) ([]domain.TeamRoster, error)
+	// UpsertTeamRosters creates missing teams, users and memberships and
+	// updates usernames and active flags in a single transaction
+	UpsertTeamRosters(ctx context.Context, rosters []domain.TeamRoster) error
+}
+
+// SnapshotWriterInterface receives a snapshot record by record, sections come
+// in the order of domain.Snapshot fields
+type SnapshotWriterInterface interface {
+	WriteHeader(header domain.SnapshotHeader) error
+	WriteTeam(team domain.Team) error
+	WriteUser(user domain.User) error
+	WriteMembership(m domain.Membership) error
+	WritePullRequest(pr domain.SnapshotPullRequest) error
+	WriteAssignment(a domain.ReviewerAssignment) error
+	Close() error
+}
+
+type SnapshotRepositoryInterface interface {
+	// SchemaVersion returns the applied migration version
+	SchemaVersion(ctx context.Context) (int64, error)
+	// ExportSnapshot streams a consistent view of the database to w
+	ExportSnapshot(ctx context.Context, w SnapshotWriterInterface) error
+	// RestoreSnapshot loads the snapshot in one transaction,
+	// ErrDatabaseNotEmpty if there are teams, users or pull requests
+	RestoreSnapshot(ctx context.Context, snapshot *domain.Snapshot) error
+}
+
 type UserRepositoryInterface interface {
 	GetUser(ctx context.Context, userId string) (*domain.User, error)
 	SetIsActive(ctx context.Context, userId string, isActive bool) (*domain.User, string, error)
 	GetTeamName(ctx context.Context, userId string) (string, error)
 }
 
+// PullRequestRepositoryInterface stores pull requests with a version bumped
+// on every change. Updates take the expected version, 0 skips the check
+// and a mismatch fails with ErrStaleVersion.
 type PullRequestRepositoryInterface interface {
+	// CreatePullRequest sets pr.Version of the created pull request
 	CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error
 	GetPullRequest(ctx context.Context, prId string) (*domain.PullRequest, error)
-	MergePullRequest(ctx context.Context, prId string) (*domain.PullRequest, error)
+	// MergePullRequest is idempotent, merging a merged pull request keeps its version.
+	// merged reports whether this call changed the status.
+	MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (pr *domain.PullRequest, merged bool, err error)
 	GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error)
-	ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string) error
+	ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error
 	GetActiveTeamMembers(ctx context.Context, teamName string) ([]domain.User, error)
 }
 
+type WorkloadRepositoryInterface interface {
+	// GetWorkload reads the current review load, MergeDurations and
+	// FirstVerdictDurations cover merges and verdicts within mergedWindow before now
+	GetWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error)
+}
+
+// ReviewVerdictRepositoryInterface keeps when assigned reviewers first
+// approved or requested changes
+type ReviewVerdictRepositoryInterface interface {
+	// RecordFirstVerdict reports whether userId is assigned to prId and had
+	// no verdict before, later verdicts keep the first one
+	RecordFirstVerdict(ctx context.Context, prId, userId string) (bool, error)
+}
+
+type WebhookRepositoryInterface interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	FanOutEvents(ctx context.Context, limit int) (int, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, deliveryId int64) error
+	RetryDelivery(ctx context.Context, deliveryId int64, attempts int, delay time.Duration, lastErr string) error
+	DeadLetterDelivery(ctx context.Context, deliveryId int64, attempts int, lastErr string) error
+	// DeleteProcessedEvents removes outbox events fanned out more than
+	// olderThan ago, events still referenced by deliveries or dead letters stay
+	DeleteProcessedEvents(ctx context.Context, olderThan time.Duration) (int64, error)
+	// DeleteUnprocessedEvents removes outbox events written more than olderThan
+	// ago and never fanned out
+	DeleteUnprocessedEvents(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type WebhookSenderInterface interface {
+	Send(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+type IdentityRepositoryInterface interface {
+	SetIdentity(ctx context.Context, identity domain.Identity) error
+	GetUserIdByLogin(ctx context.Context, provider, login string) (string, error)
+}
+
+type ChatHandleRepositoryInterface interface {
+	SetChatHandle(ctx context.Context, handle domain.ChatHandle) error
+	GetChatHandle(ctx context.Context, userId string) (*domain.ChatHandle, error)
+	ListChatHandles(ctx context.Context) ([]domain.ChatHandle, error)
+}
+
+// IdempotencyRepositoryInterface keeps responses of requests sent with
+// an Idempotency-Key until the key expires
+type IdempotencyRepositoryInterface interface {
+	// ReserveKey saves an in-progress record for lease unless the key has
+	// an unexpired record, which is returned instead. A record whose lease
+	// ran out is taken over.
+	ReserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error)
+	// SaveResponse stores the response of a reserved key and keeps it for ttl
+	SaveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error
+	// ReleaseKey deletes an in-progress record, so the request can be retried
+	ReleaseKey(ctx context.Context, key string) error
+	// DeleteExpiredKeys removes expired records and returns their number
+	DeleteExpiredKeys(ctx context.Context) (int64, error)
+}
+
+// RateLimitRepositoryInterface keeps a token bucket per rate limited key
+type RateLimitRepositoryInterface interface {
+	// TakeToken refills the bucket of the key and takes a token if there
+	// is one, atomically. A key not seen before starts with a full bucket.
+	TakeToken(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error)
+	// DeleteExpiredBuckets removes buckets that are full again and returns their number
+	DeleteExpiredBuckets(ctx context.Context) (int64, error)
+}
+
+// NotifierInterface sends direct chat messages, delivery may be asynchronous
+type NotifierInterface interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
+
+type EmailSubscriptionRepositoryInterface interface {
+	SetEmailSubscription(ctx context.Context, sub domain.EmailSubscription) (*domain.EmailSubscription, error)
+	ListDigestSubscriptions(ctx context.Context) ([]domain.EmailSubscription, error)
+	Unsubscribe(ctx context.Context, token string) (string, error)
+}
+
+// MailerInterface sends a review digest email synchronously
+type MailerInterface interface {
+	SendDigest(ctx context.Context, digest domain.EmailDigest) error
+}
+
+// EventBrokerInterface delivers live domain events to in-process subscribers.
+// Subscribers must call the returned func to unsubscribe.
+type EventBrokerInterface interface {
+	Publish(ctx context.Context, ev domain.Event) error
+	Subscribe() (<-chan domain.Event, func())
+}
+
 type LoggerInterface interface {
 	Debug(msg string, params map[string]any)
 	Info(msg string, params map[string]any)
@@ -33,6 +163,17 @@ type LoggerInterface interface {
 	Error(msg string, params map[string]any)
 }
 
+// ContextLoggerInterface is implemented by loggers that add request_id,
+// user_id and trace_id of the request in ctx to the line. Loggers without
+// it still work, their lines just have no request fields.
+type ContextLoggerInterface interface {
+	LoggerInterface
+	DebugCtx(ctx context.Context, msg string, params map[string]any)
+	InfoCtx(ctx context.Context, msg string, params map[string]any)
+	WarnCtx(ctx context.Context, msg string, params map[string]any)
+	ErrorCtx(ctx context.Context, msg string, params map[string]any)
+}
+
 type MetricsInterface interface {
 	IncTeamCreated()
 	IncUserActivated()
@@ -40,4 +181,8 @@ type MetricsInterface interface {
 	IncPullRequestCreated()
 	IncPullRequestMerged()
 	IncPullRequestReassigned()
+	// every Service method, result is OperationSuccess or OperationFailure
+	IncBusinessOperation(operation, result string)
+	// replaces the workload gauges and histograms with the snapshot
+	SetWorkload(w *domain.Workload)
 }
@@ -0,0 +1,127 @@
+package usecase
+
+import "context"
+
+// Idempotency keys
+
+// BeginIdempotentRequest reserves the key for a request. A repeated request
+// gets the saved response back, a request with another fingerprint fails with
+// ErrIdempotencyKeyReused and one sent while the first is running with
+// ErrIdempotencyInProgress.
+func (s *Service) BeginIdempotentRequest(ctx context.Context, in BeginIdempotentRequestInput) (_ *BeginIdempotentRequestOutput, err error) {
+	ctx, op := s.startOperation(ctx, "BeginIdempotentRequest")
+	defer func() { op.end(err) }()
+
+	if err := validateBeginIdempotentRequestInput(in); err != nil {
+		return nil, err
+	}
+
+	if s.idempotency == nil {
+		return nil, ErrNotConfigured
+	}
+
+	key := scopedIdempotencyKey(in.Scope, in.Key)
+	existing, err := s.idempotency.ReserveKey(ctx, key, in.Fingerprint, s.idempotencyLease)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "reserve idempotency key repository error", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	if existing == nil {
+		return &BeginIdempotentRequestOutput{}, nil
+	}
+
+	if existing.Fingerprint != in.Fingerprint {
+		s.log().WarnCtx(ctx, "idempotency key reused with a different request", map[string]any{
+			"key": key,
+		})
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	if existing.Response == nil {
+		return nil, ErrIdempotencyInProgress
+	}
+
+	s.log().InfoCtx(ctx, "replaying idempotent response", map[string]any{
+		"key":         key,
+		"status_code": existing.Response.StatusCode,
+	})
+
+	return &BeginIdempotentRequestOutput{
+		Replay: mapDomainStoredResponseToDTO(existing.Response),
+	}, nil
+}
+
+// CompleteIdempotentRequest saves the response of a request reserved
+// by BeginIdempotentRequest
+func (s *Service) CompleteIdempotentRequest(ctx context.Context, in CompleteIdempotentRequestInput) (err error) {
+	ctx, op := s.startOperation(ctx, "CompleteIdempotentRequest")
+	defer func() { op.end(err) }()
+
+	if s.idempotency == nil {
+		return ErrNotConfigured
+	}
+
+	key := scopedIdempotencyKey(in.Scope, in.Key)
+	err = s.idempotency.SaveResponse(ctx, key, mapStoredResponseDTOToDomain(in.Response), s.idempotencyTTL)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "save idempotent response repository error", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// AbortIdempotentRequest frees a reserved key without a response,
+// so the request can be retried with the same key
+func (s *Service) AbortIdempotentRequest(ctx context.Context, scope, idempotencyKey string) (err error) {
+	ctx, op := s.startOperation(ctx, "AbortIdempotentRequest")
+	defer func() { op.end(err) }()
+
+	if s.idempotency == nil {
+		return ErrNotConfigured
+	}
+
+	key := scopedIdempotencyKey(scope, idempotencyKey)
+	if err := s.idempotency.ReleaseKey(ctx, key); err != nil {
+		s.log().ErrorCtx(ctx, "release idempotency key repository error", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes keys older than the configured ttl
+func (s *Service) DeleteExpiredIdempotencyKeys(ctx context.Context) (_ int64, err error) {
+	ctx, op := s.startOperation(ctx, "DeleteExpiredIdempotencyKeys")
+	defer func() { op.end(err) }()
+
+	if s.idempotency == nil {
+		return 0, ErrNotConfigured
+	}
+
+	deleted, err := s.idempotency.DeleteExpiredKeys(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "delete expired idempotency keys repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return 0, err
+	}
+	if deleted > 0 {
+		s.log().InfoCtx(ctx, "expired idempotency keys deleted", map[string]any{
+			"deleted": deleted,
+		})
+	}
+	return deleted, nil
+}
+
+func scopedIdempotencyKey(scope, key string) string {
+	return scope + "/" + key
+}
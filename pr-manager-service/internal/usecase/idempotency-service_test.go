@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockIdempotencyRepo struct {
+	records map[string]*domain.IdempotencyRecord
+	lease   time.Duration
+	ttl     time.Duration
+}
+
+func newMockIdempotencyRepo() *mockIdempotencyRepo {
+	return &mockIdempotencyRepo{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (m *mockIdempotencyRepo) ReserveKey(ctx context.Context, key, fingerprint string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	m.lease = lease
+	if rec, ok := m.records[key]; ok {
+		return rec, nil
+	}
+	m.records[key] = &domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+	return nil, nil
+}
+
+func (m *mockIdempotencyRepo) SaveResponse(ctx context.Context, key string, resp domain.StoredResponse, ttl time.Duration) error {
+	m.ttl = ttl
+	m.records[key].Response = &resp
+	return nil
+}
+
+func (m *mockIdempotencyRepo) ReleaseKey(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func (m *mockIdempotencyRepo) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestBeginIdempotentRequest(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockIdempotencyRepo()
+	svc := NewService(nil, nil, nil, &noopLogger{}, &dummyMetrics{}, WithIdempotency(repo, time.Hour, time.Minute))
+
+	begin := func(scope, key, fingerprint string) (*BeginIdempotentRequestOutput, error) {
+		return svc.BeginIdempotentRequest(ctx, BeginIdempotentRequestInput{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint,
+		})
+	}
+
+	out, err := begin("admin:u1", "k1", "fp-1")
+	if err != nil || out.Replay != nil {
+		t.Fatalf("first request: expected a reservation, got %+v, %v", out, err)
+	}
+	if repo.lease != time.Minute {
+		t.Fatalf("expected the request to hold the key for the lease, got %s", repo.lease)
+	}
+
+	if _, err = begin("admin:u1", "k1", "fp-1"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("expected ErrIdempotencyInProgress, got %v", err)
+	}
+
+	err = svc.CompleteIdempotentRequest(ctx, CompleteIdempotentRequestInput{
+		Scope:    "admin:u1",
+		Key:      "k1",
+		Response: StoredResponseDTO{StatusCode: 201, Body: []byte("{}")},
+	})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if repo.ttl != time.Hour {
+		t.Fatalf("expected the response to be kept for the ttl, got %s", repo.ttl)
+	}
+
+	out, err = begin("admin:u1", "k1", "fp-1")
+	if err != nil || out.Replay == nil || out.Replay.StatusCode != 201 {
+		t.Fatalf("repeated request: expected the saved response, got %+v, %v", out, err)
+	}
+
+	if _, err = begin("admin:u1", "k1", "fp-2"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	// other callers have their own keys
+	out, err = begin("admin:u2", "k1", "fp-2")
+	if err != nil || out.Replay != nil {
+		t.Fatalf("another caller: expected a reservation, got %+v, %v", out, err)
+	}
+
+	// an aborted request can be retried with the same key
+	if err = svc.AbortIdempotentRequest(ctx, "admin:u2", "k1"); err != nil {
+		t.Fatalf("abort: %v", err)
+	}
+	out, err = begin("admin:u2", "k1", "fp-3")
+	if err != nil || out.Replay != nil {
+		t.Fatalf("after abort: expected a reservation, got %+v, %v", out, err)
+	}
+}
+
+func TestBeginIdempotentRequest_Validation(t *testing.T) {
+	svc := NewService(nil, nil, nil, &noopLogger{}, &dummyMetrics{})
+
+	for _, key := range []string{"", strings.Repeat("k", 256), "key\n"} {
+		_, err := svc.BeginIdempotentRequest(context.Background(), BeginIdempotentRequestInput{Key: key})
+		if !errors.Is(err, ErrIdempotencyKeyInvalid) || !IsValidationError(err) {
+			t.Fatalf("key %q: expected ErrIdempotencyKeyInvalid, got %v", key, err)
+		}
+	}
+
+	_, err := svc.BeginIdempotentRequest(context.Background(), BeginIdempotentRequestInput{Key: "k1"})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured without a repository, got %v", err)
+	}
+}
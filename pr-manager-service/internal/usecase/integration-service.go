@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Integrations
+
+func (s *Service) SetIdentity(ctx context.Context, in SetIdentityInput) (_ *SetIdentityOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SetIdentity")
+	defer func() { op.end(err) }()
+
+	if err := validateSetIdentityInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "set identity validation failed", map[string]any{
+			"provider": in.Provider,
+			"login":    in.Login,
+			"user_id":  in.UserId,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.identities == nil {
+		return nil, ErrNotConfigured
+	}
+
+	// Check if the user exists
+	_, err = s.users.GetUser(ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "set identity: user not found", map[string]any{
+				"user_id": in.UserId,
+				"error":   err.Error(),
+			})
+			return nil, err
+		}
+
+		s.log().ErrorCtx(ctx, "set identity: get user repository error", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	identity := domain.Identity{
+		Provider: in.Provider,
+		Login:    in.Login,
+		UserId:   in.UserId,
+	}
+
+	err = s.identities.SetIdentity(ctx, identity)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "set identity repository error", map[string]any{
+			"provider": in.Provider,
+			"login":    in.Login,
+			"user_id":  in.UserId,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "set identity completed", map[string]any{
+		"provider": in.Provider,
+		"login":    in.Login,
+		"user_id":  in.UserId,
+	})
+
+	return &SetIdentityOutput{
+		Provider: identity.Provider,
+		Login:    identity.Login,
+		UserId:   identity.UserId,
+	}, nil
+}
+
+// HandleProviderPullRequestEvent maps a GitHub/GitLab pull request event
+// onto CreatePullRequest and MergePullRequest, reviews record the first
+// verdict of an assigned reviewer
+func (s *Service) HandleProviderPullRequestEvent(ctx context.Context, in ProviderPullRequestEventInput) (_ *ProviderPullRequestEventOutput, err error) {
+	ctx, op := s.startOperation(ctx, "HandleProviderPullRequestEvent")
+	defer func() { op.end(err) }()
+
+	if err := validateProviderPullRequestEventInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "provider event validation failed", map[string]any{
+			"provider":        in.Provider,
+			"action":          in.Action,
+			"pull_request_id": in.PullRequestId,
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "provider event received", map[string]any{
+		"provider":        in.Provider,
+		"action":          in.Action,
+		"pull_request_id": in.PullRequestId,
+	})
+
+	switch in.Action {
+	case ProviderActionOpened, ProviderActionReopened:
+		return s.handleProviderOpened(ctx, in)
+	case ProviderActionMerged:
+		return s.handleProviderMerged(ctx, in)
+	case ProviderActionReviewed:
+		return s.handleProviderReviewed(ctx, in)
+	default:
+		// closed without merge and other actions have no counterpart in the service
+		return &ProviderPullRequestEventOutput{Result: ProviderEventIgnored}, nil
+	}
+}
+
+func (s *Service) handleProviderOpened(ctx context.Context, in ProviderPullRequestEventInput) (*ProviderPullRequestEventOutput, error) {
+	// Reopened PRs and redelivered events must not fail on an existing PR
+	existing, err := s.prs.GetPullRequest(ctx, in.PullRequestId)
+	if err == nil {
+		dto := mapDomainPRToDTO(existing)
+		return &ProviderPullRequestEventOutput{Result: ProviderEventIgnored, PR: &dto}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		s.log().ErrorCtx(ctx, "provider event: get pr repository error", map[string]any{
+			"provider":        in.Provider,
+			"pull_request_id": in.PullRequestId,
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.identities == nil {
+		return nil, ErrNotConfigured
+	}
+
+	authorId, err := s.identities.GetUserIdByLogin(ctx, in.Provider, in.AuthorLogin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "provider event: author login is not mapped", map[string]any{
+				"provider":        in.Provider,
+				"login":           in.AuthorLogin,
+				"pull_request_id": in.PullRequestId,
+			})
+			return nil, ErrIdentityNotMapped
+		}
+
+		s.log().ErrorCtx(ctx, "provider event: get identity repository error", map[string]any{
+			"provider": in.Provider,
+			"login":    in.AuthorLogin,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	out, err := s.CreatePullRequest(ctx, CreatePullRequestInput{
+		PullRequestId:   in.PullRequestId,
+		PullRequestName: in.PullRequestName,
+		AuthorId:        authorId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProviderPullRequestEventOutput{Result: ProviderEventCreated, PR: &out.PR}, nil
+}
+
+func (s *Service) handleProviderMerged(ctx context.Context, in ProviderPullRequestEventInput) (*ProviderPullRequestEventOutput, error) {
+	out, err := s.MergePullRequest(ctx, MergePullRequestInput{PullRequestId: in.PullRequestId})
+	if err != nil {
+		// PRs opened before the integration was set up are unknown to the service
+		if errors.Is(err, sql.ErrNoRows) {
+			return &ProviderPullRequestEventOutput{Result: ProviderEventIgnored}, nil
+		}
+		return nil, err
+	}
+
+	return &ProviderPullRequestEventOutput{Result: ProviderEventMerged, PR: &out.PR}, nil
+}
+
+func (s *Service) handleProviderReviewed(ctx context.Context, in ProviderPullRequestEventInput) (*ProviderPullRequestEventOutput, error) {
+	if s.identities == nil || s.verdicts == nil {
+		return nil, ErrNotConfigured
+	}
+
+	reviewerId, err := s.identities.GetUserIdByLogin(ctx, in.Provider, in.ReviewerLogin)
+	if err != nil {
+		// Anyone may review on the provider, an unmapped login is no assigned reviewer
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "provider event: reviewer login is not mapped", map[string]any{
+				"provider":        in.Provider,
+				"login":           in.ReviewerLogin,
+				"pull_request_id": in.PullRequestId,
+			})
+			return &ProviderPullRequestEventOutput{Result: ProviderEventIgnored}, nil
+		}
+
+		s.log().ErrorCtx(ctx, "provider event: get identity repository error", map[string]any{
+			"provider": in.Provider,
+			"login":    in.ReviewerLogin,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	recorded, err := s.verdicts.RecordFirstVerdict(ctx, in.PullRequestId, reviewerId)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "provider event: record verdict repository error", map[string]any{
+			"pull_request_id": in.PullRequestId,
+			"reviewer_id":     reviewerId,
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+	// Unknown pull requests, other reviewers and repeated verdicts change nothing
+	if !recorded {
+		return &ProviderPullRequestEventOutput{Result: ProviderEventIgnored}, nil
+	}
+
+	s.log().InfoCtx(ctx, "provider event: first verdict recorded", map[string]any{
+		"pull_request_id": in.PullRequestId,
+		"reviewer_id":     reviewerId,
+	})
+
+	return &ProviderPullRequestEventOutput{Result: ProviderEventReviewed}, nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockIdentityRepo struct {
+	userId string
+	err    error
+}
+
+func (m *mockIdentityRepo) SetIdentity(ctx context.Context, identity domain.Identity) error {
+	panic("not used in this test")
+}
+
+func (m *mockIdentityRepo) GetUserIdByLogin(ctx context.Context, provider, login string) (string, error) {
+	return m.userId, m.err
+}
+
+type mockVerdictRepo struct {
+	recorded bool
+	userId   string
+}
+
+func (m *mockVerdictRepo) RecordFirstVerdict(ctx context.Context, prId, userId string) (bool, error) {
+	m.userId = userId
+	return m.recorded, nil
+}
+
+func TestHandleProviderPullRequestEvent_TableDriven(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		input      ProviderPullRequestEventInput
+		identity   *mockIdentityRepo
+		verdicts   *mockVerdictRepo
+		wantResult string
+		wantErr    error
+	}{
+		{
+			name: "opened creates pr",
+			input: ProviderPullRequestEventInput{
+				Provider:        domain.ProviderGitHub,
+				Action:          ProviderActionOpened,
+				PullRequestId:   "github:acme/payments#1",
+				PullRequestName: "Add search",
+				AuthorLogin:     "alice",
+			},
+			identity:   &mockIdentityRepo{userId: "u1"},
+			wantResult: ProviderEventCreated,
+		},
+		{
+			name: "closed is ignored",
+			input: ProviderPullRequestEventInput{
+				Provider:      domain.ProviderGitHub,
+				Action:        ProviderActionClosed,
+				PullRequestId: "github:acme/payments#1",
+			},
+			identity:   &mockIdentityRepo{},
+			wantResult: ProviderEventIgnored,
+		},
+		{
+			name: "unmapped author",
+			input: ProviderPullRequestEventInput{
+				Provider:        domain.ProviderGitLab,
+				Action:          ProviderActionOpened,
+				PullRequestId:   "gitlab:acme/payments!1",
+				PullRequestName: "Add search",
+				AuthorLogin:     "mallory",
+			},
+			identity: &mockIdentityRepo{err: sql.ErrNoRows},
+			wantErr:  ErrIdentityNotMapped,
+		},
+		{
+			name: "unknown provider",
+			input: ProviderPullRequestEventInput{
+				Provider:      "bitbucket",
+				Action:        ProviderActionOpened,
+				PullRequestId: "bitbucket:acme/payments#1",
+			},
+			identity: &mockIdentityRepo{},
+			wantErr:  ErrUnknownProvider,
+		},
+		{
+			name: "opened without identities repository",
+			input: ProviderPullRequestEventInput{
+				Provider:        domain.ProviderGitHub,
+				Action:          ProviderActionOpened,
+				PullRequestId:   "github:acme/payments#1",
+				PullRequestName: "Add search",
+				AuthorLogin:     "alice",
+			},
+			wantErr: ErrNotConfigured,
+		},
+		{
+			name: "review records first verdict",
+			input: ProviderPullRequestEventInput{
+				Provider:      domain.ProviderGitHub,
+				Action:        ProviderActionReviewed,
+				PullRequestId: "github:acme/payments#1",
+				ReviewerLogin: "bob",
+			},
+			identity:   &mockIdentityRepo{userId: "u2"},
+			verdicts:   &mockVerdictRepo{recorded: true},
+			wantResult: ProviderEventReviewed,
+		},
+		{
+			name: "repeated review is ignored",
+			input: ProviderPullRequestEventInput{
+				Provider:      domain.ProviderGitHub,
+				Action:        ProviderActionReviewed,
+				PullRequestId: "github:acme/payments#1",
+				ReviewerLogin: "bob",
+			},
+			identity:   &mockIdentityRepo{userId: "u2"},
+			verdicts:   &mockVerdictRepo{},
+			wantResult: ProviderEventIgnored,
+		},
+		{
+			name: "review of unmapped reviewer is ignored",
+			input: ProviderPullRequestEventInput{
+				Provider:      domain.ProviderGitLab,
+				Action:        ProviderActionReviewed,
+				PullRequestId: "gitlab:acme/payments!1",
+				ReviewerLogin: "mallory",
+			},
+			identity:   &mockIdentityRepo{err: sql.ErrNoRows},
+			verdicts:   &mockVerdictRepo{recorded: true},
+			wantResult: ProviderEventIgnored,
+		},
+		{
+			name: "review without verdicts repository",
+			input: ProviderPullRequestEventInput{
+				Provider:      domain.ProviderGitHub,
+				Action:        ProviderActionReviewed,
+				PullRequestId: "github:acme/payments#1",
+				ReviewerLogin: "bob",
+			},
+			identity: &mockIdentityRepo{userId: "u2"},
+			wantErr:  ErrNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prRepo := &mockPRRepo{getPRErr: sql.ErrNoRows}
+			svc := &Service{
+				users: &mockUserRepo{
+					getUserResp:     &domain.User{UserId: "u1", UserName: "Alice", IsActive: true},
+					getTeamNameResp: "payments",
+				},
+				prs:     prRepo,
+				logger:  &noopLogger{},
+				metrics: &dummyMetrics{},
+			}
+			if tt.identity != nil {
+				svc.identities = tt.identity
+			}
+			if tt.verdicts != nil {
+				svc.verdicts = tt.verdicts
+			}
+
+			out, err := svc.HandleProviderPullRequestEvent(ctx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if out.Result != tt.wantResult {
+				t.Fatalf("expected result %q, got %q", tt.wantResult, out.Result)
+			}
+			if tt.wantResult == ProviderEventCreated && !prRepo.createCalled {
+				t.Fatalf("expected CreatePullRequest to be called on repository")
+			}
+			if tt.wantResult == ProviderEventReviewed && tt.verdicts.userId != "u2" {
+				t.Fatalf("expected verdict of u2, got %q", tt.verdicts.userId)
+			}
+		})
+	}
+}
+
+func TestSetIdentity_NotConfigured(t *testing.T) {
+	svc := NewService(nil, &mockUserRepo{}, nil, &noopLogger{}, &dummyMetrics{})
+
+	_, err := svc.SetIdentity(context.Background(), SetIdentityInput{
+		Provider: domain.ProviderGitHub,
+		Login:    "alice",
+		UserId:   "u1",
+	})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
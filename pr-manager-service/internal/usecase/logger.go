@@ -0,0 +1,40 @@
+package usecase
+
+import "context"
+
+// withContext returns l itself if it logs with the request context,
+// otherwise l with the Ctx methods dropping ctx
+func withContext(l LoggerInterface) ContextLoggerInterface {
+	if cl, ok := l.(ContextLoggerInterface); ok {
+		return cl
+	}
+	return contextFreeLogger{l}
+}
+
+type contextFreeLogger struct {
+	LoggerInterface
+}
+
+func (l contextFreeLogger) DebugCtx(_ context.Context, msg string, params map[string]any) {
+	l.Debug(msg, params)
+}
+
+func (l contextFreeLogger) InfoCtx(_ context.Context, msg string, params map[string]any) {
+	l.Info(msg, params)
+}
+
+func (l contextFreeLogger) WarnCtx(_ context.Context, msg string, params map[string]any) {
+	l.Warn(msg, params)
+}
+
+func (l contextFreeLogger) ErrorCtx(_ context.Context, msg string, params map[string]any) {
+	l.Error(msg, params)
+}
+
+func (s *Service) log() ContextLoggerInterface {
+	return withContext(s.logger)
+}
+
+func (w *WebhookWorker) log() ContextLoggerInterface {
+	return withContext(w.logger)
+}
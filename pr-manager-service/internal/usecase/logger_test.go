@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+)
+
+type recordingLogger struct {
+	noopLogger
+	infos []string
+}
+
+func (l *recordingLogger) Info(msg string, _ map[string]any) {
+	l.infos = append(l.infos, msg)
+}
+
+func TestWithContext_ContextFreeLogger(t *testing.T) {
+	l := &recordingLogger{}
+	svc := NewService(nil, nil, nil, l, &dummyMetrics{})
+
+	svc.log().InfoCtx(context.Background(), "pr created", nil)
+
+	if len(l.infos) != 1 || l.infos[0] != "pr created" {
+		t.Fatalf("expected the line to reach Info, got %v", l.infos)
+	}
+}
@@ -28,6 +28,28 @@ func mapDomainUsersToTeamMembersDTO(users []domain.User) []TeamMemberDTO {
 	return result
 }
 
+func mapRosterTeamsDTOToDomain(teams []RosterTeamDTO) []domain.TeamRoster {
+	result := make([]domain.TeamRoster, 0, len(teams))
+	for _, t := range teams {
+		result = append(result, domain.TeamRoster{
+			TeamName: t.TeamName,
+			Members:  mapTeamMembersDTOToDomain(t.Members),
+		})
+	}
+	return result
+}
+
+func mapDomainTeamRostersToDTO(rosters []domain.TeamRoster) []RosterTeamDTO {
+	result := make([]RosterTeamDTO, 0, len(rosters))
+	for _, r := range rosters {
+		result = append(result, RosterTeamDTO{
+			TeamName: r.TeamName,
+			Members:  mapDomainUsersToTeamMembersDTO(r.Members),
+		})
+	}
+	return result
+}
+
 // Users
 
 func mapDomainUserToSetIsActiveOutput(user *domain.User, teamName string) *SetIsActiveOutput {
@@ -55,6 +77,16 @@ func mapDomainPRsToGetUserReviewsOutput(userId string, prs []domain.PullRequest)
 	}
 }
 
+func mapDomainEventToReviewStreamDTO(ev domain.Event, kind string) ReviewStreamEventDTO {
+	return ReviewStreamEventDTO{
+		Kind:            kind,
+		PullRequestId:   ev.PullRequestId,
+		PullRequestName: ev.PullRequestName,
+		AuthorId:        ev.AuthorId,
+		OccurredAt:      ev.OccurredAt,
+	}
+}
+
 // Pull requests
 
 func mapCreatePRInputToDomain(in CreatePullRequestInput, assigned []string) *domain.PullRequest {
@@ -74,6 +106,31 @@ func mapDomainPRToDTO(pr *domain.PullRequest) PullRequestDTO {
 		AuthorId:          pr.AuthorId,
 		Status:            statusString(pr.StatusId),
 		AssignedReviewers: pr.AssignedReviewers,
+		Version:           pr.Version,
+	}
+}
+
+// Webhooks
+
+func mapCreateWebhookSubscriptionInputToDomain(in CreateWebhookSubscriptionInput) *domain.WebhookSubscription {
+	eventTypes := in.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &domain.WebhookSubscription{
+		Url:        in.Url,
+		Secret:     in.Secret,
+		EventTypes: eventTypes,
+		IsActive:   true,
+	}
+}
+
+func mapDomainWebhookSubscriptionToDTO(sub *domain.WebhookSubscription) WebhookSubscriptionDTO {
+	return WebhookSubscriptionDTO{
+		Id:         sub.Id,
+		Url:        sub.Url,
+		EventTypes: sub.EventTypes,
+		IsActive:   sub.IsActive,
 	}
 }
 
@@ -89,3 +146,34 @@ func statusString(statusId int) string {
 		return "OPEN"
 	}
 }
+
+func mapStoredResponseDTOToDomain(resp StoredResponseDTO) domain.StoredResponse {
+	return domain.StoredResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Headers,
+		Body:       resp.Body,
+	}
+}
+
+func mapDomainStoredResponseToDTO(resp *domain.StoredResponse) *StoredResponseDTO {
+	return &StoredResponseDTO{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Headers,
+		Body:       resp.Body,
+	}
+}
+
+func mapRateLimitDTOToDomain(limit RateLimitDTO) domain.RateLimit {
+	return domain.RateLimit{
+		Burst:  limit.Burst,
+		Period: limit.Period,
+	}
+}
+
+func mapDomainRateLimitDecisionToDTO(d domain.RateLimitDecision) *RateLimitDecisionDTO {
+	return &RateLimitDecisionDTO{
+		Allowed:    d.Allowed,
+		Remaining:  d.Remaining,
+		RetryAfter: d.RetryAfter,
+	}
+}
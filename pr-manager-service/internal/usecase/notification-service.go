@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Chat notifications
+
+func (s *Service) SetChatHandle(ctx context.Context, in SetChatHandleInput) (_ *SetChatHandleOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SetChatHandle")
+	defer func() { op.end(err) }()
+
+	if err := validateSetChatHandleInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "set chat handle validation failed", map[string]any{
+			"user_id":  in.UserId,
+			"provider": in.Provider,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.chats == nil {
+		return nil, ErrNotConfigured
+	}
+
+	// Check if the user exists
+	_, err = s.users.GetUser(ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "set chat handle: user not found", map[string]any{
+				"user_id": in.UserId,
+				"error":   err.Error(),
+			})
+			return nil, err
+		}
+
+		s.log().ErrorCtx(ctx, "set chat handle: get user repository error", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	handle := domain.ChatHandle{
+		UserId:   in.UserId,
+		Provider: in.Provider,
+		Handle:   in.Handle,
+	}
+
+	err = s.chats.SetChatHandle(ctx, handle)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "set chat handle repository error", map[string]any{
+			"user_id":  in.UserId,
+			"provider": in.Provider,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "set chat handle completed", map[string]any{
+		"user_id":  in.UserId,
+		"provider": in.Provider,
+	})
+
+	return &SetChatHandleOutput{
+		UserId:   handle.UserId,
+		Provider: handle.Provider,
+		Handle:   handle.Handle,
+	}, nil
+}
+
+// SendChatDigest sends every active user with a chat handle the list of
+// their open review assignments. Users with an empty queue are skipped.
+func (s *Service) SendChatDigest(ctx context.Context) (_ *SendChatDigestOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SendChatDigest")
+	defer func() { op.end(err) }()
+
+	if s.notifier == nil || s.chats == nil {
+		return nil, ErrNotConfigured
+	}
+
+	s.log().InfoCtx(ctx, "send chat digest started", nil)
+
+	handles, err := s.chats.ListChatHandles(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "send chat digest: list chat handles repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := &SendChatDigestOutput{}
+	for _, h := range handles {
+		prs, err := s.prs.GetAllPrByUserId(ctx, h.UserId)
+		if err != nil {
+			s.log().ErrorCtx(ctx, "send chat digest: get reviews repository error", map[string]any{
+				"user_id": h.UserId,
+				"error":   err.Error(),
+			})
+			return nil, err
+		}
+
+		open := openPullRequests(prs)
+		if len(open) == 0 {
+			continue
+		}
+
+		err = s.notifier.Notify(ctx, domain.Notification{
+			Kind:         domain.NotificationReviewDigest,
+			Recipient:    h,
+			PullRequests: open,
+		})
+		if err != nil {
+			s.log().ErrorCtx(ctx, "send chat digest: notify error", map[string]any{
+				"user_id": h.UserId,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		out.Notified++
+	}
+
+	s.log().InfoCtx(ctx, "send chat digest completed", map[string]any{
+		"notified": out.Notified,
+	})
+
+	return out, nil
+}
+
+// Sends a direct message to a newly assigned reviewer if they have a chat handle.
+// Failures are logged only: the assignment itself is already committed.
+func (s *Service) notifyReviewerAssigned(ctx context.Context, reviewerId string, pr *domain.PullRequest) {
+	if s.notifier == nil || s.chats == nil {
+		return
+	}
+
+	handle, err := s.chats.GetChatHandle(ctx, reviewerId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log().ErrorCtx(ctx, "notify reviewer: get chat handle repository error", map[string]any{
+				"user_id": reviewerId,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	err = s.notifier.Notify(ctx, domain.Notification{
+		Kind:         domain.NotificationReviewerAssigned,
+		Recipient:    *handle,
+		PullRequests: []domain.PullRequest{*pr},
+	})
+	if err != nil {
+		s.log().ErrorCtx(ctx, "notify reviewer error", map[string]any{
+			"user_id":         reviewerId,
+			"pull_request_id": pr.PullRequestId,
+			"error":           err.Error(),
+		})
+	}
+}
+
+func openPullRequests(prs []domain.PullRequest) []domain.PullRequest {
+	result := make([]domain.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if statusString(pr.StatusId) == "OPEN" {
+			result = append(result, pr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockChatHandleRepo struct {
+	handles map[string]domain.ChatHandle
+}
+
+func (m *mockChatHandleRepo) SetChatHandle(ctx context.Context, handle domain.ChatHandle) error {
+	m.handles[handle.UserId] = handle
+	return nil
+}
+
+func (m *mockChatHandleRepo) GetChatHandle(ctx context.Context, userId string) (*domain.ChatHandle, error) {
+	h, ok := m.handles[userId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &h, nil
+}
+
+func (m *mockChatHandleRepo) ListChatHandles(ctx context.Context) ([]domain.ChatHandle, error) {
+	result := make([]domain.ChatHandle, 0, len(m.handles))
+	for _, h := range m.handles {
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+type mockNotifier struct {
+	sent []domain.Notification
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, n domain.Notification) error {
+	m.sent = append(m.sent, n)
+	return nil
+}
+
+type mockReviewsPRRepo struct {
+	mockPRRepo
+	reviews map[string][]domain.PullRequest
+}
+
+func (m *mockReviewsPRRepo) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
+	return m.reviews[userId], nil
+}
+
+func TestCreatePullRequest_NotifiesAssignedReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	notifier := &mockNotifier{}
+	svc := &Service{
+		users: &mockUserRepo{
+			getUserResp:     &domain.User{UserId: "u1", UserName: "Alice", IsActive: true},
+			getTeamNameResp: "payments",
+		},
+		prs:      &mockPRRepo{},
+		notifier: notifier,
+		chats: &mockChatHandleRepo{handles: map[string]domain.ChatHandle{
+			"u2": {UserId: "u2", Provider: domain.ChatProviderSlack, Handle: "bob"},
+		}},
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	out, err := svc.CreatePullRequest(ctx, CreatePullRequestInput{
+		PullRequestId:   "pr-1001",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// only u2 has a chat handle, u3 is silently skipped
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected 1 notification, got %d (reviewers %v)", len(notifier.sent), out.PR.AssignedReviewers)
+	}
+	n := notifier.sent[0]
+	if n.Kind != domain.NotificationReviewerAssigned || n.Recipient.Handle != "bob" {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+	if len(n.PullRequests) != 1 || n.PullRequests[0].PullRequestId != "pr-1001" {
+		t.Fatalf("unexpected pull requests in notification: %+v", n.PullRequests)
+	}
+}
+
+func TestSendChatDigest_OnlyOpenAndNonEmptyQueues(t *testing.T) {
+	ctx := context.Background()
+
+	notifier := &mockNotifier{}
+	svc := &Service{
+		prs: &mockReviewsPRRepo{reviews: map[string][]domain.PullRequest{
+			"u2": {
+				{PullRequestId: "pr-1", StatusId: 1},
+				{PullRequestId: "pr-2", StatusId: 2},
+			},
+			"u3": {
+				{PullRequestId: "pr-3", StatusId: 2},
+			},
+		}},
+		notifier: notifier,
+		chats: &mockChatHandleRepo{handles: map[string]domain.ChatHandle{
+			"u2": {UserId: "u2", Provider: domain.ChatProviderSlack, Handle: "bob"},
+			"u3": {UserId: "u3", Provider: domain.ChatProviderMattermost, Handle: "charlie"},
+		}},
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	out, err := svc.SendChatDigest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Notified != 1 || len(notifier.sent) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(notifier.sent))
+	}
+	n := notifier.sent[0]
+	if n.Recipient.UserId != "u2" || n.Kind != domain.NotificationReviewDigest {
+		t.Fatalf("unexpected digest: %+v", n)
+	}
+	if len(n.PullRequests) != 1 || n.PullRequests[0].PullRequestId != "pr-1" {
+		t.Fatalf("expected only open pull requests, got %+v", n.PullRequests)
+	}
+}
+
+func TestSendChatDigest_NotConfigured(t *testing.T) {
+	svc := &Service{logger: &noopLogger{}, metrics: &dummyMetrics{}}
+
+	if _, err := svc.SendChatDigest(context.Background()); err != ErrNotConfigured {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Results of business operations
+const (
+	OperationSuccess = "success"
+	OperationFailure = "failure"
+)
+
+// tracer uses the global provider, spans are no-ops until one is installed
+var tracer = otel.Tracer("pr-manager-service/internal/usecase")
+
+// operation is one call of a Service method, traced and counted.
+// Background operations have no metrics and are only traced.
+type operation struct {
+	name    string
+	span    trace.Span
+	metrics MetricsInterface
+}
+
+// Starts the span of a Service method, end it with operation.end
+func (s *Service) startOperation(ctx context.Context, method string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, "usecase."+method)
+	return ctx, &operation{
+		name:    operationName(method),
+		span:    span,
+		metrics: s.metrics,
+	}
+}
+
+// Starts the span of a periodic Service method, which is not a business
+// operation and is left out of business_operations_total
+func (s *Service) startBackgroundOperation(ctx context.Context, method string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, "usecase."+method)
+	return ctx, &operation{
+		name: operationName(method),
+		span: span,
+	}
+}
+
+// Ends the span and counts the operation, both marked failed when err is not nil
+func (o *operation) end(err error) {
+	result := OperationSuccess
+	if err != nil {
+		result = OperationFailure
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+	if o.metrics != nil {
+		o.metrics.IncBusinessOperation(o.name, result)
+	}
+}
+
+// operationName turns a method name into the metric label: CreatePullRequest
+// becomes create_pull_request
+func operationName(method string) string {
+	var b strings.Builder
+	for i, r := range method {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import "testing"
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		"CreatePullRequest": "create_pull_request",
+		"GetTeam":           "get_team",
+		"SetIsActive":       "set_is_active",
+	}
+
+	for method, want := range tests {
+		if got := operationName(method); got != want {
+			t.Fatalf("operationName(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
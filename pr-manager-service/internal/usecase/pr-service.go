@@ -11,9 +11,12 @@ import (
 
 // Pull requests
 
-func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInput) (*CreatePullRequestOutput, error) {
+func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInput) (_ *CreatePullRequestOutput, err error) {
+	ctx, op := s.startOperation(ctx, "CreatePullRequest")
+	defer func() { op.end(err) }()
+
 	if err := validateCreatePullRequestInput(in); err != nil {
-		s.logger.Error("create pull request validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "create pull request validation failed", map[string]any{
 			"pull_request_id":   in.PullRequestId,
 			"pull_request_name": in.PullRequestName,
 			"author_id":         in.AuthorId,
@@ -22,7 +25,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 		return nil, err
 	}
 
-	s.logger.Info("create pull request started", map[string]any{
+	s.log().InfoCtx(ctx, "create pull request started", map[string]any{
 		"pull_request_id":   in.PullRequestId,
 		"pull_request_name": in.PullRequestName,
 		"author_id":         in.AuthorId,
@@ -32,7 +35,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 	author, err := s.users.GetUser(ctx, in.AuthorId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("create pull request: author not found", map[string]any{
+			s.log().WarnCtx(ctx, "create pull request: author not found", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"author_id":       in.AuthorId,
 				"error":           err.Error(),
@@ -40,7 +43,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 			return nil, err
 		}
 
-		s.logger.Error("create pull request: get author repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "create pull request: get author repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"author_id":       in.AuthorId,
 			"error":           err.Error(),
@@ -52,7 +55,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 	teamName, err := s.users.GetTeamName(ctx, author.UserId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("create pull request: author has no team", map[string]any{
+			s.log().WarnCtx(ctx, "create pull request: author has no team", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"author_id":       in.AuthorId,
 				"error":           err.Error(),
@@ -60,7 +63,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 			return nil, err
 		}
 
-		s.logger.Error("create pull request: get author team repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "create pull request: get author team repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"author_id":       in.AuthorId,
 			"error":           err.Error(),
@@ -71,7 +74,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 	// Get active members from this team
 	candidates, err := s.prs.GetActiveTeamMembers(ctx, teamName)
 	if err != nil {
-		s.logger.Error("create pull request: get active team members error", map[string]any{
+		s.log().ErrorCtx(ctx, "create pull request: get active team members error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"author_id":       in.AuthorId,
 			"team_name":       teamName,
@@ -99,7 +102,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 
 	err = s.prs.CreatePullRequest(ctx, pr)
 	if err != nil {
-		s.logger.Error("create pull request repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "create pull request repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"author_id":       in.AuthorId,
 			"error":           err.Error(),
@@ -111,7 +114,7 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 		PR: mapDomainPRToDTO(pr),
 	}
 
-	s.logger.Info("create pull request completed", map[string]any{
+	s.log().InfoCtx(ctx, "create pull request completed", map[string]any{
 		"pull_request_id":    out.PR.PullRequestId,
 		"author_id":          out.PR.AuthorId,
 		"assigned_reviewers": out.PR.AssignedReviewers,
@@ -119,33 +122,55 @@ func (s *Service) CreatePullRequest(ctx context.Context, in CreatePullRequestInp
 
 	s.metrics.IncPullRequestCreated()
 
+	for _, reviewerId := range pr.AssignedReviewers {
+		s.publishEvent(ctx, domain.Event{
+			EventType:       domain.EventReviewerAssigned,
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			ReviewerId:      reviewerId,
+			Reviewers:       pr.AssignedReviewers,
+		})
+		s.notifyReviewerAssigned(ctx, reviewerId, pr)
+	}
+
 	return out, nil
 }
 
-func (s *Service) MergePullRequest(ctx context.Context, in MergePullRequestInput) (*MergePullRequestOutput, error) {
+func (s *Service) MergePullRequest(ctx context.Context, in MergePullRequestInput) (_ *MergePullRequestOutput, err error) {
+	ctx, op := s.startOperation(ctx, "MergePullRequest")
+	defer func() { op.end(err) }()
+
 	if err := validateMergePullRequestInput(in); err != nil {
-		s.logger.Error("merge pull request validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "merge pull request validation failed", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"error":           err.Error(),
 		})
 		return nil, err
 	}
 
-	s.logger.Info("merge pull request started", map[string]any{
+	s.log().InfoCtx(ctx, "merge pull request started", map[string]any{
 		"pull_request_id": in.PullRequestId,
 	})
 
-	pr, err := s.prs.MergePullRequest(ctx, in.PullRequestId)
+	pr, merged, err := s.prs.MergePullRequest(ctx, in.PullRequestId, in.ExpectedVersion)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("merge pull request: pr not found", map[string]any{
+			s.log().WarnCtx(ctx, "merge pull request: pr not found", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"error":           err.Error(),
 			})
 			return nil, err
 		}
+		if errors.Is(err, ErrStaleVersion) {
+			s.log().WarnCtx(ctx, "merge pull request: stale version", map[string]any{
+				"pull_request_id":  in.PullRequestId,
+				"expected_version": in.ExpectedVersion,
+			})
+			return nil, err
+		}
 
-		s.logger.Error("merge pull request repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "merge pull request repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"error":           err.Error(),
 		})
@@ -156,19 +181,33 @@ func (s *Service) MergePullRequest(ctx context.Context, in MergePullRequestInput
 		PR: mapDomainPRToDTO(pr),
 	}
 
-	s.logger.Info("merge pull request completed", map[string]any{
+	s.log().InfoCtx(ctx, "merge pull request completed", map[string]any{
 		"pull_request_id": out.PR.PullRequestId,
 		"status":          out.PR.Status,
 	})
 
 	s.metrics.IncPullRequestMerged()
 
+	// Merge is idempotent, repeated calls must not notify subscribers again
+	if merged {
+		s.publishEvent(ctx, domain.Event{
+			EventType:       domain.EventPullRequestMerged,
+			PullRequestId:   pr.PullRequestId,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorId,
+			Reviewers:       pr.AssignedReviewers,
+		})
+	}
+
 	return out, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput) (*ReassignReviewerOutput, error) {
+func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput) (_ *ReassignReviewerOutput, err error) {
+	ctx, op := s.startOperation(ctx, "ReassignReviewer")
+	defer func() { op.end(err) }()
+
 	if err := validateReassignReviewerInput(in); err != nil {
-		s.logger.Error("reassign reviewer validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer validation failed", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"error":           err.Error(),
@@ -176,7 +215,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 		return nil, err
 	}
 
-	s.logger.Info("reassign reviewer started", map[string]any{
+	s.log().InfoCtx(ctx, "reassign reviewer started", map[string]any{
 		"pull_request_id": in.PullRequestId,
 		"old_user_id":     in.OldUserId,
 	})
@@ -185,7 +224,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	pr, err := s.prs.GetPullRequest(ctx, in.PullRequestId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("reassign reviewer: pr not found", map[string]any{
+			s.log().WarnCtx(ctx, "reassign reviewer: pr not found", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"old_user_id":     in.OldUserId,
 				"error":           err.Error(),
@@ -193,7 +232,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 			return nil, err
 		}
 
-		s.logger.Error("reassign reviewer: get pr repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer: get pr repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"error":           err.Error(),
@@ -201,9 +240,20 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 		return nil, err
 	}
 
+	// Check if the PR was changed since the version the caller has seen
+	if in.ExpectedVersion != 0 && pr.Version != in.ExpectedVersion {
+		s.log().WarnCtx(ctx, "reassign reviewer: stale version", map[string]any{
+			"pull_request_id":  in.PullRequestId,
+			"old_user_id":      in.OldUserId,
+			"expected_version": in.ExpectedVersion,
+			"version":          pr.Version,
+		})
+		return nil, ErrStaleVersion
+	}
+
 	// Check if the PR is already merged
 	if statusString(pr.StatusId) == "MERGED" {
-		s.logger.Warn("reassign reviewer: pr already merged", map[string]any{
+		s.log().WarnCtx(ctx, "reassign reviewer: pr already merged", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 		})
@@ -219,7 +269,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 		}
 	}
 	if !foundOld {
-		s.logger.Warn("reassign reviewer: old reviewer is not assigned", map[string]any{
+		s.log().WarnCtx(ctx, "reassign reviewer: old reviewer is not assigned", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 		})
@@ -230,7 +280,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	teamName, err := s.users.GetTeamName(ctx, in.OldUserId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("reassign reviewer: reviewer has no team", map[string]any{
+			s.log().WarnCtx(ctx, "reassign reviewer: reviewer has no team", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"old_user_id":     in.OldUserId,
 				"error":           err.Error(),
@@ -238,7 +288,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 			return nil, err
 		}
 
-		s.logger.Error("reassign reviewer: get team name repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer: get team name repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"error":           err.Error(),
@@ -249,7 +299,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	// Get only active members
 	candidates, err := s.prs.GetActiveTeamMembers(ctx, teamName)
 	if err != nil {
-		s.logger.Error("reassign reviewer: get active team members error", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer: get active team members error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"team_name":       teamName,
@@ -278,7 +328,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	}
 
 	if len(candidateIds) == 0 {
-		s.logger.Warn("reassign reviewer: no available candidates", map[string]any{
+		s.log().WarnCtx(ctx, "reassign reviewer: no available candidates", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"team_name":       teamName,
@@ -289,11 +339,11 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	// Choose new reviewer
 	newReviewerId := candidateIds[rand.Intn(len(candidateIds))]
 
-	// Assign new reviewer
-	err = s.prs.ReplaceReviewer(ctx, in.PullRequestId, in.OldUserId, newReviewerId)
+	// Assign new reviewer, the version check is repeated in the same update
+	err = s.prs.ReplaceReviewer(ctx, in.PullRequestId, in.OldUserId, newReviewerId, in.ExpectedVersion)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("reassign reviewer: old reviewer not found in db for this pr", map[string]any{
+			s.log().WarnCtx(ctx, "reassign reviewer: old reviewer not found in db for this pr", map[string]any{
 				"pull_request_id": in.PullRequestId,
 				"old_user_id":     in.OldUserId,
 				"new_user_id":     newReviewerId,
@@ -301,8 +351,16 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 			})
 			return nil, err
 		}
+		if errors.Is(err, ErrStaleVersion) {
+			s.log().WarnCtx(ctx, "reassign reviewer: pr changed concurrently", map[string]any{
+				"pull_request_id":  in.PullRequestId,
+				"old_user_id":      in.OldUserId,
+				"expected_version": in.ExpectedVersion,
+			})
+			return nil, err
+		}
 
-		s.logger.Error("reassign reviewer: replace reviewer repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer: replace reviewer repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"new_user_id":     newReviewerId,
@@ -314,7 +372,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 	// Get the edited PR
 	updatedPr, err := s.prs.GetPullRequest(ctx, in.PullRequestId)
 	if err != nil {
-		s.logger.Error("reassign reviewer: get updated pr repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "reassign reviewer: get updated pr repository error", map[string]any{
 			"pull_request_id": in.PullRequestId,
 			"old_user_id":     in.OldUserId,
 			"new_user_id":     newReviewerId,
@@ -328,7 +386,7 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 		ReplacedBy: newReviewerId,
 	}
 
-	s.logger.Info("reassign reviewer completed", map[string]any{
+	s.log().InfoCtx(ctx, "reassign reviewer completed", map[string]any{
 		"pull_request_id": out.PR.PullRequestId,
 		"old_user_id":     in.OldUserId,
 		"new_user_id":     out.ReplacedBy,
@@ -336,5 +394,16 @@ func (s *Service) ReassignReviewer(ctx context.Context, in ReassignReviewerInput
 
 	s.metrics.IncPullRequestReassigned()
 
+	s.publishEvent(ctx, domain.Event{
+		EventType:       domain.EventReviewerReplaced,
+		PullRequestId:   updatedPr.PullRequestId,
+		PullRequestName: updatedPr.PullRequestName,
+		AuthorId:        updatedPr.AuthorId,
+		ReviewerId:      newReviewerId,
+		OldReviewerId:   in.OldUserId,
+		Reviewers:       updatedPr.AssignedReviewers,
+	})
+	s.notifyReviewerAssigned(ctx, newReviewerId, updatedPr)
+
 	return out, nil
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"pr-manager-service/internal/domain"
@@ -32,6 +33,11 @@ type mockPRRepo struct {
 
 	getPRResp *domain.PullRequest
 	getPRErr  error
+
+	mergeExpectedVersion int64
+	mergeResp            *domain.PullRequest
+	mergeMerged          bool
+	mergeErr             error
 }
 
 func (m *mockPRRepo) CreatePullRequest(ctx context.Context, pr *domain.PullRequest) error {
@@ -44,15 +50,16 @@ func (m *mockPRRepo) GetPullRequest(ctx context.Context, prId string) (*domain.P
 	return m.getPRResp, m.getPRErr
 }
 
-func (m *mockPRRepo) MergePullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
-	panic("not used in this test")
+func (m *mockPRRepo) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (*domain.PullRequest, bool, error) {
+	m.mergeExpectedVersion = expectedVersion
+	return m.mergeResp, m.mergeMerged, m.mergeErr
 }
 
 func (m *mockPRRepo) GetAllPrByUserId(ctx context.Context, userId string) ([]domain.PullRequest, error) {
 	panic("not used in this test")
 }
 
-func (m *mockPRRepo) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string) error {
+func (m *mockPRRepo) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error {
 	panic("not used in this test")
 }
 
@@ -73,12 +80,14 @@ func (l *noopLogger) Error(string, map[string]any) {}
 
 type dummyMetrics struct{}
 
-func (m *dummyMetrics) IncTeamCreated()           {}
-func (m *dummyMetrics) IncUserActivated()         {}
-func (m *dummyMetrics) IncUserDeactivated()       {}
-func (m *dummyMetrics) IncPullRequestCreated()    {}
-func (m *dummyMetrics) IncPullRequestMerged()     {}
-func (m *dummyMetrics) IncPullRequestReassigned() {}
+func (m *dummyMetrics) IncTeamCreated()                     {}
+func (m *dummyMetrics) IncUserActivated()                   {}
+func (m *dummyMetrics) IncUserDeactivated()                 {}
+func (m *dummyMetrics) IncPullRequestCreated()              {}
+func (m *dummyMetrics) IncPullRequestMerged()               {}
+func (m *dummyMetrics) IncPullRequestReassigned()           {}
+func (m *dummyMetrics) SetWorkload(*domain.Workload)        {}
+func (m *dummyMetrics) IncBusinessOperation(string, string) {}
 
 func TestCreatePullRequest_AssignsReviewers(t *testing.T) {
 	ctx := context.Background()
@@ -159,3 +168,117 @@ func TestReassignReviewer_MergedPR_ReturnsError(t *testing.T) {
 		t.Fatalf("expected ErrEditMergedPR, got %v", err)
 	}
 }
+
+func TestReassignReviewer_StaleVersion_ReturnsError(t *testing.T) {
+	ctx := context.Background()
+
+	// ReplaceReviewer of the mock panics, the check must happen before it
+	prRepo := &mockPRRepo{
+		getPRResp: &domain.PullRequest{
+			PullRequestId:     "pr-1001",
+			PullRequestName:   "Add search",
+			AuthorId:          "u1",
+			StatusId:          1,
+			AssignedReviewers: []string{"u2", "u3"},
+			Version:           3,
+		},
+	}
+	svc := &Service{
+		users:   &mockUserRepo{getTeamNameResp: "backend"},
+		prs:     prRepo,
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	_, err := svc.ReassignReviewer(ctx, ReassignReviewerInput{
+		PullRequestId:   "pr-1001",
+		OldUserId:       "u2",
+		ExpectedVersion: 2,
+	})
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("expected ErrStaleVersion, got %v", err)
+	}
+}
+
+func TestMergePullRequest_PublishesOnlyOnStatusChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		merged      bool
+		wantPublish bool
+	}{
+		{name: "open pr", merged: true, wantPublish: true},
+		{name: "already merged pr", merged: false, wantPublish: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prRepo := &mockPRRepo{
+				mergeResp: &domain.PullRequest{
+					PullRequestId: "pr-1001",
+					AuthorId:      "u1",
+					StatusId:      2,
+				},
+				mergeMerged: tt.merged,
+			}
+			broker := &mockEventBroker{ch: make(chan domain.Event, 1)}
+			svc := &Service{
+				prs:     prRepo,
+				events:  broker,
+				logger:  &noopLogger{},
+				metrics: &dummyMetrics{},
+			}
+
+			if _, err := svc.MergePullRequest(context.Background(), MergePullRequestInput{PullRequestId: "pr-1001"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if published := len(broker.ch) == 1; published != tt.wantPublish {
+				t.Fatalf("expected publish %v, got %v", tt.wantPublish, published)
+			}
+		})
+	}
+}
+
+func TestMergePullRequest_ExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		mergeErr    error
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "current version", wantVersion: 4},
+		{name: "stale version", mergeErr: ErrStaleVersion, wantErr: ErrStaleVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prRepo := &mockPRRepo{mergeErr: tt.mergeErr}
+			if tt.mergeErr == nil {
+				prRepo.mergeResp = &domain.PullRequest{
+					PullRequestId: "pr-1001",
+					AuthorId:      "u1",
+					StatusId:      2,
+					Version:       4,
+				}
+			}
+			svc := &Service{
+				prs:     prRepo,
+				logger:  &noopLogger{},
+				metrics: &dummyMetrics{},
+			}
+
+			out, err := svc.MergePullRequest(context.Background(), MergePullRequestInput{
+				PullRequestId:   "pr-1001",
+				ExpectedVersion: 3,
+			})
+			if prRepo.mergeExpectedVersion != 3 {
+				t.Fatalf("expected version 3 to reach the repository, got %d", prRepo.mergeExpectedVersion)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && out.PR.Version != tt.wantVersion {
+				t.Fatalf("expected version %d, got %d", tt.wantVersion, out.PR.Version)
+			}
+		})
+	}
+}
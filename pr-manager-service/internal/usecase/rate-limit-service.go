@@ -0,0 +1,67 @@
+package usecase
+
+import "context"
+
+// Rate limits
+
+// TakeRateLimitToken takes a token from the bucket of the caller and route.
+// A denied request gets the time until a token is available.
+func (s *Service) TakeRateLimitToken(ctx context.Context, in TakeRateLimitTokenInput) (_ *RateLimitDecisionDTO, err error) {
+	ctx, op := s.startOperation(ctx, "TakeRateLimitToken")
+	defer func() { op.end(err) }()
+
+	if err := validateRateLimit(in.Limit); err != nil {
+		return nil, err
+	}
+
+	if s.rateLimits == nil {
+		return nil, ErrNotConfigured
+	}
+
+	key := rateLimitKey(in.Scope, in.Route)
+	decision, err := s.rateLimits.TakeToken(ctx, key, mapRateLimitDTOToDomain(in.Limit))
+	if err != nil {
+		s.log().ErrorCtx(ctx, "take rate limit token repository error", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	if !decision.Allowed {
+		s.log().WarnCtx(ctx, "rate limit exceeded", map[string]any{
+			"key":         key,
+			"retry_after": decision.RetryAfter.String(),
+		})
+	}
+	return mapDomainRateLimitDecisionToDTO(decision), nil
+}
+
+// DeleteExpiredRateLimitBuckets removes buckets that refilled completely,
+// they behave the same as missing ones
+func (s *Service) DeleteExpiredRateLimitBuckets(ctx context.Context) (_ int64, err error) {
+	ctx, op := s.startOperation(ctx, "DeleteExpiredRateLimitBuckets")
+	defer func() { op.end(err) }()
+
+	if s.rateLimits == nil {
+		return 0, ErrNotConfigured
+	}
+
+	deleted, err := s.rateLimits.DeleteExpiredBuckets(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "delete expired rate limit buckets repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return 0, err
+	}
+	if deleted > 0 {
+		s.log().InfoCtx(ctx, "expired rate limit buckets deleted", map[string]any{
+			"deleted": deleted,
+		})
+	}
+	return deleted, nil
+}
+
+func rateLimitKey(scope, route string) string {
+	return route + " " + scope
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockRateLimitRepo struct {
+	buckets map[string]domain.TokenBucket
+	now     time.Time
+}
+
+func newMockRateLimitRepo() *mockRateLimitRepo {
+	return &mockRateLimitRepo{
+		buckets: make(map[string]domain.TokenBucket),
+		now:     time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func (m *mockRateLimitRepo) TakeToken(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = domain.NewTokenBucket(limit, m.now)
+	}
+	bucket, decision := bucket.TakeToken(limit, m.now)
+	m.buckets[key] = bucket
+	return decision, nil
+}
+
+func (m *mockRateLimitRepo) DeleteExpiredBuckets(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestTakeRateLimitToken(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockRateLimitRepo()
+	svc := NewService(nil, nil, nil, &noopLogger{}, &dummyMetrics{}, WithRateLimits(repo))
+
+	// 2 requests at once, then one every 30 seconds
+	limit := RateLimitDTO{Burst: 2, Period: time.Minute}
+	take := func(scope, route string) *RateLimitDecisionDTO {
+		t.Helper()
+		out, err := svc.TakeRateLimitToken(ctx, TakeRateLimitTokenInput{Scope: scope, Route: route, Limit: limit})
+		if err != nil {
+			t.Fatalf("take token: %v", err)
+		}
+		return out
+	}
+
+	if out := take("user:u1", "/pullRequest/create"); !out.Allowed || out.Remaining != 1 {
+		t.Fatalf("first request: expected allowed with 1 left, got %+v", out)
+	}
+	if out := take("user:u1", "/pullRequest/create"); !out.Allowed || out.Remaining != 0 {
+		t.Fatalf("second request: expected allowed with 0 left, got %+v", out)
+	}
+	out := take("user:u1", "/pullRequest/create")
+	if out.Allowed || out.RetryAfter != 30*time.Second {
+		t.Fatalf("third request: expected denied for 30s, got %+v", out)
+	}
+
+	// other callers and routes have their own buckets
+	if out := take("user:u2", "/pullRequest/create"); !out.Allowed {
+		t.Fatalf("another caller: expected allowed, got %+v", out)
+	}
+	if out := take("user:u1", "/pullRequest/merge"); !out.Allowed {
+		t.Fatalf("another route: expected allowed, got %+v", out)
+	}
+
+	// a denied request takes nothing, a token is back after RetryAfter
+	repo.now = repo.now.Add(20 * time.Second)
+	if out := take("user:u1", "/pullRequest/create"); out.Allowed || out.RetryAfter != 10*time.Second {
+		t.Fatalf("after 20s: expected denied for 10s, got %+v", out)
+	}
+	repo.now = repo.now.Add(10 * time.Second)
+	if out := take("user:u1", "/pullRequest/create"); !out.Allowed || out.Remaining != 0 {
+		t.Fatalf("after 30s: expected allowed with 0 left, got %+v", out)
+	}
+
+	// the bucket never holds more than Burst tokens
+	repo.now = repo.now.Add(time.Hour)
+	take("user:u1", "/pullRequest/create")
+	take("user:u1", "/pullRequest/create")
+	if out := take("user:u1", "/pullRequest/create"); out.Allowed {
+		t.Fatalf("after an hour: expected only 2 requests allowed, got %+v", out)
+	}
+}
+
+func TestTakeRateLimitToken_Validation(t *testing.T) {
+	svc := NewService(nil, nil, nil, &noopLogger{}, &dummyMetrics{})
+
+	for _, limit := range []RateLimitDTO{{Burst: 0, Period: time.Minute}, {Burst: 1, Period: 0}} {
+		_, err := svc.TakeRateLimitToken(context.Background(), TakeRateLimitTokenInput{Limit: limit})
+		if !errors.Is(err, ErrRateLimitInvalid) || !IsValidationError(err) {
+			t.Fatalf("limit %+v: expected ErrRateLimitInvalid, got %v", limit, err)
+		}
+	}
+
+	_, err := svc.TakeRateLimitToken(context.Background(), TakeRateLimitTokenInput{
+		Limit: RateLimitDTO{Burst: 1, Period: time.Minute},
+	})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured without a repository, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Review stream
+
+// Buffer of a single review stream, events are dropped for slow readers
+const reviewStreamBuffer = 16
+
+func (s *Service) SubscribeReviewStream(ctx context.Context, in SubscribeReviewStreamInput) (_ <-chan ReviewStreamEventDTO, err error) {
+	ctx, op := s.startOperation(ctx, "SubscribeReviewStream")
+	defer func() { op.end(err) }()
+
+	if err := validateSubscribeReviewStreamInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "subscribe review stream validation failed", map[string]any{
+			"user_id": in.UserId,
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.events == nil {
+		return nil, ErrNotConfigured
+	}
+
+	events, unsubscribe := s.events.Subscribe()
+	out := make(chan ReviewStreamEventDTO, reviewStreamBuffer)
+
+	s.log().InfoCtx(ctx, "review stream subscribed", map[string]any{
+		"user_id": in.UserId,
+	})
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.log().InfoCtx(ctx, "review stream unsubscribed", map[string]any{
+					"user_id": in.UserId,
+				})
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				kind := reviewStreamKind(ev, in.UserId)
+				if kind == "" {
+					continue
+				}
+				select {
+				case out <- mapDomainEventToReviewStreamDTO(ev, kind):
+				default:
+					s.log().WarnCtx(ctx, "review stream buffer is full, event dropped", map[string]any{
+						"user_id":         in.UserId,
+						"pull_request_id": ev.PullRequestId,
+						"kind":            kind,
+					})
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Returns how the event affects the review queue of the user, empty if it does not
+func reviewStreamKind(ev domain.Event, userId string) string {
+	switch ev.EventType {
+	case domain.EventReviewerAssigned:
+		if ev.ReviewerId == userId {
+			return ReviewStreamAssign
+		}
+	case domain.EventReviewerReplaced:
+		if ev.ReviewerId == userId {
+			return ReviewStreamAssign
+		}
+		if ev.OldReviewerId == userId {
+			return ReviewStreamUnassign
+		}
+	case domain.EventPullRequestMerged:
+		for _, r := range ev.Reviewers {
+			if r == userId {
+				return ReviewStreamMerge
+			}
+		}
+	}
+	return ""
+}
+
+// Publishes the event to live subscribers. Failures are logged only:
+// the change itself is already committed.
+func (s *Service) publishEvent(ctx context.Context, ev domain.Event) {
+	if s.events == nil {
+		return
+	}
+	if ev.OccurredAt.IsZero() {
+		ev.OccurredAt = time.Now()
+	}
+	if err := s.events.Publish(ctx, ev); err != nil {
+		s.log().ErrorCtx(ctx, "publish event error", map[string]any{
+			"event_type":      ev.EventType,
+			"pull_request_id": ev.PullRequestId,
+			"error":           err.Error(),
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockEventBroker struct {
+	ch chan domain.Event
+}
+
+func (m *mockEventBroker) Publish(ctx context.Context, ev domain.Event) error {
+	m.ch <- ev
+	return nil
+}
+
+func (m *mockEventBroker) Subscribe() (<-chan domain.Event, func()) {
+	return m.ch, func() {}
+}
+
+func TestReviewStreamKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		ev     domain.Event
+		userId string
+		want   string
+	}{
+		{
+			name:   "assigned to user",
+			ev:     domain.Event{EventType: domain.EventReviewerAssigned, ReviewerId: "u2"},
+			userId: "u2",
+			want:   ReviewStreamAssign,
+		},
+		{
+			name:   "assigned to other user",
+			ev:     domain.Event{EventType: domain.EventReviewerAssigned, ReviewerId: "u3"},
+			userId: "u2",
+			want:   "",
+		},
+		{
+			name:   "replaced by user",
+			ev:     domain.Event{EventType: domain.EventReviewerReplaced, ReviewerId: "u2", OldReviewerId: "u3"},
+			userId: "u2",
+			want:   ReviewStreamAssign,
+		},
+		{
+			name:   "user replaced",
+			ev:     domain.Event{EventType: domain.EventReviewerReplaced, ReviewerId: "u2", OldReviewerId: "u3"},
+			userId: "u3",
+			want:   ReviewStreamUnassign,
+		},
+		{
+			name:   "merged pr reviewed by user",
+			ev:     domain.Event{EventType: domain.EventPullRequestMerged, Reviewers: []string{"u2", "u3"}},
+			userId: "u3",
+			want:   ReviewStreamMerge,
+		},
+		{
+			name:   "merged pr of author",
+			ev:     domain.Event{EventType: domain.EventPullRequestMerged, AuthorId: "u1", Reviewers: []string{"u2"}},
+			userId: "u1",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reviewStreamKind(tt.ev, tt.userId)
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSubscribeReviewStream_ReceivesOwnEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := &mockEventBroker{ch: make(chan domain.Event, 4)}
+	svc := &Service{
+		events:  broker,
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	stream, err := svc.SubscribeReviewStream(ctx, SubscribeReviewStreamInput{UserId: "u2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc.publishEvent(ctx, domain.Event{EventType: domain.EventReviewerAssigned, PullRequestId: "pr-1", ReviewerId: "u3"})
+	svc.publishEvent(ctx, domain.Event{EventType: domain.EventReviewerAssigned, PullRequestId: "pr-2", ReviewerId: "u2"})
+
+	select {
+	case ev := <-stream:
+		if ev.PullRequestId != "pr-2" || ev.Kind != ReviewStreamAssign {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected event for u2")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-stream:
+		if ok {
+			t.Fatalf("expected stream to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected stream to be closed after cancel")
+	}
+}
+
+func TestSubscribeReviewStream_NotConfigured(t *testing.T) {
+	svc := &Service{
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+
+	_, err := svc.SubscribeReviewStream(context.Background(), SubscribeReviewStreamInput{UserId: "u2"})
+	if err != ErrNotConfigured {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
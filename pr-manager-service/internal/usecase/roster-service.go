@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Roster import and export
+
+// ImportRoster upserts teams, users, memberships and active flags from a roster.
+// Nothing is deleted: users and memberships missing in the roster are kept.
+func (s *Service) ImportRoster(ctx context.Context, in ImportRosterInput) (_ *ImportRosterOutput, err error) {
+	ctx, op := s.startOperation(ctx, "ImportRoster")
+	defer func() { op.end(err) }()
+
+	if err := validateImportRosterInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "import roster validation failed", map[string]any{
+			"teams_count": len(in.Teams),
+			"error":       err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.rosters == nil {
+		return nil, ErrNotConfigured
+	}
+
+	s.log().InfoCtx(ctx, "import roster started", map[string]any{
+		"teams_count": len(in.Teams),
+		"dry_run":     in.DryRun,
+	})
+
+	current, err := s.rosters.ListTeamRosters(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "import roster: list rosters repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := diffRosters(current, in.Teams)
+	out.DryRun = in.DryRun
+
+	if !in.DryRun && out.hasChanges() {
+		err = s.rosters.UpsertTeamRosters(ctx, mapRosterTeamsDTOToDomain(in.Teams))
+		if err != nil {
+			s.log().ErrorCtx(ctx, "import roster repository error", map[string]any{
+				"error": err.Error(),
+			})
+			return nil, err
+		}
+	}
+
+	s.log().InfoCtx(ctx, "import roster completed", map[string]any{
+		"dry_run":           out.DryRun,
+		"teams_created":     len(out.TeamsCreated),
+		"users_created":     len(out.UsersCreated),
+		"users_updated":     len(out.UsersUpdated),
+		"memberships_added": len(out.MembershipsAdded),
+	})
+
+	return out, nil
+}
+
+func (s *Service) ExportRoster(ctx context.Context) (_ *ExportRosterOutput, err error) {
+	ctx, op := s.startOperation(ctx, "ExportRoster")
+	defer func() { op.end(err) }()
+
+	if s.rosters == nil {
+		return nil, ErrNotConfigured
+	}
+
+	rosters, err := s.rosters.ListTeamRosters(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "export roster repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := &ExportRosterOutput{
+		Teams: mapDomainTeamRostersToDTO(rosters),
+	}
+
+	s.log().InfoCtx(ctx, "export roster completed", map[string]any{
+		"teams_count": len(out.Teams),
+	})
+
+	return out, nil
+}
+
+func (o *ImportRosterOutput) hasChanges() bool {
+	return len(o.TeamsCreated) > 0 || len(o.UsersCreated) > 0 ||
+		len(o.UsersUpdated) > 0 || len(o.MembershipsAdded) > 0
+}
+
+// Lists what an import of teams changes in the current state, in roster order
+func diffRosters(current []domain.TeamRoster, teams []RosterTeamDTO) *ImportRosterOutput {
+	existingTeams := make(map[string]struct{}, len(current))
+	existingUsers := make(map[string]domain.User)
+	existingMemberships := make(map[RosterMembershipDTO]struct{})
+
+	for _, r := range current {
+		existingTeams[r.TeamName] = struct{}{}
+		for _, u := range r.Members {
+			existingUsers[u.UserId] = u
+			existingMemberships[RosterMembershipDTO{TeamName: r.TeamName, UserId: u.UserId}] = struct{}{}
+		}
+	}
+
+	out := &ImportRosterOutput{
+		TeamsCreated:     []string{},
+		UsersCreated:     []TeamMemberDTO{},
+		UsersUpdated:     []RosterUserChangeDTO{},
+		MembershipsAdded: []RosterMembershipDTO{},
+	}
+	seenUsers := make(map[string]struct{})
+
+	for _, t := range teams {
+		if _, ok := existingTeams[t.TeamName]; !ok {
+			out.TeamsCreated = append(out.TeamsCreated, t.TeamName)
+		}
+
+		for _, m := range t.Members {
+			membership := RosterMembershipDTO{TeamName: t.TeamName, UserId: m.UserId}
+			if _, ok := existingMemberships[membership]; !ok {
+				out.MembershipsAdded = append(out.MembershipsAdded, membership)
+			}
+
+			if _, ok := seenUsers[m.UserId]; ok {
+				continue
+			}
+			seenUsers[m.UserId] = struct{}{}
+
+			old, ok := existingUsers[m.UserId]
+			switch {
+			case !ok:
+				out.UsersCreated = append(out.UsersCreated, m)
+			case old.UserName != m.UserName || old.IsActive != m.IsActive:
+				out.UsersUpdated = append(out.UsersUpdated, RosterUserChangeDTO{
+					UserId:      m.UserId,
+					OldUserName: old.UserName,
+					UserName:    m.UserName,
+					OldIsActive: old.IsActive,
+					IsActive:    m.IsActive,
+				})
+			}
+		}
+	}
+
+	return out
+}
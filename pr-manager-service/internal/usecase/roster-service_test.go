@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockRosterRepo struct {
+	rosters []domain.TeamRoster
+	listErr error
+
+	upserted    []domain.TeamRoster
+	upsertCalls int
+}
+
+func (m *mockRosterRepo) ListTeamRosters(ctx context.Context) ([]domain.TeamRoster, error) {
+	return m.rosters, m.listErr
+}
+
+func (m *mockRosterRepo) UpsertTeamRosters(ctx context.Context, rosters []domain.TeamRoster) error {
+	m.upsertCalls++
+	m.upserted = rosters
+	return nil
+}
+
+func newRosterService(repo *mockRosterRepo) *Service {
+	return &Service{
+		rosters: repo,
+		logger:  &noopLogger{},
+		metrics: &dummyMetrics{},
+	}
+}
+
+func TestImportRoster_Diff(t *testing.T) {
+	repo := &mockRosterRepo{
+		rosters: []domain.TeamRoster{
+			{TeamName: "backend", Members: []domain.User{
+				{UserId: "u1", UserName: "Alice", IsActive: true},
+				{UserId: "u2", UserName: "Bob", IsActive: true},
+			}},
+		},
+	}
+	svc := newRosterService(repo)
+
+	in := ImportRosterInput{
+		DryRun: true,
+		Teams: []RosterTeamDTO{
+			{TeamName: "backend", Members: []TeamMemberDTO{
+				{UserId: "u1", UserName: "Alice", IsActive: true},
+				{UserId: "u2", UserName: "Bob", IsActive: false},
+			}},
+			{TeamName: "frontend", Members: []TeamMemberDTO{
+				{UserId: "u1", UserName: "Alice", IsActive: true},
+				{UserId: "u3", UserName: "Carol", IsActive: true},
+			}},
+		},
+	}
+
+	out, err := svc.ImportRoster(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &ImportRosterOutput{
+		DryRun:       true,
+		TeamsCreated: []string{"frontend"},
+		UsersCreated: []TeamMemberDTO{{UserId: "u3", UserName: "Carol", IsActive: true}},
+		UsersUpdated: []RosterUserChangeDTO{
+			{UserId: "u2", OldUserName: "Bob", UserName: "Bob", OldIsActive: true, IsActive: false},
+		},
+		MembershipsAdded: []RosterMembershipDTO{
+			{TeamName: "frontend", UserId: "u1"},
+			{TeamName: "frontend", UserId: "u3"},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("unexpected diff:\n got %+v\nwant %+v", out, want)
+	}
+	if repo.upsertCalls != 0 {
+		t.Fatalf("dry run must not write, got %d upserts", repo.upsertCalls)
+	}
+
+	in.DryRun = false
+	if _, err := svc.ImportRoster(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.upsertCalls != 1 || len(repo.upserted) != 2 {
+		t.Fatalf("expected one upsert of 2 teams, got %d calls with %d teams", repo.upsertCalls, len(repo.upserted))
+	}
+}
+
+func TestImportRoster_NoChangesSkipsWrite(t *testing.T) {
+	repo := &mockRosterRepo{
+		rosters: []domain.TeamRoster{
+			{TeamName: "backend", Members: []domain.User{{UserId: "u1", UserName: "Alice", IsActive: true}}},
+		},
+	}
+	svc := newRosterService(repo)
+
+	export, err := svc.ExportRoster(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// an exported roster imports back without changes
+	out, err := svc.ImportRoster(context.Background(), ImportRosterInput{Teams: export.Teams})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.hasChanges() || repo.upsertCalls != 0 {
+		t.Fatalf("expected no changes and no writes, got %+v and %d upserts", out, repo.upsertCalls)
+	}
+}
+
+func TestImportRoster_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		teams   []RosterTeamDTO
+		wantErr error
+	}{
+		{
+			name:    "empty team name",
+			teams:   []RosterTeamDTO{{TeamName: ""}},
+			wantErr: ErrTeamNameRequired,
+		},
+		{
+			name:    "duplicate team",
+			teams:   []RosterTeamDTO{{TeamName: "backend"}, {TeamName: "backend"}},
+			wantErr: ErrRosterDuplicateTeam,
+		},
+		{
+			name:    "empty username",
+			teams:   []RosterTeamDTO{{TeamName: "backend", Members: []TeamMemberDTO{{UserId: "u1"}}}},
+			wantErr: ErrUserNameRequired,
+		},
+		{
+			name: "conflicting user",
+			teams: []RosterTeamDTO{
+				{TeamName: "backend", Members: []TeamMemberDTO{{UserId: "u1", UserName: "Alice", IsActive: true}}},
+				{TeamName: "frontend", Members: []TeamMemberDTO{{UserId: "u1", UserName: "Alice", IsActive: false}}},
+			},
+			wantErr: ErrRosterUserConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRosterRepo{}
+			_, err := newRosterService(repo).ImportRoster(context.Background(), ImportRosterInput{Teams: tt.teams})
+			if !errors.Is(err, tt.wantErr) || !IsValidationError(err) {
+				t.Fatalf("expected validation error %v, got %v", tt.wantErr, err)
+			}
+			if repo.upsertCalls != 0 {
+				t.Fatalf("invalid roster must not be written")
+			}
+		})
+	}
+}
+
+func TestImportRoster_NotConfigured(t *testing.T) {
+	svc := &Service{logger: &noopLogger{}, metrics: &dummyMetrics{}}
+	_, err := svc.ImportRoster(context.Background(), ImportRosterInput{})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -1,12 +1,118 @@
 package usecase
 
+import "time"
+
 // Service contains business logic for teams, users and pull requests
 type Service struct {
-	teams   TeamRepositoryInterface
-	users   UserRepositoryInterface
-	prs     PullRequestRepositoryInterface
-	logger  LoggerInterface
-	metrics MetricsInterface
+	teams            TeamRepositoryInterface
+	users            UserRepositoryInterface
+	prs              PullRequestRepositoryInterface
+	webhooks         WebhookRepositoryInterface
+	identities       IdentityRepositoryInterface
+	verdicts         ReviewVerdictRepositoryInterface
+	events           EventBrokerInterface
+	notifier         NotifierInterface
+	chats            ChatHandleRepositoryInterface
+	emails           EmailSubscriptionRepositoryInterface
+	mailer           MailerInterface
+	rosters          RosterRepositoryInterface
+	snapshots        SnapshotRepositoryInterface
+	idempotency      IdempotencyRepositoryInterface
+	idempotencyTTL   time.Duration
+	idempotencyLease time.Duration
+	workload         WorkloadRepositoryInterface
+	mergedWindow     time.Duration
+	rateLimits       RateLimitRepositoryInterface
+	logger           LoggerInterface
+	metrics          MetricsInterface
+}
+
+// ServiceOption configures optional dependencies of the Service
+type ServiceOption func(*Service)
+
+// WithWebhooks enables management of outgoing webhook subscriptions
+func WithWebhooks(webhooks WebhookRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.webhooks = webhooks
+	}
+}
+
+// WithIdentities enables git provider integrations
+func WithIdentities(identities IdentityRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.identities = identities
+	}
+}
+
+// WithReviewVerdicts enables verdicts of provider review events
+func WithReviewVerdicts(verdicts ReviewVerdictRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.verdicts = verdicts
+	}
+}
+
+// WithEventBroker enables live review streams
+func WithEventBroker(events EventBrokerInterface) ServiceOption {
+	return func(s *Service) {
+		s.events = events
+	}
+}
+
+// WithNotifier enables direct chat notifications to users with a chat handle
+func WithNotifier(notifier NotifierInterface, chats ChatHandleRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.notifier = notifier
+		s.chats = chats
+	}
+}
+
+// WithEmailDigest enables email subscriptions and the review digest
+func WithEmailDigest(emails EmailSubscriptionRepositoryInterface, mailer MailerInterface) ServiceOption {
+	return func(s *Service) {
+		s.emails = emails
+		s.mailer = mailer
+	}
+}
+
+// WithRoster enables bulk roster import and export
+func WithRoster(rosters RosterRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.rosters = rosters
+	}
+}
+
+// WithSnapshots enables snapshot backup and restore
+func WithSnapshots(snapshots SnapshotRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.snapshots = snapshots
+	}
+}
+
+// WithIdempotency enables Idempotency-Key support, responses are kept for ttl.
+// A request in progress holds its key for lease, so the key of a crashed
+// request is freed long before the ttl.
+func WithIdempotency(idempotency IdempotencyRepositoryInterface, ttl, lease time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.idempotency = idempotency
+		s.idempotencyTTL = ttl
+		s.idempotencyLease = lease
+	}
+}
+
+// WithWorkload enables the workload gauges, time to merge and to first
+// verdict cover merges and verdicts within mergedWindow
+func WithWorkload(workload WorkloadRepositoryInterface, mergedWindow time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.workload = workload
+		s.mergedWindow = mergedWindow
+	}
+}
+
+// WithRateLimits enables rate limiting with token buckets kept in rateLimits
+func WithRateLimits(rateLimits RateLimitRepositoryInterface) ServiceOption {
+	return func(s *Service) {
+		s.rateLimits = rateLimits
+	}
 }
 
 func NewService(
@@ -15,12 +121,17 @@ func NewService(
 	prs PullRequestRepositoryInterface,
 	logger LoggerInterface,
 	metrics MetricsInterface,
+	opts ...ServiceOption,
 ) *Service {
-	return &Service{
+	s := &Service{
 		teams:   teams,
 		users:   users,
 		prs:     prs,
 		logger:  logger,
 		metrics: metrics,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"pr-manager-service/internal/domain"
+)
+
+// Snapshots
+
+// ExportSnapshot streams all teams, users, memberships, pull requests and
+// reviewer assignments to w
+func (s *Service) ExportSnapshot(ctx context.Context, w SnapshotWriterInterface) (err error) {
+	ctx, op := s.startOperation(ctx, "ExportSnapshot")
+	defer func() { op.end(err) }()
+
+	if s.snapshots == nil {
+		return ErrNotConfigured
+	}
+
+	s.log().InfoCtx(ctx, "export snapshot started", map[string]any{})
+
+	err = s.snapshots.ExportSnapshot(ctx, w)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "export snapshot repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return err
+	}
+
+	s.log().InfoCtx(ctx, "export snapshot completed", map[string]any{})
+
+	return nil
+}
+
+// RestoreSnapshot loads a snapshot into an empty database. The snapshot must come
+// from the same or an older schema version, newer ones may hold unknown data.
+func (s *Service) RestoreSnapshot(ctx context.Context, in RestoreSnapshotInput) (_ *RestoreSnapshotOutput, err error) {
+	ctx, op := s.startOperation(ctx, "RestoreSnapshot")
+	defer func() { op.end(err) }()
+
+	if err := validateRestoreSnapshotInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "restore snapshot validation failed", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.snapshots == nil {
+		return nil, ErrNotConfigured
+	}
+
+	schemaVersion, err := s.snapshots.SchemaVersion(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "restore snapshot: schema version repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	snap := in.Snapshot
+	if snap.Header.SchemaVersion < domain.MinSnapshotSchemaVersion || snap.Header.SchemaVersion > schemaVersion {
+		err = fmt.Errorf("%w: snapshot has %d, database has %d", ErrSnapshotSchemaVersion, snap.Header.SchemaVersion, schemaVersion)
+		s.log().WarnCtx(ctx, "restore snapshot: schema version mismatch", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	s.log().InfoCtx(ctx, "restore snapshot started", map[string]any{
+		"schema_version": snap.Header.SchemaVersion,
+		"created_at":     snap.Header.CreatedAt,
+		"teams":          len(snap.Teams),
+		"users":          len(snap.Users),
+		"pull_requests":  len(snap.PullRequests),
+	})
+
+	err = s.snapshots.RestoreSnapshot(ctx, snap)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "restore snapshot repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := &RestoreSnapshotOutput{
+		SchemaVersion: schemaVersion,
+		Teams:         len(snap.Teams),
+		Users:         len(snap.Users),
+		Memberships:   len(snap.Memberships),
+		PullRequests:  len(snap.PullRequests),
+		Assignments:   len(snap.Assignments),
+	}
+
+	s.log().InfoCtx(ctx, "restore snapshot completed", map[string]any{
+		"teams":         out.Teams,
+		"users":         out.Users,
+		"memberships":   out.Memberships,
+		"pull_requests": out.PullRequests,
+		"assignments":   out.Assignments,
+	})
+
+	return out, nil
+}
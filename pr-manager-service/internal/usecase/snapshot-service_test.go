@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockSnapshotRepo struct {
+	schemaVersion int64
+	restoreErr    error
+	restored      *domain.Snapshot
+}
+
+func (m *mockSnapshotRepo) SchemaVersion(ctx context.Context) (int64, error) {
+	return m.schemaVersion, nil
+}
+
+func (m *mockSnapshotRepo) ExportSnapshot(ctx context.Context, w SnapshotWriterInterface) error {
+	return nil
+}
+
+func (m *mockSnapshotRepo) RestoreSnapshot(ctx context.Context, snapshot *domain.Snapshot) error {
+	if m.restoreErr != nil {
+		return m.restoreErr
+	}
+	m.restored = snapshot
+	return nil
+}
+
+func validSnapshot() *domain.Snapshot {
+	return &domain.Snapshot{
+		Header: domain.SnapshotHeader{
+			FormatVersion: domain.SnapshotFormatVersion,
+			SchemaVersion: 5,
+			CreatedAt:     time.Now(),
+		},
+		Teams: []domain.Team{{TeamName: "backend"}},
+		Users: []domain.User{
+			{UserId: "u1", UserName: "Alice", IsActive: true},
+			{UserId: "u2", UserName: "Bob", IsActive: true},
+		},
+		Memberships: []domain.Membership{
+			{TeamName: "backend", UserId: "u1"},
+			{TeamName: "backend", UserId: "u2"},
+		},
+		PullRequests: []domain.SnapshotPullRequest{
+			{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1", StatusId: 1, Version: 1},
+		},
+		Assignments: []domain.ReviewerAssignment{
+			{PullRequestId: "pr-1", UserId: "u2", Slot: 1},
+		},
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(s *domain.Snapshot)
+		schemaVersion int64
+		restoreErr    error
+		wantErr       error
+	}{
+		{
+			name:          "ok",
+			schemaVersion: 5,
+		},
+		{
+			name:          "older schema is compatible",
+			mutate:        func(s *domain.Snapshot) { s.Header.SchemaVersion = 3 },
+			schemaVersion: 5,
+		},
+		{
+			name:          "newer schema",
+			schemaVersion: 4,
+			wantErr:       ErrSnapshotSchemaVersion,
+		},
+		{
+			name:          "unknown format version",
+			mutate:        func(s *domain.Snapshot) { s.Header.FormatVersion = 99 },
+			schemaVersion: 5,
+			wantErr:       ErrSnapshotFormatVersion,
+		},
+		{
+			name:          "membership of unknown user",
+			mutate:        func(s *domain.Snapshot) { s.Memberships[0].UserId = "u9" },
+			schemaVersion: 5,
+			wantErr:       ErrSnapshotInvalid,
+		},
+		{
+			name: "two reviewers in one slot",
+			mutate: func(s *domain.Snapshot) {
+				s.Assignments = append(s.Assignments, domain.ReviewerAssignment{PullRequestId: "pr-1", UserId: "u1", Slot: 1})
+			},
+			schemaVersion: 5,
+			wantErr:       ErrSnapshotInvalid,
+		},
+		{
+			name:          "unknown status",
+			mutate:        func(s *domain.Snapshot) { s.PullRequests[0].StatusId = 0 },
+			schemaVersion: 5,
+			wantErr:       ErrSnapshotInvalid,
+		},
+		{
+			name:          "pull request without version",
+			mutate:        func(s *domain.Snapshot) { s.PullRequests[0].Version = 0 },
+			schemaVersion: 5,
+			wantErr:       ErrSnapshotInvalid,
+		},
+		{
+			name:          "database not empty",
+			schemaVersion: 5,
+			restoreErr:    ErrDatabaseNotEmpty,
+			wantErr:       ErrDatabaseNotEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := validSnapshot()
+			if tt.mutate != nil {
+				tt.mutate(snap)
+			}
+			repo := &mockSnapshotRepo{schemaVersion: tt.schemaVersion, restoreErr: tt.restoreErr}
+			svc := &Service{snapshots: repo, logger: &noopLogger{}, metrics: &dummyMetrics{}}
+
+			out, err := svc.RestoreSnapshot(context.Background(), RestoreSnapshotInput{Snapshot: snap})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.restored != snap || out.Users != 2 || out.Assignments != 1 || out.SchemaVersion != 5 {
+				t.Fatalf("unexpected output %+v", out)
+			}
+		})
+	}
+}
+
+func TestSnapshots_NotConfigured(t *testing.T) {
+	svc := &Service{logger: &noopLogger{}, metrics: &dummyMetrics{}}
+
+	if err := svc.ExportSnapshot(context.Background(), nil); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+	_, err := svc.RestoreSnapshot(context.Background(), RestoreSnapshotInput{Snapshot: validSnapshot()})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
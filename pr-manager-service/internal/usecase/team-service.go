@@ -8,25 +8,28 @@ import (
 
 // Teams
 
-func (s *Service) CreateTeam(ctx context.Context, in CreateTeamInput) (*CreateTeamOutput, error) {
+func (s *Service) CreateTeam(ctx context.Context, in CreateTeamInput) (_ *CreateTeamOutput, err error) {
+	ctx, op := s.startOperation(ctx, "CreateTeam")
+	defer func() { op.end(err) }()
+
 	if err := validateCreateTeamInput(in); err != nil {
-		s.logger.Error("create team validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "create team validation failed", map[string]any{
 			"team_name": in.TeamName,
 			"error":     err.Error(),
 		})
 		return nil, err
 	}
 
-	s.logger.Info("create team started", map[string]any{
+	s.log().InfoCtx(ctx, "create team started", map[string]any{
 		"team_name":     in.TeamName,
 		"members_count": len(in.Members),
 	})
 
 	members := mapTeamMembersDTOToDomain(in.Members)
 
-	err := s.teams.CreateTeam(ctx, in.TeamName, members)
+	err = s.teams.CreateTeam(ctx, in.TeamName, members)
 	if err != nil {
-		s.logger.Error("create team repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "create team repository error", map[string]any{
 			"team_name": in.TeamName,
 			"error":     err.Error(),
 		})
@@ -38,7 +41,7 @@ func (s *Service) CreateTeam(ctx context.Context, in CreateTeamInput) (*CreateTe
 		Members:  in.Members,
 	}
 
-	s.logger.Info("create team completed", map[string]any{
+	s.log().InfoCtx(ctx, "create team completed", map[string]any{
 		"team_name":     out.TeamName,
 		"members_count": len(out.Members),
 	})
@@ -48,30 +51,33 @@ func (s *Service) CreateTeam(ctx context.Context, in CreateTeamInput) (*CreateTe
 	return out, nil
 }
 
-func (s *Service) GetTeam(ctx context.Context, in GetTeamInput) (*GetTeamOutput, error) {
+func (s *Service) GetTeam(ctx context.Context, in GetTeamInput) (_ *GetTeamOutput, err error) {
+	ctx, op := s.startOperation(ctx, "GetTeam")
+	defer func() { op.end(err) }()
+
 	if err := validateGetTeamInput(in); err != nil {
-		s.logger.Error("get team validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "get team validation failed", map[string]any{
 			"team_name": in.TeamName,
 			"error":     err.Error(),
 		})
 		return nil, err
 	}
 
-	s.logger.Info("get team started", map[string]any{
+	s.log().InfoCtx(ctx, "get team started", map[string]any{
 		"team_name": in.TeamName,
 	})
 
 	_, members, err := s.teams.GetTeam(ctx, in.TeamName)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("get team: team not found", map[string]any{
+			s.log().WarnCtx(ctx, "get team: team not found", map[string]any{
 				"team_name": in.TeamName,
 				"error":     err.Error(),
 			})
 			return nil, err
 		}
 
-		s.logger.Error("get team repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "get team repository error", map[string]any{
 			"team_name": in.TeamName,
 			"error":     err.Error(),
 		})
@@ -85,7 +91,7 @@ func (s *Service) GetTeam(ctx context.Context, in GetTeamInput) (*GetTeamOutput,
 		Members:  outMembers,
 	}
 
-	s.logger.Info("get team completed", map[string]any{
+	s.log().InfoCtx(ctx, "get team completed", map[string]any{
 		"team_name":     out.TeamName,
 		"members_count": len(out.Members),
 	})
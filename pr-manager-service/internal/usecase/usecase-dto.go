@@ -1,5 +1,11 @@
 package usecase
 
+import (
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
 // Teams
 
 type TeamMemberDTO struct {
@@ -27,6 +33,59 @@ type GetTeamOutput struct {
 	Members  []TeamMemberDTO
 }
 
+// Roster import and export
+
+type RosterTeamDTO struct {
+	TeamName string
+	Members  []TeamMemberDTO
+}
+
+type ImportRosterInput struct {
+	Teams []RosterTeamDTO
+	// DryRun only reports the changes without applying them
+	DryRun bool
+}
+
+type RosterUserChangeDTO struct {
+	UserId      string
+	OldUserName string
+	UserName    string
+	OldIsActive bool
+	IsActive    bool
+}
+
+type RosterMembershipDTO struct {
+	TeamName string
+	UserId   string
+}
+
+type ImportRosterOutput struct {
+	DryRun           bool
+	TeamsCreated     []string
+	UsersCreated     []TeamMemberDTO
+	UsersUpdated     []RosterUserChangeDTO
+	MembershipsAdded []RosterMembershipDTO
+}
+
+type ExportRosterOutput struct {
+	Teams []RosterTeamDTO
+}
+
+// Snapshots
+
+type RestoreSnapshotInput struct {
+	Snapshot *domain.Snapshot
+}
+
+type RestoreSnapshotOutput struct {
+	SchemaVersion int64
+	Teams         int
+	Users         int
+	Memberships   int
+	PullRequests  int
+	Assignments   int
+}
+
 // Users
 
 type SetIsActiveInput struct {
@@ -57,6 +116,66 @@ type GetUserReviewsOutput struct {
 	PullRequests []PullRequestShortDTO
 }
 
+type SetChatHandleInput struct {
+	UserId   string
+	Provider string
+	Handle   string
+}
+
+type SetChatHandleOutput struct {
+	UserId   string
+	Provider string
+	Handle   string
+}
+
+type SendChatDigestOutput struct {
+	Notified int
+}
+
+type SetEmailSubscriptionInput struct {
+	UserId        string
+	Email         string
+	DigestEnabled bool
+}
+
+type SetEmailSubscriptionOutput struct {
+	UserId        string
+	Email         string
+	DigestEnabled bool
+}
+
+type UnsubscribeEmailInput struct {
+	Token string
+}
+
+type UnsubscribeEmailOutput struct {
+	UserId string
+}
+
+type SendEmailDigestOutput struct {
+	Sent   int
+	Failed int
+}
+
+type SubscribeReviewStreamInput struct {
+	UserId string
+}
+
+// Kinds of review stream events as seen by a reviewer
+const (
+	ReviewStreamAssign   = "assign"
+	ReviewStreamUnassign = "unassign"
+	ReviewStreamMerge    = "merge"
+)
+
+type ReviewStreamEventDTO struct {
+	Kind            string
+	PullRequestId   string
+	PullRequestName string
+	AuthorId        string
+	OccurredAt      time.Time
+}
+
 // Pull requests
 
 type CreatePullRequestInput struct {
@@ -71,6 +190,7 @@ type PullRequestDTO struct {
 	AuthorId          string
 	Status            string
 	AssignedReviewers []string
+	Version           int64
 }
 
 type CreatePullRequestOutput struct {
@@ -79,6 +199,9 @@ type CreatePullRequestOutput struct {
 
 type MergePullRequestInput struct {
 	PullRequestId string
+	// ExpectedVersion fails the merge with ErrStaleVersion if the pull
+	// request has another version, 0 skips the check
+	ExpectedVersion int64
 }
 
 type MergePullRequestOutput struct {
@@ -88,9 +211,134 @@ type MergePullRequestOutput struct {
 type ReassignReviewerInput struct {
 	PullRequestId string
 	OldUserId     string
+	// ExpectedVersion fails the reassignment with ErrStaleVersion if the
+	// pull request has another version, 0 skips the check
+	ExpectedVersion int64
 }
 
 type ReassignReviewerOutput struct {
 	PR         PullRequestDTO
 	ReplacedBy string
 }
+
+// Webhooks
+
+type CreateWebhookSubscriptionInput struct {
+	Url        string
+	Secret     string
+	EventTypes []string
+}
+
+type WebhookSubscriptionDTO struct {
+	Id         int64
+	Url        string
+	EventTypes []string
+	IsActive   bool
+}
+
+type CreateWebhookSubscriptionOutput struct {
+	Subscription WebhookSubscriptionDTO
+}
+
+type ListWebhookSubscriptionsOutput struct {
+	Subscriptions []WebhookSubscriptionDTO
+}
+
+type DeleteWebhookSubscriptionInput struct {
+	Id int64
+}
+
+// Integrations
+
+type SetIdentityInput struct {
+	Provider string
+	Login    string
+	UserId   string
+}
+
+type SetIdentityOutput struct {
+	Provider string
+	Login    string
+	UserId   string
+}
+
+// Provider pull request actions the service reacts to
+const (
+	ProviderActionOpened   = "opened"
+	ProviderActionReopened = "reopened"
+	ProviderActionClosed   = "closed"
+	ProviderActionMerged   = "merged"
+	// an approval or a change request of a reviewer
+	ProviderActionReviewed = "reviewed"
+)
+
+type ProviderPullRequestEventInput struct {
+	Provider        string
+	Action          string
+	PullRequestId   string
+	PullRequestName string
+	AuthorLogin     string
+	// set for reviewed events only
+	ReviewerLogin string
+}
+
+// Results of a handled provider event
+const (
+	ProviderEventCreated  = "created"
+	ProviderEventMerged   = "merged"
+	ProviderEventReviewed = "reviewed"
+	ProviderEventIgnored  = "ignored"
+)
+
+type ProviderPullRequestEventOutput struct {
+	Result string
+	PR     *PullRequestDTO
+}
+
+// Idempotency keys
+
+type BeginIdempotentRequestInput struct {
+	// Scope separates keys of different callers
+	Scope       string
+	Key         string
+	Fingerprint string
+}
+
+type StoredResponseDTO struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
+
+type BeginIdempotentRequestOutput struct {
+	// Replay is the saved response of an earlier request with the key,
+	// nil if the key was reserved for this request
+	Replay *StoredResponseDTO
+}
+
+type CompleteIdempotentRequestInput struct {
+	Scope    string
+	Key      string
+	Response StoredResponseDTO
+}
+
+// Rate limits
+
+// RateLimitDTO allows Burst requests at once and Burst per Period after that
+type RateLimitDTO struct {
+	Burst  int
+	Period time.Duration
+}
+
+type TakeRateLimitTokenInput struct {
+	// Scope is the rate limited caller, buckets of different routes are separate
+	Scope string
+	Route string
+	Limit RateLimitDTO
+}
+
+type RateLimitDecisionDTO struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
@@ -8,16 +8,19 @@ import (
 
 // Users
 
-func (s *Service) SetIsActive(ctx context.Context, in SetIsActiveInput) (*SetIsActiveOutput, error) {
+func (s *Service) SetIsActive(ctx context.Context, in SetIsActiveInput) (_ *SetIsActiveOutput, err error) {
+	ctx, op := s.startOperation(ctx, "SetIsActive")
+	defer func() { op.end(err) }()
+
 	if err := validateSetIsActiveInput(in); err != nil {
-		s.logger.Error("set is_active validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "set is_active validation failed", map[string]any{
 			"user_id": in.UserId,
 			"error":   err.Error(),
 		})
 		return nil, err
 	}
 
-	s.logger.Info("set is_active started", map[string]any{
+	s.log().InfoCtx(ctx, "set is_active started", map[string]any{
 		"user_id":   in.UserId,
 		"is_active": in.IsActive,
 	})
@@ -25,14 +28,14 @@ func (s *Service) SetIsActive(ctx context.Context, in SetIsActiveInput) (*SetIsA
 	user, teamName, err := s.users.SetIsActive(ctx, in.UserId, in.IsActive)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("set is_active: user not found", map[string]any{
+			s.log().WarnCtx(ctx, "set is_active: user not found", map[string]any{
 				"user_id": in.UserId,
 				"error":   err.Error(),
 			})
 			return nil, err
 		}
 
-		s.logger.Error("set is_active repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "set is_active repository error", map[string]any{
 			"user_id": in.UserId,
 			"error":   err.Error(),
 		})
@@ -41,7 +44,7 @@ func (s *Service) SetIsActive(ctx context.Context, in SetIsActiveInput) (*SetIsA
 
 	out := mapDomainUserToSetIsActiveOutput(user, teamName)
 
-	s.logger.Info("set is_active completed", map[string]any{
+	s.log().InfoCtx(ctx, "set is_active completed", map[string]any{
 		"user_id":   out.UserId,
 		"is_active": out.IsActive,
 		"team_name": out.TeamName,
@@ -56,22 +59,25 @@ func (s *Service) SetIsActive(ctx context.Context, in SetIsActiveInput) (*SetIsA
 	return out, nil
 }
 
-func (s *Service) GetUserReviews(ctx context.Context, in GetUserReviewsInput) (*GetUserReviewsOutput, error) {
+func (s *Service) GetUserReviews(ctx context.Context, in GetUserReviewsInput) (_ *GetUserReviewsOutput, err error) {
+	ctx, op := s.startOperation(ctx, "GetUserReviews")
+	defer func() { op.end(err) }()
+
 	if err := validateGetUserReviewsInput(in); err != nil {
-		s.logger.Error("get user reviews validation failed", map[string]any{
+		s.log().ErrorCtx(ctx, "get user reviews validation failed", map[string]any{
 			"user_id": in.UserId,
 			"error":   err.Error(),
 		})
 		return nil, err
 	}
 
-	s.logger.Info("get user reviews started", map[string]any{
+	s.log().InfoCtx(ctx, "get user reviews started", map[string]any{
 		"user_id": in.UserId,
 	})
 
 	prs, err := s.prs.GetAllPrByUserId(ctx, in.UserId)
 	if err != nil {
-		s.logger.Error("get user reviews repository error", map[string]any{
+		s.log().ErrorCtx(ctx, "get user reviews repository error", map[string]any{
 			"user_id": in.UserId,
 			"error":   err.Error(),
 		})
@@ -80,7 +86,7 @@ func (s *Service) GetUserReviews(ctx context.Context, in GetUserReviewsInput) (*
 
 	out := mapDomainPRsToGetUserReviewsOutput(in.UserId, prs)
 
-	s.logger.Info("get user reviews completed", map[string]any{
+	s.log().InfoCtx(ctx, "get user reviews completed", map[string]any{
 		"user_id":  out.UserId,
 		"pr_count": len(out.PullRequests),
 	})
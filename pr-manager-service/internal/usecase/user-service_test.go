@@ -40,7 +40,7 @@ func (m *prRepoMockForUserService) GetPullRequest(ctx context.Context, prId stri
 	panic("not used")
 }
 
-func (m *prRepoMockForUserService) MergePullRequest(ctx context.Context, prId string) (*domain.PullRequest, error) {
+func (m *prRepoMockForUserService) MergePullRequest(ctx context.Context, prId string, expectedVersion int64) (*domain.PullRequest, bool, error) {
 	panic("not used")
 }
 
@@ -48,7 +48,7 @@ func (m *prRepoMockForUserService) GetAllPrByUserId(ctx context.Context, userId
 	return m.getAllResp, m.getAllErr
 }
 
-func (m *prRepoMockForUserService) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string) error {
+func (m *prRepoMockForUserService) ReplaceReviewer(ctx context.Context, prId, oldUserId, newUserId string, expectedVersion int64) error {
 	panic("not used")
 }
 
@@ -63,6 +63,9 @@ type metricsMock struct {
 	prCreated             int
 	prMerged              int
 	prReassigned          int
+	// "operation:result" -> count
+	operations map[string]int
+	workload   *domain.Workload
 }
 
 func (m *metricsMock) IncTeamCreated()           { m.teamCreated++ }
@@ -71,6 +74,13 @@ func (m *metricsMock) IncUserDeactivated()       { m.userDeactivated++ }
 func (m *metricsMock) IncPullRequestCreated()    { m.prCreated++ }
 func (m *metricsMock) IncPullRequestMerged()     { m.prMerged++ }
 func (m *metricsMock) IncPullRequestReassigned() { m.prReassigned++ }
+func (m *metricsMock) SetWorkload(w *domain.Workload) { m.workload = w }
+func (m *metricsMock) IncBusinessOperation(operation, result string) {
+	if m.operations == nil {
+		m.operations = map[string]int{}
+	}
+	m.operations[operation+":"+result]++
+}
 
 func TestSetIsActive_TableDriven(t *testing.T) {
 	ctx := context.Background()
@@ -165,6 +175,14 @@ func TestSetIsActive_TableDriven(t *testing.T) {
 			if metrics.userDeactivated != tt.wantDeact {
 				t.Fatalf("expected userDeactivated=%d, got %d", tt.wantDeact, metrics.userDeactivated)
 			}
+
+			result := OperationSuccess
+			if tt.wantErr != nil {
+				result = OperationFailure
+			}
+			if got := metrics.operations["set_is_active:"+result]; got != 1 || len(metrics.operations) != 1 {
+				t.Fatalf("expected one set_is_active:%s operation, got %v", result, metrics.operations)
+			}
 		})
 	}
 }
@@ -1,5 +1,13 @@
 package usecase
 
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+
+	"pr-manager-service/internal/domain"
+)
+
 func validateCreateTeamInput(in CreateTeamInput) error {
 	if in.TeamName == "" {
 		return ErrTeamNameRequired
@@ -14,6 +22,137 @@ func validateGetTeamInput(in GetTeamInput) error {
 	return nil
 }
 
+func validateImportRosterInput(in ImportRosterInput) error {
+	teams := make(map[string]struct{}, len(in.Teams))
+	users := make(map[string]TeamMemberDTO)
+
+	for _, t := range in.Teams {
+		if t.TeamName == "" {
+			return ErrTeamNameRequired
+		}
+		if _, ok := teams[t.TeamName]; ok {
+			return fmt.Errorf("%w: %s", ErrRosterDuplicateTeam, t.TeamName)
+		}
+		teams[t.TeamName] = struct{}{}
+
+		for _, m := range t.Members {
+			if m.UserId == "" {
+				return fmt.Errorf("%w: team %s", ErrUserIdRequired, t.TeamName)
+			}
+			if m.UserName == "" {
+				return fmt.Errorf("%w: user %s", ErrUserNameRequired, m.UserId)
+			}
+			// a user may be in several teams, but must be the same user everywhere
+			if prev, ok := users[m.UserId]; ok && prev != m {
+				return fmt.Errorf("%w: %s", ErrRosterUserConflict, m.UserId)
+			}
+			users[m.UserId] = m
+		}
+	}
+	return nil
+}
+
+// Checks the snapshot is self-consistent, so the restore does not fail halfway on a foreign key
+func validateRestoreSnapshotInput(in RestoreSnapshotInput) error {
+	snap := in.Snapshot
+	if snap == nil {
+		return fmt.Errorf("%w: snapshot is empty", ErrSnapshotInvalid)
+	}
+	if snap.Header.FormatVersion != domain.SnapshotFormatVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrSnapshotFormatVersion, snap.Header.FormatVersion, domain.SnapshotFormatVersion)
+	}
+
+	teams := make(map[string]struct{}, len(snap.Teams))
+	for _, t := range snap.Teams {
+		if t.TeamName == "" {
+			return fmt.Errorf("%w: %v", ErrSnapshotInvalid, ErrTeamNameRequired)
+		}
+		if _, ok := teams[t.TeamName]; ok {
+			return fmt.Errorf("%w: duplicate team %s", ErrSnapshotInvalid, t.TeamName)
+		}
+		teams[t.TeamName] = struct{}{}
+	}
+
+	users := make(map[string]struct{}, len(snap.Users))
+	for _, u := range snap.Users {
+		if u.UserId == "" {
+			return fmt.Errorf("%w: %v", ErrSnapshotInvalid, ErrUserIdRequired)
+		}
+		if _, ok := users[u.UserId]; ok {
+			return fmt.Errorf("%w: duplicate user %s", ErrSnapshotInvalid, u.UserId)
+		}
+		users[u.UserId] = struct{}{}
+	}
+
+	memberships := make(map[domain.Membership]struct{}, len(snap.Memberships))
+	for _, m := range snap.Memberships {
+		if _, ok := teams[m.TeamName]; !ok {
+			return fmt.Errorf("%w: membership of unknown team %s", ErrSnapshotInvalid, m.TeamName)
+		}
+		if _, ok := users[m.UserId]; !ok {
+			return fmt.Errorf("%w: membership of unknown user %s", ErrSnapshotInvalid, m.UserId)
+		}
+		if _, ok := memberships[m]; ok {
+			return fmt.Errorf("%w: duplicate membership %s/%s", ErrSnapshotInvalid, m.TeamName, m.UserId)
+		}
+		memberships[m] = struct{}{}
+	}
+
+	prs := make(map[string]struct{}, len(snap.PullRequests))
+	for _, pr := range snap.PullRequests {
+		if pr.PullRequestId == "" {
+			return fmt.Errorf("%w: %v", ErrSnapshotInvalid, ErrPullRequestIdRequired)
+		}
+		if _, ok := prs[pr.PullRequestId]; ok {
+			return fmt.Errorf("%w: duplicate pull request %s", ErrSnapshotInvalid, pr.PullRequestId)
+		}
+		if _, ok := users[pr.AuthorId]; !ok {
+			return fmt.Errorf("%w: pull request %s has unknown author %s", ErrSnapshotInvalid, pr.PullRequestId, pr.AuthorId)
+		}
+		if pr.StatusId != 1 && pr.StatusId != 2 {
+			return fmt.Errorf("%w: pull request %s has unknown status", ErrSnapshotInvalid, pr.PullRequestId)
+		}
+		if pr.Version < 1 {
+			return fmt.Errorf("%w: pull request %s has version %d", ErrSnapshotInvalid, pr.PullRequestId, pr.Version)
+		}
+		prs[pr.PullRequestId] = struct{}{}
+	}
+
+	type slotKey struct {
+		prId string
+		slot int
+	}
+	type reviewerKey struct {
+		prId   string
+		userId string
+	}
+	slots := make(map[slotKey]struct{}, len(snap.Assignments))
+	reviewers := make(map[reviewerKey]struct{}, len(snap.Assignments))
+	for _, a := range snap.Assignments {
+		if _, ok := prs[a.PullRequestId]; !ok {
+			return fmt.Errorf("%w: assignment to unknown pull request %s", ErrSnapshotInvalid, a.PullRequestId)
+		}
+		if _, ok := users[a.UserId]; !ok {
+			return fmt.Errorf("%w: assignment of unknown user %s", ErrSnapshotInvalid, a.UserId)
+		}
+		if a.Slot != 1 && a.Slot != 2 {
+			return fmt.Errorf("%w: assignment slot must be 1 or 2", ErrSnapshotInvalid)
+		}
+		key := slotKey{prId: a.PullRequestId, slot: a.Slot}
+		if _, ok := slots[key]; ok {
+			return fmt.Errorf("%w: pull request %s has two reviewers in slot %d", ErrSnapshotInvalid, a.PullRequestId, a.Slot)
+		}
+		slots[key] = struct{}{}
+		reviewer := reviewerKey{prId: a.PullRequestId, userId: a.UserId}
+		if _, ok := reviewers[reviewer]; ok {
+			return fmt.Errorf("%w: user %s is assigned twice to %s", ErrSnapshotInvalid, a.UserId, a.PullRequestId)
+		}
+		reviewers[reviewer] = struct{}{}
+	}
+
+	return nil
+}
+
 func validateSetIsActiveInput(in SetIsActiveInput) error {
 	if in.UserId == "" {
 		return ErrUserIdRequired
@@ -28,6 +167,47 @@ func validateGetUserReviewsInput(in GetUserReviewsInput) error {
 	return nil
 }
 
+func validateSetChatHandleInput(in SetChatHandleInput) error {
+	if in.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if !domain.IsKnownChatProvider(in.Provider) {
+		return ErrUnknownChatProvider
+	}
+	if in.Handle == "" {
+		return ErrChatHandleRequired
+	}
+	return nil
+}
+
+func validateSetEmailSubscriptionInput(in SetEmailSubscriptionInput) error {
+	if in.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if in.Email == "" {
+		return ErrEmailRequired
+	}
+	addr, err := mail.ParseAddress(in.Email)
+	if err != nil || addr.Address != in.Email {
+		return ErrEmailInvalid
+	}
+	return nil
+}
+
+func validateUnsubscribeEmailInput(in UnsubscribeEmailInput) error {
+	if in.Token == "" {
+		return ErrUnsubscribeTokenRequired
+	}
+	return nil
+}
+
+func validateSubscribeReviewStreamInput(in SubscribeReviewStreamInput) error {
+	if in.UserId == "" {
+		return ErrUserIdRequired
+	}
+	return nil
+}
+
 func validateCreatePullRequestInput(in CreatePullRequestInput) error {
 	if in.PullRequestId == "" {
 		return ErrPullRequestIdRequired
@@ -57,3 +237,72 @@ func validateReassignReviewerInput(in ReassignReviewerInput) error {
 	}
 	return nil
 }
+
+func validateCreateWebhookSubscriptionInput(in CreateWebhookSubscriptionInput) error {
+	if in.Url == "" {
+		return ErrWebhookUrlRequired
+	}
+	u, err := url.Parse(in.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookUrlInvalid
+	}
+	if in.Secret == "" {
+		return ErrWebhookSecretRequired
+	}
+	for _, t := range in.EventTypes {
+		if !domain.IsKnownEventType(t) {
+			return ErrUnknownEventType
+		}
+	}
+	return nil
+}
+
+func validateDeleteWebhookSubscriptionInput(in DeleteWebhookSubscriptionInput) error {
+	if in.Id == 0 {
+		return ErrWebhookIdRequired
+	}
+	return nil
+}
+
+func validateSetIdentityInput(in SetIdentityInput) error {
+	if !domain.IsKnownProvider(in.Provider) {
+		return ErrUnknownProvider
+	}
+	if in.Login == "" {
+		return ErrLoginRequired
+	}
+	if in.UserId == "" {
+		return ErrUserIdRequired
+	}
+	return nil
+}
+
+func validateProviderPullRequestEventInput(in ProviderPullRequestEventInput) error {
+	if !domain.IsKnownProvider(in.Provider) {
+		return ErrUnknownProvider
+	}
+	if in.PullRequestId == "" {
+		return ErrPullRequestIdRequired
+	}
+	return nil
+}
+
+// Idempotency keys are 1 to 255 printable ASCII characters, e.g. a UUID
+func validateBeginIdempotentRequestInput(in BeginIdempotentRequestInput) error {
+	if len(in.Key) == 0 || len(in.Key) > 255 {
+		return ErrIdempotencyKeyInvalid
+	}
+	for i := 0; i < len(in.Key); i++ {
+		if in.Key[i] < 0x20 || in.Key[i] > 0x7e {
+			return ErrIdempotencyKeyInvalid
+		}
+	}
+	return nil
+}
+
+func validateRateLimit(limit RateLimitDTO) error {
+	if limit.Burst < 1 || limit.Period <= 0 {
+		return ErrRateLimitInvalid
+	}
+	return nil
+}
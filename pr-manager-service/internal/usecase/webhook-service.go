@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Webhooks
+
+func (s *Service) CreateWebhookSubscription(ctx context.Context, in CreateWebhookSubscriptionInput) (_ *CreateWebhookSubscriptionOutput, err error) {
+	ctx, op := s.startOperation(ctx, "CreateWebhookSubscription")
+	defer func() { op.end(err) }()
+
+	if err := validateCreateWebhookSubscriptionInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "create webhook subscription validation failed", map[string]any{
+			"url":         in.Url,
+			"event_types": in.EventTypes,
+			"error":       err.Error(),
+		})
+		return nil, err
+	}
+
+	if s.webhooks == nil {
+		return nil, ErrNotConfigured
+	}
+
+	s.log().InfoCtx(ctx, "create webhook subscription started", map[string]any{
+		"url":         in.Url,
+		"event_types": in.EventTypes,
+	})
+
+	sub := mapCreateWebhookSubscriptionInputToDomain(in)
+
+	err = s.webhooks.CreateSubscription(ctx, sub)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "create webhook subscription repository error", map[string]any{
+			"url":   in.Url,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := &CreateWebhookSubscriptionOutput{
+		Subscription: mapDomainWebhookSubscriptionToDTO(sub),
+	}
+
+	s.log().InfoCtx(ctx, "create webhook subscription completed", map[string]any{
+		"subscription_id": out.Subscription.Id,
+		"url":             out.Subscription.Url,
+	})
+
+	return out, nil
+}
+
+func (s *Service) ListWebhookSubscriptions(ctx context.Context) (_ *ListWebhookSubscriptionsOutput, err error) {
+	ctx, op := s.startOperation(ctx, "ListWebhookSubscriptions")
+	defer func() { op.end(err) }()
+
+	if s.webhooks == nil {
+		return nil, ErrNotConfigured
+	}
+
+	subs, err := s.webhooks.ListSubscriptions(ctx)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "list webhook subscriptions repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+
+	out := &ListWebhookSubscriptionsOutput{
+		Subscriptions: make([]WebhookSubscriptionDTO, 0, len(subs)),
+	}
+	for i := range subs {
+		out.Subscriptions = append(out.Subscriptions, mapDomainWebhookSubscriptionToDTO(&subs[i]))
+	}
+
+	return out, nil
+}
+
+func (s *Service) DeleteWebhookSubscription(ctx context.Context, in DeleteWebhookSubscriptionInput) (err error) {
+	ctx, op := s.startOperation(ctx, "DeleteWebhookSubscription")
+	defer func() { op.end(err) }()
+
+	if err := validateDeleteWebhookSubscriptionInput(in); err != nil {
+		s.log().ErrorCtx(ctx, "delete webhook subscription validation failed", map[string]any{
+			"subscription_id": in.Id,
+			"error":           err.Error(),
+		})
+		return err
+	}
+
+	if s.webhooks == nil {
+		return ErrNotConfigured
+	}
+
+	err = s.webhooks.DeleteSubscription(ctx, in.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log().WarnCtx(ctx, "delete webhook subscription: subscription not found", map[string]any{
+				"subscription_id": in.Id,
+				"error":           err.Error(),
+			})
+			return err
+		}
+
+		s.log().ErrorCtx(ctx, "delete webhook subscription repository error", map[string]any{
+			"subscription_id": in.Id,
+			"error":           err.Error(),
+		})
+		return err
+	}
+
+	s.log().InfoCtx(ctx, "delete webhook subscription completed", map[string]any{
+		"subscription_id": in.Id,
+	})
+
+	return nil
+}
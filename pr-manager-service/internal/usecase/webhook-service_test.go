@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockWebhookRepo struct {
+	createCalled bool
+	createErr    error
+
+	claimResp []domain.WebhookDelivery
+
+	completed    []int64
+	retried      []int64
+	retryDelay   time.Duration
+	retryAttempt int
+	deadLettered []int64
+
+	deleteOlderThan            time.Duration
+	deleteResp                 int64
+	deleteUnprocessedOlderThan time.Duration
+}
+
+func (m *mockWebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	m.createCalled = true
+	sub.Id = 1
+	return m.createErr
+}
+
+func (m *mockWebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	panic("not used in this test")
+}
+
+func (m *mockWebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	panic("not used in this test")
+}
+
+func (m *mockWebhookRepo) FanOutEvents(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	return m.claimResp, nil
+}
+
+func (m *mockWebhookRepo) CompleteDelivery(ctx context.Context, deliveryId int64) error {
+	m.completed = append(m.completed, deliveryId)
+	return nil
+}
+
+func (m *mockWebhookRepo) RetryDelivery(ctx context.Context, deliveryId int64, attempts int, delay time.Duration, lastErr string) error {
+	m.retried = append(m.retried, deliveryId)
+	m.retryAttempt = attempts
+	m.retryDelay = delay
+	return nil
+}
+
+func (m *mockWebhookRepo) DeadLetterDelivery(ctx context.Context, deliveryId int64, attempts int, lastErr string) error {
+	m.deadLettered = append(m.deadLettered, deliveryId)
+	return nil
+}
+
+func (m *mockWebhookRepo) DeleteProcessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.deleteOlderThan = olderThan
+	return m.deleteResp, nil
+}
+
+func (m *mockWebhookRepo) DeleteUnprocessedEvents(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.deleteUnprocessedOlderThan = olderThan
+	return m.deleteResp, nil
+}
+
+type mockWebhookSender struct {
+	errs map[int64]error
+}
+
+func (m *mockWebhookSender) Send(ctx context.Context, d domain.WebhookDelivery) error {
+	return m.errs[d.Id]
+}
+
+func TestCreateWebhookSubscription_TableDriven(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		input     CreateWebhookSubscriptionInput
+		wantErr   error
+		wantCalls bool
+	}{
+		{
+			name: "ok",
+			input: CreateWebhookSubscriptionInput{
+				Url:        "https://bot.example.com/hook",
+				Secret:     "s3cr3t",
+				EventTypes: []string{domain.EventReviewerAssigned},
+			},
+			wantErr:   nil,
+			wantCalls: true,
+		},
+		{
+			name:      "empty url",
+			input:     CreateWebhookSubscriptionInput{Secret: "s3cr3t"},
+			wantErr:   ErrWebhookUrlRequired,
+			wantCalls: false,
+		},
+		{
+			name:      "relative url",
+			input:     CreateWebhookSubscriptionInput{Url: "/hook", Secret: "s3cr3t"},
+			wantErr:   ErrWebhookUrlInvalid,
+			wantCalls: false,
+		},
+		{
+			name:      "empty secret",
+			input:     CreateWebhookSubscriptionInput{Url: "https://bot.example.com/hook"},
+			wantErr:   ErrWebhookSecretRequired,
+			wantCalls: false,
+		},
+		{
+			name: "unknown event type",
+			input: CreateWebhookSubscriptionInput{
+				Url:        "https://bot.example.com/hook",
+				Secret:     "s3cr3t",
+				EventTypes: []string{"pull_request.closed"},
+			},
+			wantErr:   ErrUnknownEventType,
+			wantCalls: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockWebhookRepo{}
+			svc := &Service{
+				webhooks: repo,
+				logger:   &noopLogger{},
+				metrics:  &dummyMetrics{},
+			}
+
+			out, err := svc.CreateWebhookSubscription(ctx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err %v, got %v", tt.wantErr, err)
+			}
+			if repo.createCalled != tt.wantCalls {
+				t.Fatalf("expected createCalled=%v, got %v", tt.wantCalls, repo.createCalled)
+			}
+			if err == nil && out.Subscription.Id != 1 {
+				t.Fatalf("expected subscription id 1, got %d", out.Subscription.Id)
+			}
+		})
+	}
+}
+
+func TestWebhookWorker_RunOnce(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mockWebhookRepo{
+		claimResp: []domain.WebhookDelivery{
+			{Id: 1, Attempts: 0},
+			{Id: 2, Attempts: 2},
+			{Id: 3, Attempts: 4},
+		},
+	}
+	sender := &mockWebhookSender{
+		errs: map[int64]error{
+			2: errors.New("connection refused"),
+			3: errors.New("status 500"),
+		},
+	}
+	worker := NewWebhookWorker(repo, sender, &noopLogger{}, WebhookWorkerConfig{
+		BatchSize:   10,
+		MaxAttempts: 5,
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+		Lease:       time.Minute,
+	})
+
+	if err := worker.RunOnce(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.completed) != 1 || repo.completed[0] != 1 {
+		t.Fatalf("expected delivery 1 to be completed, got %v", repo.completed)
+	}
+	if len(repo.retried) != 1 || repo.retried[0] != 2 {
+		t.Fatalf("expected delivery 2 to be retried, got %v", repo.retried)
+	}
+	if repo.retryAttempt != 3 {
+		t.Fatalf("expected attempts 3, got %d", repo.retryAttempt)
+	}
+	if repo.retryDelay != 4*time.Second {
+		t.Fatalf("expected retry delay 4s, got %s", repo.retryDelay)
+	}
+	if len(repo.deadLettered) != 1 || repo.deadLettered[0] != 3 {
+		t.Fatalf("expected delivery 3 to be dead lettered, got %v", repo.deadLettered)
+	}
+}
+
+func TestWebhookWorker_DeleteProcessedEvents(t *testing.T) {
+	repo := &mockWebhookRepo{deleteResp: 3}
+	worker := NewWebhookWorker(repo, &mockWebhookSender{}, &noopLogger{}, WebhookWorkerConfig{
+		OutboxRetention: 72 * time.Hour,
+	})
+
+	deleted, err := worker.DeleteProcessedEvents(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 3 {
+		t.Fatalf("expected 3 deleted events, got %d", deleted)
+	}
+	if repo.deleteOlderThan != 72*time.Hour {
+		t.Fatalf("expected retention 72h to reach the repository, got %s", repo.deleteOlderThan)
+	}
+}
+
+func TestWebhookWorker_DeleteUnprocessedEvents(t *testing.T) {
+	repo := &mockWebhookRepo{deleteResp: 2}
+	worker := NewWebhookWorker(repo, &mockWebhookSender{}, &noopLogger{}, WebhookWorkerConfig{
+		OutboxRetention: 72 * time.Hour,
+	})
+
+	deleted, err := worker.DeleteUnprocessedEvents(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected 2 deleted events, got %d", deleted)
+	}
+	if repo.deleteUnprocessedOlderThan != 72*time.Hour || repo.deleteOlderThan != 0 {
+		t.Fatalf("expected only unprocessed events older than 72h to be deleted, got %s and %s",
+			repo.deleteUnprocessedOlderThan, repo.deleteOlderThan)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 2 * time.Second},
+		{attempts: 2, want: 4 * time.Second},
+		{attempts: 3, want: 8 * time.Second},
+		{attempts: 10, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := webhookBackoff(tt.attempts, 2*time.Second, 30*time.Second)
+		if got != tt.want {
+			t.Fatalf("attempts %d: expected %s, got %s", tt.attempts, tt.want, got)
+		}
+	}
+}
+
+func TestWebhookSubscriptions_NotConfigured(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(nil, nil, nil, &noopLogger{}, &dummyMetrics{})
+
+	_, err := svc.CreateWebhookSubscription(ctx, CreateWebhookSubscriptionInput{
+		Url:        "https://hooks.example.com/prm",
+		Secret:     "s3cret",
+		EventTypes: []string{domain.EventPullRequestMerged},
+	})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("create: expected ErrNotConfigured, got %v", err)
+	}
+	if _, err := svc.ListWebhookSubscriptions(ctx); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("list: expected ErrNotConfigured, got %v", err)
+	}
+	if err := svc.DeleteWebhookSubscription(ctx, DeleteWebhookSubscriptionInput{Id: 1}); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("delete: expected ErrNotConfigured, got %v", err)
+	}
+}
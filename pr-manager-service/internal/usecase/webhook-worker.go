@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type WebhookWorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	// Lease must be longer than a single send attempt
+	Lease time.Duration
+	// how long processed outbox events are kept
+	OutboxRetention time.Duration
+}
+
+// WebhookWorker delivers outbox events to webhook subscriptions in the background
+type WebhookWorker struct {
+	repo   WebhookRepositoryInterface
+	sender WebhookSenderInterface
+	logger LoggerInterface
+	cfg    WebhookWorkerConfig
+}
+
+func NewWebhookWorker(
+	repo WebhookRepositoryInterface,
+	sender WebhookSenderInterface,
+	logger LoggerInterface,
+	cfg WebhookWorkerConfig,
+) *WebhookWorker {
+	return &WebhookWorker{
+		repo:   repo,
+		sender: sender,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (w *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				w.log().ErrorCtx(ctx, "webhook worker iteration failed", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// RunOnce fans out new outbox events and sends the deliveries that are due
+func (w *WebhookWorker) RunOnce(ctx context.Context) error {
+	fannedOut, err := w.repo.FanOutEvents(ctx, w.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	if fannedOut > 0 {
+		w.log().DebugCtx(ctx, "webhook outbox events fanned out", map[string]any{
+			"events_count": fannedOut,
+		})
+	}
+
+	deliveries, err := w.repo.ClaimDueDeliveries(ctx, w.cfg.BatchSize, w.cfg.Lease)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		w.deliver(ctx, d)
+	}
+
+	return nil
+}
+
+// DeleteProcessedEvents removes outbox events processed longer than the retention ago
+func (w *WebhookWorker) DeleteProcessedEvents(ctx context.Context) (int64, error) {
+	deleted, err := w.repo.DeleteProcessedEvents(ctx, w.cfg.OutboxRetention)
+	if err != nil {
+		w.log().ErrorCtx(ctx, "delete processed outbox events repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return 0, err
+	}
+	if deleted > 0 {
+		w.log().InfoCtx(ctx, "processed outbox events deleted", map[string]any{
+			"deleted": deleted,
+		})
+	}
+	return deleted, nil
+}
+
+// DeleteUnprocessedEvents removes outbox events older than the retention that
+// were never fanned out. It is run instead of the worker when the worker is
+// disabled, so the outbox does not grow without bound.
+func (w *WebhookWorker) DeleteUnprocessedEvents(ctx context.Context) (int64, error) {
+	deleted, err := w.repo.DeleteUnprocessedEvents(ctx, w.cfg.OutboxRetention)
+	if err != nil {
+		w.log().ErrorCtx(ctx, "delete unprocessed outbox events repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return 0, err
+	}
+	if deleted > 0 {
+		w.log().WarnCtx(ctx, "unprocessed outbox events deleted", map[string]any{
+			"deleted": deleted,
+		})
+	}
+	return deleted, nil
+}
+
+func (w *WebhookWorker) deliver(ctx context.Context, d domain.WebhookDelivery) {
+	sendErr := w.sender.Send(ctx, d)
+	if sendErr == nil {
+		if err := w.repo.CompleteDelivery(ctx, d.Id); err != nil {
+			w.log().ErrorCtx(ctx, "webhook delivery: complete repository error", map[string]any{
+				"delivery_id": d.Id,
+				"error":       err.Error(),
+			})
+			return
+		}
+		w.log().InfoCtx(ctx, "webhook delivered", map[string]any{
+			"delivery_id":     d.Id,
+			"subscription_id": d.Subscription.Id,
+			"event_id":        d.EventId,
+			"event_type":      d.EventType,
+		})
+		return
+	}
+
+	attempts := d.Attempts + 1
+
+	if attempts >= w.cfg.MaxAttempts {
+		w.log().ErrorCtx(ctx, "webhook delivery failed permanently, moved to dead letters", map[string]any{
+			"delivery_id":     d.Id,
+			"subscription_id": d.Subscription.Id,
+			"event_id":        d.EventId,
+			"attempts":        attempts,
+			"error":           sendErr.Error(),
+		})
+		if err := w.repo.DeadLetterDelivery(ctx, d.Id, attempts, sendErr.Error()); err != nil {
+			w.log().ErrorCtx(ctx, "webhook delivery: dead letter repository error", map[string]any{
+				"delivery_id": d.Id,
+				"error":       err.Error(),
+			})
+		}
+		return
+	}
+
+	delay := webhookBackoff(attempts, w.cfg.BackoffBase, w.cfg.BackoffMax)
+
+	w.log().WarnCtx(ctx, "webhook delivery failed, retry scheduled", map[string]any{
+		"delivery_id":     d.Id,
+		"subscription_id": d.Subscription.Id,
+		"event_id":        d.EventId,
+		"attempts":        attempts,
+		"retry_in":        delay.String(),
+		"error":           sendErr.Error(),
+	})
+
+	if err := w.repo.RetryDelivery(ctx, d.Id, attempts, delay, sendErr.Error()); err != nil {
+		w.log().ErrorCtx(ctx, "webhook delivery: retry repository error", map[string]any{
+			"delivery_id": d.Id,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// Exponential backoff: base, 2*base, 4*base, ... capped by max
+func webhookBackoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
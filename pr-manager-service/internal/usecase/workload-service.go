@@ -0,0 +1,32 @@
+package usecase
+
+import "context"
+
+// RefreshWorkloadMetrics reads the review load and replaces the workload
+// gauges with it, it is called periodically
+func (s *Service) RefreshWorkloadMetrics(ctx context.Context) (err error) {
+	ctx, op := s.startBackgroundOperation(ctx, "RefreshWorkloadMetrics")
+	defer func() { op.end(err) }()
+
+	if s.workload == nil {
+		return ErrNotConfigured
+	}
+
+	w, err := s.workload.GetWorkload(ctx, s.mergedWindow)
+	if err != nil {
+		s.log().ErrorCtx(ctx, "refresh workload metrics repository error", map[string]any{
+			"error": err.Error(),
+		})
+		return err
+	}
+
+	s.metrics.SetWorkload(w)
+
+	s.log().DebugCtx(ctx, "workload metrics refreshed", map[string]any{
+		"reviewers":              len(w.OpenAssignmentsByUser),
+		"teams":                  len(w.OpenAssignmentsByTeam),
+		"open_pull_requests":     len(w.OpenPullRequestAges),
+		"unfilled_pull_requests": w.UnfilledPullRequests,
+	})
+	return nil
+}
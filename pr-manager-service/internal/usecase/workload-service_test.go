@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pr-manager-service/internal/domain"
+)
+
+type mockWorkloadRepo struct {
+	workload     *domain.Workload
+	err          error
+	mergedWindow time.Duration
+}
+
+func (m *mockWorkloadRepo) GetWorkload(ctx context.Context, mergedWindow time.Duration) (*domain.Workload, error) {
+	m.mergedWindow = mergedWindow
+	return m.workload, m.err
+}
+
+func TestRefreshWorkloadMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not configured", func(t *testing.T) {
+		svc := NewService(nil, nil, nil, &noopLogger{}, &metricsMock{})
+		if err := svc.RefreshWorkloadMetrics(ctx); !errors.Is(err, ErrNotConfigured) {
+			t.Fatalf("expected ErrNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("sets the snapshot", func(t *testing.T) {
+		w := &domain.Workload{
+			OpenAssignmentsByUser: map[string]int{"u1": 2},
+			OpenAssignmentsByTeam: map[string]int{"backend": 2},
+			UnfilledPullRequests:  1,
+		}
+		repo := &mockWorkloadRepo{workload: w}
+		metrics := &metricsMock{}
+		svc := NewService(nil, nil, nil, &noopLogger{}, metrics, WithWorkload(repo, 24*time.Hour))
+
+		if err := svc.RefreshWorkloadMetrics(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if metrics.workload != w {
+			t.Fatalf("expected the workload to be set, got %+v", metrics.workload)
+		}
+		if repo.mergedWindow != 24*time.Hour {
+			t.Fatalf("expected the merged window to be passed, got %v", repo.mergedWindow)
+		}
+		if len(metrics.operations) != 0 {
+			t.Fatalf("expected the refresh not to be counted as a business operation, got %v", metrics.operations)
+		}
+	})
+
+	t.Run("repository error keeps the gauges", func(t *testing.T) {
+		repo := &mockWorkloadRepo{err: errors.New("db error")}
+		metrics := &metricsMock{}
+		svc := NewService(nil, nil, nil, &noopLogger{}, metrics, WithWorkload(repo, time.Hour))
+
+		if err := svc.RefreshWorkloadMetrics(ctx); err == nil {
+			t.Fatalf("expected an error")
+		}
+		if metrics.workload != nil {
+			t.Fatalf("expected no workload on error, got %+v", metrics.workload)
+		}
+		if len(metrics.operations) != 0 {
+			t.Fatalf("expected the refresh not to be counted as a business operation, got %v", metrics.operations)
+		}
+	})
+}
@@ -1,3 +1,7 @@
+DROP TABLE IF EXISTS reviewer_assignments;
+
+DROP TABLE IF EXISTS memberships;
+
 DROP TABLE IF EXISTS pull_requests;
 
 DROP TABLE IF EXISTS users;
@@ -5,7 +9,3 @@ DROP TABLE IF EXISTS users;
 DROP TABLE IF EXISTS teams;
 
 DROP TABLE IF EXISTS statuses;
-
-DROP TABLE IF EXISTS memberships;
-
-DROP TABLE IF EXISTS reviewer_assignments;
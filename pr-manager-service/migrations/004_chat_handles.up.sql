@@ -0,0 +1,9 @@
+-- chat handles for direct notifications
+
+CREATE TABLE user_chat_handles (
+    user_id TEXT PRIMARY KEY NOT NULL REFERENCES users(user_id),
+    provider TEXT NOT NULL,
+    handle TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,10 @@
+-- email addresses and digest preferences
+
+CREATE TABLE user_email_subscriptions (
+    user_id TEXT PRIMARY KEY NOT NULL REFERENCES users(user_id),
+    email TEXT NOT NULL,
+    digest_enabled BOOLEAN NOT NULL DEFAULT true,
+    unsubscribe_token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
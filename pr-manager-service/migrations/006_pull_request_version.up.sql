@@ -0,0 +1,3 @@
+-- optimistic concurrency: bumped on every change of a pull request
+
+ALTER TABLE pull_requests ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+-- token buckets of RATE_LIMIT_BACKEND=postgres, shared between replicas
+
+CREATE TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    -- the bucket is full again from then on and can be deleted
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON rate_limit_buckets (expires_at);
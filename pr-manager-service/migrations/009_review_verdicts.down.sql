@@ -0,0 +1 @@
+ALTER TABLE reviewer_assignments DROP COLUMN IF EXISTS first_verdict_at;
@@ -0,0 +1,4 @@
+-- first approval or change request of every assignment, created_at of the
+-- assignment is when the reviewer got it
+
+ALTER TABLE reviewer_assignments ADD COLUMN first_verdict_at TIMESTAMP;
@@ -0,0 +1,9 @@
+// Package migrations embeds the Postgres schema migrations, so the service
+// binary can apply them on startup. Files are named
+// NNN_name.up.sql and NNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS